@@ -16,15 +16,25 @@ package command
 
 import (
 	"flag"
+
 	"github.com/spf13/cobra"
+	"k8s.io/klog"
 )
 
 // NewCommand creates the `maya-apiserver` command and its nested children.
 func NewCommand() *cobra.Command {
+	// klog's flags have the same names as glog's flags & are hence set from
+	// glog's flags rather than registered with the command
+	klogFlags := flag.NewFlagSet("klog", flag.ExitOnError)
+	klog.InitFlags(klogFlags)
+
 	cmd := &cobra.Command{
 		Use:   "maya-apiserver",
 		Short: "CLI for managing maya-apiserver",
 		Long:  `CLI for managing maya-apiserver`,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			syncKlogFlags(klogFlags)
+		},
 	}
 
 	cmd.AddCommand(
@@ -32,8 +42,21 @@ func NewCommand() *cobra.Command {
 		NewCmdStart(),
 	)
 
+	cmd.PersistentFlags().AddGoFlagSet(flag.CommandLine)
+
 	// fix glog parse error
 	flag.CommandLine.Parse([]string{})
 
 	return cmd
 }
+
+// syncKlogFlags sets the provided klog flags to the values of the glog flags
+// with the same names e.g. -v. Packages that log via klog are configured the
+// same way as the packages that log via glog.
+func syncKlogFlags(klogFlags *flag.FlagSet) {
+	klogFlags.VisitAll(func(f *flag.Flag) {
+		if glogFlag := flag.CommandLine.Lookup(f.Name); glogFlag != nil {
+			f.Value.Set(glogFlag.Value.String())
+		}
+	})
+}
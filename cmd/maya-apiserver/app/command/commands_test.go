@@ -1,7 +1,10 @@
 package command
 
 import (
+	"flag"
 	"testing"
+
+	"k8s.io/klog"
 )
 
 func TestNewCommand(t *testing.T) {
@@ -25,3 +28,38 @@ func TestNewCommand(t *testing.T) {
 	}
 
 }
+
+func TestSyncKlogFlags(t *testing.T) {
+	klogFlags := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(klogFlags)
+
+	verbosity := flag.CommandLine.Lookup("v").Value.String()
+	defer func() {
+		flag.CommandLine.Set("v", verbosity)
+		syncKlogFlags(klogFlags)
+	}()
+
+	flag.CommandLine.Set("v", "4")
+	syncKlogFlags(klogFlags)
+	if !klog.V(4) {
+		t.Errorf("ExpectedKlogVerbosity: '4' ActualKlogVerbosity: '%s'", klogFlags.Lookup("v").Value)
+	}
+}
+
+func TestNewCommandKlogVerbosity(t *testing.T) {
+	verbosity := flag.CommandLine.Lookup("v").Value.String()
+	defer func() {
+		cmd := NewCommand()
+		cmd.SetArgs([]string{"version", "-v=" + verbosity})
+		cmd.Execute()
+	}()
+
+	cmd := NewCommand()
+	cmd.SetArgs([]string{"version", "-v=4"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("ExpectedError: 'nil' ActualError: '%s'", err)
+	}
+	if !klog.V(4) {
+		t.Errorf("ExpectedKlogVerbosity: '4' ActualKlogVerbosity: 'below 4'")
+	}
+}
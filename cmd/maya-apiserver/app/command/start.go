@@ -33,11 +33,13 @@ import (
 	"github.com/openebs/maya/cmd/maya-apiserver/app/config"
 	"github.com/openebs/maya/cmd/maya-apiserver/app/server"
 	install "github.com/openebs/maya/pkg/install/v1alpha1"
+	"github.com/openebs/maya/pkg/task"
 	"github.com/openebs/maya/pkg/util"
 	"github.com/openebs/maya/pkg/version"
 
 	"github.com/openebs/maya/cmd/maya-apiserver/spc-watcher"
 	"github.com/spf13/cobra"
+	"k8s.io/klog"
 )
 
 var (
@@ -69,6 +71,11 @@ General Options :
     Specify the verbosity level of maya api server's logs. Valid values include
     DEBUG, INFO, and WARN, in decreasing order of verbosity. The
     default is INFO.
+
+  -log-task-values-diff
+    Log the template values that each run task adds, removes or modifies.
+    The difference is logged only if -v is 4 or above. This is disabled
+    by default since it has a performance cost.
  `
 )
 
@@ -86,6 +93,10 @@ type CmdStartOptions struct {
 	ShutdownCh <-chan struct{}
 	args       []string
 
+	// LogTaskValuesDiff flags if the difference in template values due to
+	// each run task is logged
+	LogTaskValuesDiff bool
+
 	// TODO
 	// Check if both maya & httpServer instances are required ?
 	// Can httpServer or maya embed one of the other ?
@@ -116,6 +127,9 @@ func NewCmdStart() *cobra.Command {
 	cmd.Flags().StringVarP(&options.ConfigPath, "config", "", options.ConfigPath,
 		"Path to a single config file or directory.")
 
+	cmd.Flags().BoolVarP(&options.LogTaskValuesDiff, "log-task-values-diff", "", options.LogTaskValuesDiff,
+		"Log the template values changed by each run task; requires -v=4 or above.")
+
 	return cmd
 }
 
@@ -135,6 +149,10 @@ func Run(cmd *cobra.Command, c *CmdStartOptions) error {
 		}
 	}()
 	//TODO Setup Log Level
+	task.SetLogValuesDiff(c.LogTaskValuesDiff)
+	if c.LogTaskValuesDiff && !bool(klog.V(4)) {
+		glog.Warningf("Template values diff of run tasks will not be logged: -v needs to be 4 or above")
+	}
 
 	// Setup Maya server
 	if err := c.setupMayaServer(mconfig); err != nil {
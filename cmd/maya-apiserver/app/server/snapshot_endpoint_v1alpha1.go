@@ -73,7 +73,7 @@ func (sOps *snapshotAPIOps) list(volName, namespace, casType string) (interface{
 		return nil, CodedError(400, err.Error())
 	}
 
-	snaps, err := snapOps.List()
+	snaps, err := snapOps.List(sOps.req.Context())
 	if err != nil {
 		glog.Errorf("Failed to list snapshots: error '%s'", err.Error())
 		return nil, CodedError(500, err.Error())
@@ -123,7 +123,7 @@ func (sOps *snapshotAPIOps) create() (interface{}, error) {
 
 	glog.Infof("Creating %s volume %q snapshot", snap.Spec.CasType, snap.Spec.VolumeName)
 
-	snap, err = snapOps.Create()
+	snap, err = snapOps.Create(sOps.req.Context())
 	if err != nil {
 		glog.Errorf("Failed to create snapshot: error '%s'", err.Error())
 		return nil, CodedError(500, err.Error())
@@ -165,7 +165,7 @@ func (sOps *snapshotAPIOps) get(snapName, volName, namespace, casType string) (i
 	}
 
 	glog.Infof("Getting %s volume %q snapshot %q", casType, volName, snapName)
-	snap, err := snapOps.Read()
+	snap, err := snapOps.Read(sOps.req.Context())
 	if err != nil {
 		glog.Errorf("Failed to get snapshot: error '%s'", err.Error())
 		return nil, CodedError(500, err.Error())
@@ -203,7 +203,7 @@ func (sOps *snapshotAPIOps) delete(snapName, volName, namespace, casType string)
 	}
 
 	glog.Infof("Deleting snapshot %q of %s volume %q", snapName, casType, volName)
-	output, err := snapOps.Delete()
+	output, err := snapOps.Delete(sOps.req.Context())
 	if err != nil {
 		glog.Errorf("Failed to delete snapshot %q for volume %q: %s", snapName, volName, err)
 		return nil, err
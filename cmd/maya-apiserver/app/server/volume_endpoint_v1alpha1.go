@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -9,15 +10,10 @@ import (
 	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
 	"github.com/openebs/maya/pkg/template"
 	"github.com/openebs/maya/pkg/volume"
-	"k8s.io/apimachinery/pkg/api/errors"
 )
 
 func isNotFound(err error) bool {
-	if _, ok := err.(*template.NotFoundError); ok {
-		return ok
-	}
-
-	return errors.IsNotFound(err)
+	return template.IsNotFound(err)
 }
 
 type volumeAPIOpsV1alpha1 struct {
@@ -25,6 +21,15 @@ type volumeAPIOpsV1alpha1 struct {
 	resp http.ResponseWriter
 }
 
+// reqContext returns the context of the http request. Volume operations are
+// cancelled once this context is done e.g. when the client disconnects.
+func (v *volumeAPIOpsV1alpha1) reqContext() context.Context {
+	if v.req == nil {
+		return context.Background()
+	}
+	return v.req.Context()
+}
+
 // volumeV1alpha1SpecificRequest is a http handler to handle HTTP
 // requests to a OpenEBS volume.
 func (s *HTTPServer) volumeV1alpha1SpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -103,7 +108,7 @@ func (v *volumeAPIOpsV1alpha1) create() (*v1alpha1.CASVolume, error) {
 		return nil, CodedError(400, err.Error())
 	}
 
-	cvol, err := vOps.Create()
+	cvol, err := vOps.Create(v.reqContext())
 	if err != nil {
 		glog.Errorf("failed to create cas template based volume: error '%s'", err.Error())
 		return nil, CodedError(500, err.Error())
@@ -150,7 +155,7 @@ func (v *volumeAPIOpsV1alpha1) read(volumeName string) (*v1alpha1.CASVolume, err
 		return nil, CodedError(400, err.Error())
 	}
 
-	cvol, err := vOps.Read()
+	cvol, err := vOps.Read(v.reqContext())
 	if err != nil {
 		glog.Errorf("failed to read cas template based volume: error '%s'", err.Error())
 		if isNotFound(err) {
@@ -193,7 +198,7 @@ func (v *volumeAPIOpsV1alpha1) delete(volumeName string) (*v1alpha1.CASVolume, e
 		return nil, CodedError(400, err.Error())
 	}
 
-	cvol, err := vOps.Delete()
+	cvol, err := vOps.Delete(v.reqContext())
 	if err != nil {
 		glog.Errorf("failed to delete cas template based volume: error '%s'", err.Error())
 		if isNotFound(err) {
@@ -229,7 +234,7 @@ func (v *volumeAPIOpsV1alpha1) list() (*v1alpha1.CASVolumeList, error) {
 		return nil, CodedError(400, err.Error())
 	}
 
-	cvols, err := vOps.List()
+	cvols, err := vOps.List(v.reqContext())
 	if err != nil {
 		glog.Errorf("failed to list cas template based volumes at namespaces '%s': error '%s'", vols.Namespace, err.Error())
 		return nil, CodedError(500, err.Error())
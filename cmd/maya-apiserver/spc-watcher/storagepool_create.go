@@ -17,6 +17,7 @@ limitations under the License.
 package spc
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/golang/glog"
@@ -103,7 +104,9 @@ func poolCreateWorker(pool *apis.CasPool) error {
 		return fmt.Errorf("NewCasPoolOperation failed error '%s'", err.Error())
 
 	}
-	_, err = storagepoolOps.Create()
+	// there is no request to derive a context from since the storagepool is
+	// created as a result of a watch event
+	_, err = storagepoolOps.Create(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to create cas template based storagepool: error '%s'", err.Error())
 
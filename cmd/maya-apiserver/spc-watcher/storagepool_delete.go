@@ -17,6 +17,7 @@ limitations under the License.
 package spc
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/golang/glog"
@@ -45,7 +46,9 @@ func DeleteStoragePool(spcGot *v1alpha1.StoragePoolClaim) error {
 	if err != nil {
 		return fmt.Errorf("NewCasPoolOperation failed error '%s'", err.Error())
 	}
-	_, err = storagepoolOps.Delete()
+	// there is no request to derive a context from since the storagepool is
+	// deleted as a result of a watch event
+	_, err = storagepoolOps.Delete(context.Background())
 	if err != nil {
 		return fmt.Errorf("Failed to delete cas template based storagepool: error '%s'", err.Error())
 	}
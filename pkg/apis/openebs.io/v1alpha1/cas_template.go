@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -51,6 +52,12 @@ type CASTemplateSpec struct {
 	// Fallback is the CASTemplate to fallback to in-case of specific failures
 	// e.g. VersionMismatchError, etc
 	Fallback string `json:"fallback"`
+	// MinVersion is the minimum version supported by this CASTemplate; is
+	// optional
+	MinVersion string `json:"minVersion,omitempty"`
+	// MaxVersion is the maximum version supported by this CASTemplate; is
+	// optional
+	MaxVersion string `json:"maxVersion,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -111,6 +118,88 @@ type RunTaskSpec struct {
 	// against the result of this task's execution. In other words, this
 	// is run post the task execution.
 	PostRun string `json:"post"`
+	// TimeoutSeconds is the maximum duration in seconds this task is allowed
+	// to execute. Execution of this task is considered as a failure if it
+	// does not complete within this duration. This is optional & there is no
+	// timeout if not set.
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+	// RetryPolicy determines the re-execution of this task if its execution
+	// resulted in a transient error. This is optional & there are no retries
+	// if not set.
+	RetryPolicy RetryPolicy `json:"retryPolicy,omitempty"`
+	// WatchCondition is a go template that is evaluated against each object
+	// observed by a watch based task. The watch completes when this evaluates
+	// to a truthy value e.g. "true". The observed object is available at
+	// .JsonResult while evaluating this condition.
+	//
+	// A sample watch condition:
+	//
+	// {{- jsonpath .JsonResult "{.status.phase}" | eq "Bound" -}}
+	WatchCondition string `json:"watchCondition,omitempty"`
+	// Outputs are the named results that are extracted from the result of
+	// this task's execution. This is optional & is in addition to the
+	// results saved by the post run templates.
+	Outputs []OutputSpec `json:"outputs,omitempty"`
+	// SecretRef refers to a key of a K8s Secret whose value is made
+	// available to this task's templates. This is optional.
+	//
+	// NOTE:
+	//  The value is accessed as {{ .Secret }}
+	SecretRef *SecretRef `json:"secretRef,omitempty"`
+	// ContentType is the media type the output of this task is rendered as
+	// e.g. application/json. This is applicable to output tasks only & is
+	// optional. If not set, the media type is negotiated from the template
+	// values set at .Accept & the output is returned as is if this
+	// negotiation does not succeed.
+	ContentType string `json:"contentType,omitempty"`
+	// MaxCapacity is the maximum capacity this task is allowed to request.
+	// This task is not executed if the capacity found at CapacityPath of
+	// the template values exceeds this limit. This is optional & there is no
+	// limit if not set.
+	MaxCapacity resource.Quantity `json:"maxCapacity,omitempty"`
+	// CapacityPath is the jsonpath of the capacity requested by this task
+	// w.r.t the template values e.g. {.Volume.capacity}. This is applicable
+	// only if MaxCapacity is set & defaults to the volume's capacity.
+	CapacityPath string `json:"capacityPath,omitempty"`
+}
+
+// SecretRef refers to the value of a key in a K8s Secret
+type SecretRef struct {
+	// Name of the secret
+	Name string `json:"name"`
+	// Namespace of the secret; defaults to the namespace where openebs
+	// is installed
+	Namespace string `json:"namespace,omitempty"`
+	// Key whose value is referred to
+	Key string `json:"key"`
+}
+
+// OutputSpec is the specification to extract a named result from the result
+// of a task's execution
+//
+// NOTE:
+//  The extracted value will be accessed as
+// {{ .TaskResult.<TaskIdentity>.<Name> }}
+type OutputSpec struct {
+	// Name of the result
+	Name string `json:"name"`
+	// JSONPath is the json path expression to extract the result
+	//
+	// e.g. {.items[*].metadata.name}
+	JSONPath string `json:"jsonPath"`
+}
+
+// RetryPolicy is the policy to retry a task whose execution resulted in a
+// transient error e.g. kubernetes API server is overloaded
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times the task gets executed
+	// including its first execution
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// InitialDelayMS is the delay in milliseconds before the first retry
+	InitialDelayMS int `json:"initialDelayMS,omitempty"`
+	// BackoffFactor is the factor by which the delay gets multiplied after
+	// each retry; defaults to 2 if not set
+	BackoffFactor float64 `json:"backoffFactor,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
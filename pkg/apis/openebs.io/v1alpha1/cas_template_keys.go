@@ -92,6 +92,20 @@ const (
 	// The result of the current task's execution is stored in this top
 	// level property.
 	CurrentJSONResultTLP TopLevelProperty = "JsonResult"
+	// SecretTLP is a top level property supported by CAS template engine
+	//
+	// The value of the secret referred to by the current task is stored in
+	// this top level property.
+	//
+	// NOTE:
+	//  This value is redacted once the current task is executed
+	SecretTLP TopLevelProperty = "Secret"
+	// AcceptTLP is a top level property supported by CAS template engine
+	//
+	// The media types acceptable to the caller e.g. value of a http request's
+	// Accept header is stored in this top level property. The output of the
+	// tasks is rendered as the first of these media types that is supported.
+	AcceptTLP TopLevelProperty = "Accept"
 	// ListItemsTLP is a top level property supported by CAS template engine
 	//
 	// Results of one or more tasks' execution can be saved in this property.
@@ -239,6 +253,14 @@ const (
 	//  The corresponding value will be accessed as
 	// {{ .TaskResult.<TaskIdentity>.objectName }}
 	ObjectNameTRTP TaskResultTLPProperty = "objectName"
+	// ObjectNamesTRTP is the objectNames property of the TaskResultTLP. It
+	// is the list of names of the objects operated by the task & is
+	// preferred over the comma separated names set at ObjectNameTRTP.
+	//
+	// NOTE:
+	//  The corresponding value will be accessed as
+	// {{ .TaskResult.<TaskIdentity>.objectNames }}
+	ObjectNamesTRTP TaskResultTLPProperty = "objectNames"
 	// AnnotationsTRTP is the annotations property of the
 	// TaskResultTLP
 	//
@@ -273,6 +295,52 @@ const (
 	//  The corresponding value will be accessed as
 	// {{ .TaskResult.<TaskIdentity>.versionMismatchErr }}
 	TaskResultVersionMismatchErrTRTP TaskResultTLPProperty = "versionMismatchErr"
+	// TaskResultExecuteErrTRTP is a property of TaskResultTLP
+	//
+	// Error that resulted in failure of the task's execution is stored in this
+	// property. This is set only in the values provided to post task run
+	// callbacks.
+	//
+	// NOTE:
+	//  The corresponding value will be accessed as
+	// {{ .TaskResult.<TaskIdentity>.executeErr }}
+	TaskResultExecuteErrTRTP TaskResultTLPProperty = "executeErr"
+	// TaskResultTimeoutErrTRTP is a property of TaskResultTLP
+	//
+	// Error due to the task's execution exceeding its timeout is stored in this
+	// property.
+	//
+	// NOTE:
+	//  The corresponding value will be accessed as
+	// {{ .TaskResult.<TaskIdentity>.timeoutErr }}
+	TaskResultTimeoutErrTRTP TaskResultTLPProperty = "timeoutErr"
+	// TaskResultWatchResultTRTP is a property of TaskResultTLP
+	//
+	// Object observed by a watch based task when its watch condition was met
+	// is stored in this property
+	//
+	// NOTE:
+	//  The corresponding value will be accessed as
+	// {{ .TaskResult.<TaskIdentity>.watchResult }}
+	TaskResultWatchResultTRTP TaskResultTLPProperty = "watchResult"
+	// ResourceVersionTRTP is a property of TaskResultTLP
+	//
+	// Resource version of the object patched by a patch-cas task is stored
+	// in this property
+	//
+	// NOTE:
+	//  The corresponding value will be accessed as
+	// {{ .TaskResult.<TaskIdentity>.resourceVersion }}
+	ResourceVersionTRTP TaskResultTLPProperty = "resourceVersion"
+	// TaskResultIgnoredErrTRTP is a property of TaskResultTLP
+	//
+	// Error of a task that was ignored since its class is listed in the
+	// task's ignoreErrors is stored in this property
+	//
+	// NOTE:
+	//  The corresponding value will be accessed as
+	// {{ .TaskResult.<TaskIdentity>.ignoredErr }}
+	TaskResultIgnoredErrTRTP TaskResultTLPProperty = "ignoredErr"
 )
 
 // ListItemsTLPProperty is the name of the property that is found
@@ -33,7 +33,9 @@ import (
 	api_storage_v1 "k8s.io/api/storage/v1"
 
 	mach_apis_meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 
 	//typed_oe_v1alpha1 "github.com/openebs/maya/pkg/client/clientset/versioned/typed/openebs/v1alpha1"
 	typed_oe_v1alpha1 "github.com/openebs/maya/pkg/client/generated/clientset/internalclientset/typed/openebs.io/v1alpha1"
@@ -130,6 +132,10 @@ type K8sClient struct {
 	// NOTE: This property enables unit testing
 	ConfigMap *api_core_v1.ConfigMap
 
+	// Secret refers to a K8s Secret object
+	// NOTE: This property enables unit testing
+	Secret *api_core_v1.Secret
+
 	// Deployment refers to a K8s Deployment object
 	// NOTE: This property enables unit testing
 	Deployment *api_extn_v1beta1.Deployment
@@ -447,6 +453,22 @@ func (k *K8sClient) GetConfigMap(name string, opts mach_apis_meta_v1.GetOptions)
 	return cops.Get(name, opts)
 }
 
+// secretOps is a utility function that provides a instance capable of
+// executing various K8s Secret related operations.
+func (k *K8sClient) secretOps() typed_core_v1.SecretInterface {
+	return k.cs.CoreV1().Secrets(k.ns)
+}
+
+// GetSecret fetches the K8s Secret with the provided name
+func (k *K8sClient) GetSecret(name string, opts mach_apis_meta_v1.GetOptions) (*api_core_v1.Secret, error) {
+	if k.Secret != nil {
+		return k.Secret, nil
+	}
+
+	sops := k.secretOps()
+	return sops.Get(name, opts)
+}
+
 // coreV1PVCOps is a utility function that provides a instance capable of
 // executing various K8s PVC related operations.
 func (k *K8sClient) coreV1PVCOps() typed_core_v1.PersistentVolumeClaimInterface {
@@ -492,6 +514,29 @@ func (k *K8sClient) GetCoreV1PersistentVolumeAsRaw(name string) (result []byte,
 	return
 }
 
+// nameListOptions returns the list options that select the object with the
+// provided name
+func nameListOptions(name string) mach_apis_meta_v1.ListOptions {
+	return mach_apis_meta_v1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	}
+}
+
+// WatchCoreV1PVC watches the K8s PVC with the provided name
+func (k *K8sClient) WatchCoreV1PVC(name string) (watch.Interface, error) {
+	return k.coreV1PVCOps().Watch(nameListOptions(name))
+}
+
+// WatchCoreV1PV watches the K8s PersistentVolume with the provided name
+func (k *K8sClient) WatchCoreV1PV(name string) (watch.Interface, error) {
+	return k.coreV1PVOps().Watch(nameListOptions(name))
+}
+
+// WatchCoreV1Pod watches the K8s Pod with the provided name
+func (k *K8sClient) WatchCoreV1Pod(name string) (watch.Interface, error) {
+	return k.podOps().Watch(nameListOptions(name))
+}
+
 // GetCoreV1PVCAsRaw fetches the K8s PVC with the provided name
 func (k *K8sClient) GetCoreV1PVCAsRaw(name string) (result []byte, err error) {
 	result, err = k.cs.CoreV1().RESTClient().
@@ -606,6 +651,50 @@ func (k *K8sClient) GetOEV1alpha1SPAsRaw(name string) (result []byte, err error)
 	//return
 }
 
+// GetOEV1alpha1CSPAsRaw fetches the OpenEBS CStorPool with the provided name
+func (k *K8sClient) GetOEV1alpha1CSPAsRaw(name string) (result []byte, err error) {
+	csp, err := k.GetOEV1alpha1CSP(name)
+	if err != nil {
+		return
+	}
+
+	return json.Marshal(csp)
+}
+
+// GetOEV1alpha1CVAsRaw fetches the OpenEBS CStorVolume with the provided name
+func (k *K8sClient) GetOEV1alpha1CVAsRaw(name string) (result []byte, err error) {
+	cv, err := k.GetOEV1alpha1CV(name)
+	if err != nil {
+		return
+	}
+
+	return json.Marshal(cv)
+}
+
+// GetOEV1alpha1CVRAsRaw fetches the OpenEBS CStorVolumeReplica with the
+// provided name
+func (k *K8sClient) GetOEV1alpha1CVRAsRaw(name string) (result []byte, err error) {
+	cvr, err := k.GetOEV1alpha1CVR(name)
+	if err != nil {
+		return
+	}
+
+	return json.Marshal(cvr)
+}
+
+// GetCoreV1ServiceAsRaw fetches the K8s Service with the provided name
+func (k *K8sClient) GetCoreV1ServiceAsRaw(name string) (result []byte, err error) {
+	result, err = k.cs.CoreV1().RESTClient().
+		Get().
+		Namespace(k.ns).
+		Resource("services").
+		Name(name).
+		VersionedParams(&mach_apis_meta_v1.GetOptions{}, scheme.ParameterCodec).
+		DoRaw()
+
+	return
+}
+
 // podOps is a utility function that provides a instance capable of
 // executing various K8s pod related operations.
 func (k *K8sClient) podOps() typed_core_v1.PodInterface {
@@ -17,32 +17,34 @@ limitations under the License.
 package engine
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
 	"github.com/openebs/maya/pkg/task"
 	"github.com/openebs/maya/pkg/util"
+	"github.com/openebs/maya/pkg/version"
 )
 
 // CASCreator exposes method to create a cas entity
 type CASCreator interface {
-	Create() (output []byte, err error)
+	Create(ctx context.Context) (output []byte, err error)
 }
 
 // CASDeleter exposes method to delete a cas entity
 type CASDeleter interface {
-	Delete() (output []byte, err error)
+	Delete(ctx context.Context) (output []byte, err error)
 }
 
 // CASLister exposes method to list one or more cas entities
 type CASLister interface {
-	List() (output []byte, err error)
+	List(ctx context.Context) (output []byte, err error)
 }
 
 // CASReader exposes method to fetch details of a cas entity
 type CASReader interface {
-	Read() (output []byte, err error)
+	Read(ctx context.Context) (output []byte, err error)
 }
 
 // CASEngine supports various operations w.r.t a CAS entity
@@ -249,11 +251,22 @@ func (c *CASEngine) prepareOutputTask() (err error) {
 // mismatch error
 func (c *CASEngine) prepareFallback() {
 	f := c.casTemplate.Spec.Fallback
-	c.taskGroupRunner.SetFallback(f)
+	c.taskGroupRunner.SetFallback([]string{f})
+	c.taskGroupRunner.SetVersion(version.GetVersion())
+	c.taskGroupRunner.SetVersionRange(c.casTemplate.Spec.MinVersion, c.casTemplate.Spec.MaxVersion)
 }
 
-// Run executes the cas engine based on the tasks set in the cas template
-func (c *CASEngine) Run() (output []byte, err error) {
+// SetAccept sets the media types acceptable to the caller e.g. value of a
+// http request's Accept header. Output is rendered as the first of these
+// media types that is supported.
+func (c *CASEngine) SetAccept(accept string) {
+	c.templateValues[string(v1alpha1.AcceptTLP)] = accept
+}
+
+// Run executes the cas engine based on the tasks set in the cas template.
+// The tasks are aborted once the provided context is done e.g. when the
+// caller's request is cancelled.
+func (c *CASEngine) Run(ctx context.Context) (output []byte, err error) {
 	// Set default config if config tlp is not set
 	if c.templateValues[string(v1alpha1.ConfigTLP)] == nil {
 		err = c.AddConfigToConfigTLP(c.casTemplate.Spec.Defaults)
@@ -273,25 +286,25 @@ func (c *CASEngine) Run() (output []byte, err error) {
 
 	c.prepareFallback()
 
-	return c.taskGroupRunner.Run(c.templateValues)
+	return c.taskGroupRunner.Run(ctx, c.templateValues)
 }
 
 // Create a CAS entity
-func (c *CASEngine) Create() (output []byte, err error) {
-	return c.Run()
+func (c *CASEngine) Create(ctx context.Context) (output []byte, err error) {
+	return c.Run(ctx)
 }
 
 // Read the details of a CAS entity
-func (c *CASEngine) Read() (output []byte, err error) {
-	return c.Run()
+func (c *CASEngine) Read(ctx context.Context) (output []byte, err error) {
+	return c.Run(ctx)
 }
 
 // Delete a CAS entity
-func (c *CASEngine) Delete() (output []byte, err error) {
-	return c.Run()
+func (c *CASEngine) Delete(ctx context.Context) (output []byte, err error) {
+	return c.Run(ctx)
 }
 
 // List the details of one or more CAS entities
-func (c *CASEngine) List() (output []byte, err error) {
-	return c.Run()
+func (c *CASEngine) List(ctx context.Context) (output []byte, err error) {
+	return c.Run(ctx)
 }
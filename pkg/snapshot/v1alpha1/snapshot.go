@@ -17,6 +17,7 @@ limitations under the License.
 package snapshot
 
 import (
+	"context"
 	"strings"
 
 	yaml "github.com/ghodss/yaml"
@@ -66,7 +67,7 @@ func Snapshot(opts *v1alpha1.SnapshotOptions) (*snapshot, error) {
 }
 
 // Create creates an OpenEBS snapshot of a volume
-func (s *snapshot) Create() (*v1alpha1.CASSnapshot, error) {
+func (s *snapshot) Create(ctx context.Context) (*v1alpha1.CASSnapshot, error) {
 	if s.k8sClient == nil {
 		return nil, errors.Errorf("unable to create snapshot: nil k8s client")
 	}
@@ -116,7 +117,7 @@ func (s *snapshot) Create() (*v1alpha1.CASSnapshot, error) {
 	}
 
 	// create the snapshot
-	data, err := cc.Create()
+	data, err := cc.Create(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -132,7 +133,7 @@ func (s *snapshot) Create() (*v1alpha1.CASSnapshot, error) {
 }
 
 // Get the openebs snapshot details
-func (s *snapshot) Read() (*v1alpha1.CASSnapshot, error) {
+func (s *snapshot) Read(ctx context.Context) (*v1alpha1.CASSnapshot, error) {
 	if s.k8sClient == nil {
 		return nil, errors.Errorf("unable to read snapshot: nil k8s client")
 	}
@@ -182,7 +183,7 @@ func (s *snapshot) Read() (*v1alpha1.CASSnapshot, error) {
 	}
 
 	// read the cas snapshot
-	data, err := engine.Read()
+	data, err := engine.Read(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -196,7 +197,7 @@ func (s *snapshot) Read() (*v1alpha1.CASSnapshot, error) {
 }
 
 // Get the openebs snapshot details
-func (s *snapshot) Delete() (*v1alpha1.CASSnapshot, error) {
+func (s *snapshot) Delete(ctx context.Context) (*v1alpha1.CASSnapshot, error) {
 	if s.k8sClient == nil {
 		return nil, errors.Errorf("unable to delete snapshot: nil k8s client")
 	}
@@ -246,7 +247,7 @@ func (s *snapshot) Delete() (*v1alpha1.CASSnapshot, error) {
 	}
 
 	// read the cas snapshot
-	data, err := engine.Delete()
+	data, err := engine.Delete(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -259,7 +260,7 @@ func (s *snapshot) Delete() (*v1alpha1.CASSnapshot, error) {
 	return snap, nil
 }
 
-func (s *snapshot) List() (*v1alpha1.CASSnapshotList, error) {
+func (s *snapshot) List(ctx context.Context) (*v1alpha1.CASSnapshotList, error) {
 	if s.k8sClient == nil {
 		return nil, errors.Errorf("unable to list snapshot: nil k8s client")
 	}
@@ -307,7 +308,7 @@ func (s *snapshot) List() (*v1alpha1.CASSnapshotList, error) {
 	}
 
 	// list the cas snapshots
-	data, err := engine.List()
+	data, err := engine.List(ctx)
 	if err != nil {
 		return nil, err
 	}
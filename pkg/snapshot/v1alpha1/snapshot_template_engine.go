@@ -18,6 +18,7 @@ limitations under the License.
 package snapshot
 
 import (
+	"context"
 	"errors"
 	"strings"
 
@@ -76,7 +77,7 @@ func SnapshotEngine(
 }
 
 // Create creates a CAS snapshot
-func (c *snapshotEngine) Create() ([]byte, error) {
+func (c *snapshotEngine) Create(ctx context.Context) ([]byte, error) {
 	// set customized CAS config as a top level property
 	err := c.engine.AddConfigToConfigTLP(c.defaultConfig)
 	if err != nil {
@@ -84,5 +85,5 @@ func (c *snapshotEngine) Create() ([]byte, error) {
 	}
 
 	// delegate to generic cas template engine
-	return c.engine.Run()
+	return c.engine.Run(ctx)
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package storagepool
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/ghodss/yaml"
@@ -66,7 +67,7 @@ func NewCasPoolOperation(pool *v1alpha1.CasPool) (*casPoolOperation, error) {
 }
 
 // Create provisions an OpenEBS storagePool
-func (v *casPoolOperation) Create() (*v1alpha1.CasPool, error) {
+func (v *casPoolOperation) Create(ctx context.Context) (*v1alpha1.CasPool, error) {
 	if v.k8sClient == nil {
 		return nil, fmt.Errorf("Unable to create storagepool: nil k8s client")
 	}
@@ -101,7 +102,7 @@ func (v *casPoolOperation) Create() (*v1alpha1.CasPool, error) {
 	}
 
 	// create the storagePool
-	data, err := cc.Create()
+	data, err := cc.Create(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +116,7 @@ func (v *casPoolOperation) Create() (*v1alpha1.CasPool, error) {
 	return pool, nil
 }
 
-func (v *casPoolOperation) Delete() (*v1alpha1.CasPool, error) {
+func (v *casPoolOperation) Delete(ctx context.Context) (*v1alpha1.CasPool, error) {
 	if len(v.pool.StoragePoolClaim) == 0 {
 		return nil, fmt.Errorf("Unable to delete storagepool: storagepoolclaim name not provided")
 	}
@@ -149,7 +150,7 @@ func (v *casPoolOperation) Delete() (*v1alpha1.CasPool, error) {
 	}
 
 	// delete the CasPool
-	data, err := engine.Delete()
+	data, err := engine.Delete(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -18,6 +18,7 @@ limitations under the License.
 package storagepool
 
 import (
+	"context"
 	"fmt"
 	"github.com/ghodss/yaml"
 	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
@@ -150,7 +151,7 @@ func (c *casStoragePoolEngine) addConfigToConfigTLP() error {
 }
 
 // Create creates a storagepool
-func (c *casStoragePoolEngine) Create() ([]byte, error) {
+func (c *casStoragePoolEngine) Create(ctx context.Context) ([]byte, error) {
 	// set customized CAS config as a top level property
 	err := c.addConfigToConfigTLP()
 	if err != nil {
@@ -158,5 +159,5 @@ func (c *casStoragePoolEngine) Create() ([]byte, error) {
 	}
 
 	// delegate to generic cas template engine
-	return c.casEngine.Run()
+	return c.casEngine.Run(ctx)
 }
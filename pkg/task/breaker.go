@@ -0,0 +1,214 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+// CircuitBreaker decides if a task can be executed based on the outcome of
+// the tasks executed previously. This avoids hammering an API server that
+// keeps failing e.g. due to overload.
+type CircuitBreaker interface {
+	// Allow returns true if a task can be executed
+	Allow() bool
+	// RecordSuccess records a task that was executed successfully
+	RecordSuccess()
+	// RecordFailure records a task that failed
+	RecordFailure()
+}
+
+// CircuitState represents the state of a circuit breaker
+type CircuitState string
+
+const (
+	// CircuitClosed flags a circuit breaker that allows the tasks to execute
+	CircuitClosed CircuitState = "Closed"
+	// CircuitOpen flags a circuit breaker that does not allow the tasks to
+	// execute
+	CircuitOpen CircuitState = "Open"
+	// CircuitHalfOpen flags a circuit breaker that allows the tasks to
+	// execute on a trial basis after it was open
+	CircuitHalfOpen CircuitState = "HalfOpen"
+)
+
+// ThresholdCircuitBreaker is a circuit breaker that opens after a number of
+// consecutive failures & closes after a number of consecutive successes
+//
+// NOTE:
+//  This is an implementation of CircuitBreaker interface
+type ThresholdCircuitBreaker struct {
+	// failureThreshold is the number of consecutive failures that opens
+	// this breaker
+	failureThreshold int
+	// successThreshold is the number of consecutive successes in half open
+	// state that closes this breaker
+	successThreshold int
+	// openTimeout is the duration this breaker stays open before it turns
+	// half open
+	openTimeout time.Duration
+	// now returns the current time; defaults to time.Now
+	now func() time.Time
+
+	mutex     sync.Mutex
+	state     CircuitState
+	failures  int
+	successes int
+	openedAt  time.Time
+}
+
+// NewThresholdCircuitBreaker returns a new instance of
+// ThresholdCircuitBreaker. Thresholds less than 1 are considered as 1.
+func NewThresholdCircuitBreaker(failureThreshold, successThreshold int, openTimeout time.Duration) *ThresholdCircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	if successThreshold < 1 {
+		successThreshold = 1
+	}
+	return &ThresholdCircuitBreaker{
+		failureThreshold: failureThreshold,
+		successThreshold: successThreshold,
+		openTimeout:      openTimeout,
+		now:              time.Now,
+		state:            CircuitClosed,
+	}
+}
+
+// Allow returns true if this breaker is closed or half open. An open breaker
+// turns half open once its open timeout elapses.
+func (b *ThresholdCircuitBreaker) Allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.openTimeout {
+		b.state = CircuitHalfOpen
+		b.successes = 0
+	}
+	return b.state != CircuitOpen
+}
+
+// RecordSuccess records a successful task. A half open breaker closes once
+// its success threshold is reached.
+func (b *ThresholdCircuitBreaker) RecordSuccess() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures = 0
+	if b.state != CircuitHalfOpen {
+		return
+	}
+	b.successes++
+	if b.successes >= b.successThreshold {
+		b.state = CircuitClosed
+		b.successes = 0
+	}
+}
+
+// RecordFailure records a failed task. A closed breaker opens once its
+// failure threshold is reached while a half open breaker opens at once.
+func (b *ThresholdCircuitBreaker) RecordFailure() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.failureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+		b.failures = 0
+		b.successes = 0
+	}
+}
+
+// State returns the current state of this breaker
+func (b *ThresholdCircuitBreaker) State() CircuitState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.state
+}
+
+// WithCircuitBreaker sets the circuit breaker that is consulted before
+// executing each task of the task group runner. The run is aborted & the
+// executed tasks are rolled back if the breaker does not allow a task.
+func WithCircuitBreaker(cb CircuitBreaker) TaskGroupRunnerOption {
+	return func(m *TaskGroupRunner) {
+		m.circuitBreaker = cb
+	}
+}
+
+// CircuitBreakerOpenError represents an error due to a task not being
+// executed since the runner's circuit breaker was open
+type CircuitBreakerOpenError struct {
+	// TaskName is the name of the run task that was not executed
+	TaskName string
+}
+
+func (e *CircuitBreakerOpenError) Error() string {
+	return fmt.Sprintf("aborted runtasks: circuit breaker is open: task '%s' was not executed", e.TaskName)
+}
+
+// IsCircuitBreakerOpen flags if the provided error or any error wrapped or
+// aggregated by it is due to an open circuit breaker
+func IsCircuitBreakerOpen(err error) bool {
+	for err != nil {
+		switch e := err.(type) {
+		case *CircuitBreakerOpenError:
+			return true
+		case *multierror.Error:
+			for _, nested := range e.Errors {
+				if IsCircuitBreakerOpen(nested) {
+					return true
+				}
+			}
+			return false
+		}
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			return false
+		}
+		err = cause.Cause()
+	}
+	return false
+}
+
+// allowTask returns an error if the runner's circuit breaker if any does not
+// allow the provided task to execute
+func (m *TaskGroupRunner) allowTask(runtask *v1alpha1.RunTask) error {
+	if m.circuitBreaker == nil || m.circuitBreaker.Allow() {
+		return nil
+	}
+	return &CircuitBreakerOpenError{TaskName: runtask.Name}
+}
+
+// recordTaskOutcome records the outcome of an executed task against the
+// runner's circuit breaker if any
+func (m *TaskGroupRunner) recordTaskOutcome(err error) {
+	if m.circuitBreaker == nil {
+		return
+	}
+	if err != nil {
+		m.circuitBreaker.RecordFailure()
+		return
+	}
+	m.circuitBreaker.RecordSuccess()
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestThresholdCircuitBreaker(t *testing.T) {
+	tests := map[string]struct {
+		// outcomes are recorded in order; 's' is a success, 'f' is a failure
+		// & 'w' waits for the open timeout to elapse
+		outcomes      string
+		expectedState CircuitState
+		isAllowed     bool
+	}{
+		"circuit breaker - +ve test case - new breaker is closed": {
+			expectedState: CircuitClosed,
+			isAllowed:     true,
+		},
+		"circuit breaker - +ve test case - failures below threshold": {
+			outcomes:      "ff",
+			expectedState: CircuitClosed,
+			isAllowed:     true,
+		},
+		"circuit breaker - +ve test case - success resets failures": {
+			outcomes:      "ffsff",
+			expectedState: CircuitClosed,
+			isAllowed:     true,
+		},
+		"circuit breaker - +ve test case - consecutive failures open": {
+			outcomes:      "fff",
+			expectedState: CircuitOpen,
+		},
+		"circuit breaker - +ve test case - open turns half open after timeout": {
+			outcomes:      "fffw",
+			expectedState: CircuitHalfOpen,
+			isAllowed:     true,
+		},
+		"circuit breaker - +ve test case - half open stays till success threshold": {
+			outcomes:      "fffws",
+			expectedState: CircuitHalfOpen,
+			isAllowed:     true,
+		},
+		"circuit breaker - +ve test case - half open closes on success threshold": {
+			outcomes:      "fffwss",
+			expectedState: CircuitClosed,
+			isAllowed:     true,
+		},
+		"circuit breaker - +ve test case - half open opens on failure": {
+			outcomes:      "fffwsf",
+			expectedState: CircuitOpen,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			b := NewThresholdCircuitBreaker(3, 2, time.Minute)
+			b.now = func() time.Time { return now }
+
+			for _, outcome := range mock.outcomes {
+				switch outcome {
+				case 's':
+					b.RecordSuccess()
+				case 'f':
+					b.RecordFailure()
+				case 'w':
+					now = now.Add(time.Minute)
+					b.Allow()
+				}
+			}
+
+			if b.Allow() != mock.isAllowed {
+				t.Fatalf("failed to test circuit breaker: expected allowed '%t': actual '%t'", mock.isAllowed, !mock.isAllowed)
+			}
+			if b.State() != mock.expectedState {
+				t.Fatalf("failed to test circuit breaker: expected state '%s': actual '%s'", mock.expectedState, b.State())
+			}
+		})
+	}
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	tests := map[string]struct {
+		failing      int
+		fallbacks    []string
+		isBreakerErr bool
+	}{
+		"with circuit breaker - +ve test case - closed breaker continues on error": {
+			failing: 1,
+		},
+		"with circuit breaker - +ve test case - open breaker aborts run": {
+			failing:      2,
+			isBreakerErr: true,
+		},
+		"with circuit breaker - +ve test case - open breaker does not fall back": {
+			failing:      2,
+			fallbacks:    []string{"cast-v2"},
+			isBreakerErr: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			var tried []string
+			r := NewTaskGroupRunner(WithCircuitBreaker(NewThresholdCircuitBreaker(2, 1, time.Hour)))
+			r.fallbackFn = func(ctx context.Context, castemplate string, values map[string]interface{}) ([]byte, error) {
+				tried = append(tried, castemplate)
+				return []byte(castemplate), nil
+			}
+			r.SetFallback(mock.fallbacks)
+			// every other error results in a fallback
+			r.SetFallbackPredicate(func(err error) bool { return true })
+			r.SetErrorPolicy(ContinueOnError)
+			for idx, id := range []string{"t1", "t2"} {
+				post := ""
+				if idx < mock.failing {
+					post = `{{- fail "server busy" -}}`
+				}
+				r.AddRunTask(fakeCommandRunTask(id, post))
+			}
+			r.AddRunTask(fakeCommandRunTask("t3", ""))
+
+			result := r.RunWithReport(fakeTemplateValues())
+			if mock.isBreakerErr != IsCircuitBreakerOpen(result.Err) {
+				t.Fatalf("failed to test with circuit breaker: expected breaker error '%t': actual '%v'", mock.isBreakerErr, result.Err)
+			}
+			if mock.isBreakerErr && len(tried) != 0 {
+				t.Fatalf("failed to test with circuit breaker: expected no fallback: actual '%v'", tried)
+			}
+			executed := false
+			for _, task := range result.ExecutedTasks {
+				if task.Identity == "t3" {
+					executed = true
+				}
+			}
+			if executed == mock.isBreakerErr {
+				t.Fatalf("failed to test with circuit breaker: expected task 't3' executed '%t': actual '%t'", !mock.isBreakerErr, executed)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+// TaskGroupRunnerBuilder builds a task group runner via method chaining.
+// Errors found while building are recorded & are returned together by
+// Build.
+//
+// Example:
+//  r, err := NewTaskGroupRunnerBuilder().
+//    WithRunTask(rt1).
+//    WithRunTask(rt2).
+//    WithOutputTask(out).
+//    WithTimeout(2 * time.Minute).
+//    Build()
+type TaskGroupRunnerBuilder struct {
+	runner *TaskGroupRunner
+	// values are the template values the runner is validated against
+	values map[string]interface{}
+	// source fetches the run tasks that are added by their names
+	source TaskSpecFetcher
+	errs   *multierror.Error
+}
+
+// NewTaskGroupRunnerBuilder returns a new instance of TaskGroupRunnerBuilder
+func NewTaskGroupRunnerBuilder() *TaskGroupRunnerBuilder {
+	return &TaskGroupRunnerBuilder{runner: NewTaskGroupRunner()}
+}
+
+// WithRunTask adds the provided run task to the runner
+func (b *TaskGroupRunnerBuilder) WithRunTask(runtask *v1alpha1.RunTask) *TaskGroupRunnerBuilder {
+	err := b.runner.AddRunTask(runtask)
+	if err != nil {
+		b.errs = multierror.Append(b.errs, err)
+	}
+	return b
+}
+
+// WithOutputTask adds the provided run task to the output tasks of the
+// runner
+func (b *TaskGroupRunnerBuilder) WithOutputTask(runtask *v1alpha1.RunTask) *TaskGroupRunnerBuilder {
+	err := b.runner.AddOutputTask(runtask)
+	if err != nil {
+		b.errs = multierror.Append(b.errs, err)
+	}
+	return b
+}
+
+// WithTaskSource sets the source that fetches the run tasks added by their
+// names e.g. a FileTaskSource or a K8sTaskSpecFetcher
+func (b *TaskGroupRunnerBuilder) WithTaskSource(source TaskSpecFetcher) *TaskGroupRunnerBuilder {
+	b.source = source
+	return b
+}
+
+// WithRunTaskNamed fetches the run task having the provided name from the
+// task source & adds it to the runner
+func (b *TaskGroupRunnerBuilder) WithRunTaskNamed(name string) *TaskGroupRunnerBuilder {
+	runtask, err := b.fetch(name)
+	if err != nil {
+		b.errs = multierror.Append(b.errs, err)
+		return b
+	}
+	return b.WithRunTask(runtask)
+}
+
+// WithOutputTaskNamed fetches the run task having the provided name from the
+// task source & adds it to the output tasks of the runner
+func (b *TaskGroupRunnerBuilder) WithOutputTaskNamed(name string) *TaskGroupRunnerBuilder {
+	runtask, err := b.fetch(name)
+	if err != nil {
+		b.errs = multierror.Append(b.errs, err)
+		return b
+	}
+	return b.WithOutputTask(runtask)
+}
+
+// fetch returns the run task having the provided name from the task source
+func (b *TaskGroupRunnerBuilder) fetch(name string) (*v1alpha1.RunTask, error) {
+	if b.source == nil {
+		return nil, fmt.Errorf("failed to add runtask '%s': nil task source: set one via WithTaskSource", name)
+	}
+	return b.source.Fetch(name)
+}
+
+// WithFallback appends the provided CAS Template to the runner's chain of
+// fallbacks
+func (b *TaskGroupRunnerBuilder) WithFallback(castemplate string) *TaskGroupRunnerBuilder {
+	b.runner.AddFallback(castemplate)
+	return b
+}
+
+// WithTimeout sets the maximum duration all the tasks of the runner are
+// allowed to execute
+func (b *TaskGroupRunnerBuilder) WithTimeout(d time.Duration) *TaskGroupRunnerBuilder {
+	if d < 0 {
+		b.errs = multierror.Append(b.errs, fmt.Errorf("invalid timeout '%s': timeout can not be negative", d))
+		return b
+	}
+	WithTimeout(d)(b.runner)
+	return b
+}
+
+// WithRetryPolicy sets the policy to retry the tasks of the runner that do
+// not set their own retry policy
+func (b *TaskGroupRunnerBuilder) WithRetryPolicy(policy v1alpha1.RetryPolicy) *TaskGroupRunnerBuilder {
+	if policy.MaxAttempts < 0 || policy.InitialDelayMS < 0 || policy.BackoffFactor < 0 {
+		b.errs = multierror.Append(b.errs, fmt.Errorf("invalid retry policy '%+v': values can not be negative", policy))
+		return b
+	}
+	WithRetryPolicy(policy)(b.runner)
+	return b
+}
+
+// WithTracer sets the tracer that traces the runner's tasks, output,
+// rollback & fallback
+func (b *TaskGroupRunnerBuilder) WithTracer(tracer Tracer) *TaskGroupRunnerBuilder {
+	if tracer == nil {
+		b.errs = multierror.Append(b.errs, fmt.Errorf("invalid tracer: nil tracer"))
+		return b
+	}
+	WithRunnerTracer(tracer)(b.runner)
+	return b
+}
+
+// WithValues sets the template values the runner is validated against by
+// Build
+func (b *TaskGroupRunnerBuilder) WithValues(values map[string]interface{}) *TaskGroupRunnerBuilder {
+	b.values = values
+	return b
+}
+
+// Build returns the runner if all its specifications are valid. The errors
+// recorded while building are returned along with the errors reported by
+// the runner's Validate.
+//
+// NOTE:
+//  Runner is validated against empty template values unless these are set
+// via WithValues
+func (b *TaskGroupRunnerBuilder) Build() (*TaskGroupRunner, error) {
+	values := b.values
+	if values == nil {
+		values = map[string]interface{}{
+			string(v1alpha1.TaskResultTLP): map[string]interface{}{},
+			string(v1alpha1.ListItemsTLP):  map[string]interface{}{},
+		}
+	}
+
+	errs := b.errs
+	err := b.runner.Validate(values)
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if errs.ErrorOrNil() != nil {
+		return nil, errs
+	}
+	return b.runner, nil
+}
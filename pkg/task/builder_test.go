@@ -0,0 +1,117 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+func TestTaskGroupRunnerBuilder(t *testing.T) {
+	output := &v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{
+		Meta: "id: o1\nkind: Command\naction: output",
+		Task: `{{ nestedString .TaskResult "t1" "objectName" }}`,
+	}}
+
+	tests := map[string]struct {
+		build          func(b *TaskGroupRunnerBuilder) *TaskGroupRunnerBuilder
+		expectedErrs   []string
+		expectedOutput string
+	}{
+		"builder - +ve test case - valid runner": {
+			build: func(b *TaskGroupRunnerBuilder) *TaskGroupRunnerBuilder {
+				return b.
+					WithRunTask(fakeCommandRunTask("t1", `{{- "t1-new" | saveAs "t1.objectName" .TaskResult | noop -}}`)).
+					WithOutputTask(output).
+					WithTimeout(time.Minute).
+					WithRetryPolicy(v1alpha1.RetryPolicy{MaxAttempts: 2}).
+					WithTracer(&fakeTracer{})
+			},
+			expectedOutput: "t1-new",
+		},
+		"builder - -ve test case - no runtasks": {
+			build: func(b *TaskGroupRunnerBuilder) *TaskGroupRunnerBuilder {
+				return b.WithOutputTask(output)
+			},
+			expectedErrs: []string{"no runtasks found"},
+		},
+		"builder - -ve test case - all errors are aggregated": {
+			build: func(b *TaskGroupRunnerBuilder) *TaskGroupRunnerBuilder {
+				return b.
+					WithRunTask(nil).
+					WithRunTask(fakeCommandRunTask("t1", "")).
+					WithRunTask(fakeCommandRunTask("t1", "")).
+					WithTimeout(-time.Second).
+					WithRetryPolicy(v1alpha1.RetryPolicy{MaxAttempts: -1}).
+					WithTracer(nil)
+			},
+			expectedErrs: []string{"nil runtask", "duplicate id 't1'", "invalid timeout", "invalid retry policy", "invalid tracer"},
+		},
+		"builder - -ve test case - unresolved fallback": {
+			build: func(b *TaskGroupRunnerBuilder) *TaskGroupRunnerBuilder {
+				b.runner.resolveFallbackFn = func(castemplate string) error {
+					return fmt.Errorf("castemplate '%s' not found", castemplate)
+				}
+				return b.
+					WithRunTask(fakeCommandRunTask("t1", "")).
+					WithFallback("cast-v2")
+			},
+			expectedErrs: []string{"invalid fallback 'cast-v2'"},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := mock.build(NewTaskGroupRunnerBuilder()).Build()
+			if len(mock.expectedErrs) != 0 {
+				if err == nil || r != nil {
+					t.Fatalf("failed to test builder: expected errors '%v': actual 'no error'", mock.expectedErrs)
+				}
+				merr, ok := err.(*multierror.Error)
+				if !ok {
+					t.Fatalf("failed to test builder: expected aggregated error: actual '%T'", err)
+				}
+				for _, expected := range mock.expectedErrs {
+					if !strings.Contains(err.Error(), expected) {
+						t.Fatalf("failed to test builder: expected error '%s': actual '%s'", expected, err)
+					}
+				}
+				if len(merr.Errors) < len(mock.expectedErrs) {
+					t.Fatalf("failed to test builder: expected '%d' errors: actual '%d'", len(mock.expectedErrs), len(merr.Errors))
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("failed to test builder: expected 'no error': actual '%s'", err)
+			}
+			out, err := r.Run(context.Background(), fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test builder: expected 'no error' on run: actual '%s'", err)
+			}
+			if string(out) != mock.expectedOutput {
+				t.Fatalf("failed to test builder: expected output '%s': actual '%s'", mock.expectedOutput, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+)
+
+// ResultCache caches the results of read only tasks. A cached result is
+// reused instead of executing a task having the same rendered specifications.
+type ResultCache interface {
+	// Get returns the cached result if any against the provided key
+	Get(key string) (result interface{}, found bool)
+	// Set caches the provided result against the provided key
+	Set(key string, result interface{})
+}
+
+// cachedResult is a result cached by TTLResultCache
+type cachedResult struct {
+	result  interface{}
+	expires time.Time
+}
+
+// TTLResultCache is an in-memory result cache whose entries expire after a
+// fixed duration
+type TTLResultCache struct {
+	ttl     time.Duration
+	mutex   sync.Mutex
+	entries map[string]cachedResult
+}
+
+// NewTTLResultCache returns a new instance of TTLResultCache whose entries
+// expire after the provided duration
+func NewTTLResultCache(ttl time.Duration) *TTLResultCache {
+	return &TTLResultCache{
+		ttl:     ttl,
+		entries: map[string]cachedResult{},
+	}
+}
+
+// Get returns the cached result if any against the provided key. Expired
+// entries are evicted & are never returned.
+func (c *TTLResultCache) Get(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.result, true
+}
+
+// Set caches the provided result against the provided key
+func (c *TTLResultCache) Set(key string, result interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[key] = cachedResult{
+		result:  result,
+		expires: time.Now().Add(c.ttl),
+	}
+}
+
+// isCacheable flags if the result of this task can be cached
+//
+// NOTE:
+//  Only the results of get & list actions are cached. Results of tasks that
+// retry on verification errors are not cached since these tasks expect their
+// results to change.
+func (m *taskExecutor) isCacheable() bool {
+	if m.resultCache == nil || !m.metaTaskExec.isCacheable() {
+		return false
+	}
+	if !m.metaTaskExec.isGet() && !m.metaTaskExec.isList() {
+		return false
+	}
+	return len(strings.TrimSpace(m.metaTaskExec.getMetaInfo().Retry)) == 0
+}
+
+// resultCacheKey returns the hash of this task's rendered meta & task
+// specifications
+func (m *taskExecutor) resultCacheKey() (key string, err error) {
+	meta, err := json.Marshal(m.metaTaskExec.getMetaInfo())
+	if err != nil {
+		return
+	}
+
+	h := sha256.New()
+	h.Write(meta)
+	if m.runtask != nil && len(m.runtask.Spec.Task) != 0 {
+		var spec []byte
+		spec, err = m.asTemplatedBytes("RunTask", m.runtask.Spec.Task)
+		if err != nil {
+			return
+		}
+		h.Write(spec)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// getCachedResult sets the cached result of this task at .JsonResult if
+// available
+func (m *taskExecutor) getCachedResult(key string) (found bool) {
+	result, found := m.resultCache.Get(key)
+	if !found {
+		return
+	}
+
+	if isDebug() {
+		m.logger().Info("using cached result of task", "task", m.getTaskIdentity())
+	}
+	util.SetNestedField(m.templateValues, result, string(v1alpha1.CurrentJSONResultTLP))
+	return
+}
+
+// setCachedResult caches the result of this task i.e. .JsonResult
+func (m *taskExecutor) setCachedResult(key string) {
+	result := util.GetNestedField(m.templateValues, string(v1alpha1.CurrentJSONResultTLP))
+	if result == nil {
+		return
+	}
+	m.resultCache.Set(key, result)
+}
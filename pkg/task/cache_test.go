@@ -0,0 +1,133 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLResultCache(t *testing.T) {
+	tests := map[string]struct {
+		ttl     time.Duration
+		wait    time.Duration
+		isFound bool
+	}{
+		"ttl result cache - +ve test case - entry within ttl": {
+			ttl:     time.Minute,
+			isFound: true,
+		},
+		"ttl result cache - +ve test case - expired entry": {
+			ttl:  time.Millisecond,
+			wait: 5 * time.Millisecond,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := NewTTLResultCache(mock.ttl)
+			c.Set("key", []byte("result"))
+			time.Sleep(mock.wait)
+
+			result, found := c.Get("key")
+			if found != mock.isFound {
+				t.Fatalf("failed to test ttl result cache: expected found '%t': actual found '%t'", mock.isFound, found)
+			}
+			if found && string(result.([]byte)) != "result" {
+				t.Fatalf("failed to test ttl result cache: expected 'result': actual '%s'", result)
+			}
+		})
+	}
+}
+
+func TestIsCacheable(t *testing.T) {
+	tests := map[string]struct {
+		cache    ResultCache
+		meta     MetaTaskSpec
+		expected bool
+	}{
+		"is cacheable - +ve test case - cacheable get": {
+			cache:    NewTTLResultCache(time.Minute),
+			meta:     MetaTaskSpec{Action: GetTA, MetaTaskProps: MetaTaskProps{Cacheable: true}},
+			expected: true,
+		},
+		"is cacheable - +ve test case - cacheable list": {
+			cache:    NewTTLResultCache(time.Minute),
+			meta:     MetaTaskSpec{Action: ListTA, MetaTaskProps: MetaTaskProps{Cacheable: true}},
+			expected: true,
+		},
+		"is cacheable - -ve test case - no cache": {
+			meta: MetaTaskSpec{Action: GetTA, MetaTaskProps: MetaTaskProps{Cacheable: true}},
+		},
+		"is cacheable - -ve test case - not marked as cacheable": {
+			cache: NewTTLResultCache(time.Minute),
+			meta:  MetaTaskSpec{Action: GetTA},
+		},
+		"is cacheable - -ve test case - put is never cached": {
+			cache: NewTTLResultCache(time.Minute),
+			meta:  MetaTaskSpec{Action: PutTA, MetaTaskProps: MetaTaskProps{Cacheable: true}},
+		},
+		"is cacheable - -ve test case - patch is never cached": {
+			cache: NewTTLResultCache(time.Minute),
+			meta:  MetaTaskSpec{Action: PatchTA, MetaTaskProps: MetaTaskProps{Cacheable: true}},
+		},
+		"is cacheable - -ve test case - get with retry": {
+			cache: NewTTLResultCache(time.Minute),
+			meta:  MetaTaskSpec{Action: GetTA, MetaTaskProps: MetaTaskProps{Cacheable: true, Retry: "3,1s"}},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			te := &taskExecutor{
+				resultCache:  mock.cache,
+				metaTaskExec: &metaTaskExecutor{metaTask: mock.meta},
+			}
+			if te.isCacheable() != mock.expected {
+				t.Fatalf("failed to test is cacheable: expected '%t': actual '%t'", mock.expected, !mock.expected)
+			}
+		})
+	}
+}
+
+func TestResultCacheKey(t *testing.T) {
+	newExecutor := func(pvc string) *taskExecutor {
+		te := &taskExecutor{
+			templateValues: map[string]interface{}{"pvc": pvc},
+			runtask:        fakeCommandRunTask("getpvc", ""),
+			metaTaskExec: &metaTaskExecutor{
+				metaTask: MetaTaskSpec{MetaTaskIdentity: MetaTaskIdentity{Identity: "getpvc"}, Action: GetTA},
+			},
+		}
+		te.runtask.Spec.Task = "name: {{ .pvc }}"
+		return te
+	}
+
+	key1, err := newExecutor("pvc-1").resultCacheKey()
+	if err != nil {
+		t.Fatalf("failed to test result cache key: expected 'no error': actual '%s'", err)
+	}
+	key2, _ := newExecutor("pvc-1").resultCacheKey()
+	key3, _ := newExecutor("pvc-2").resultCacheKey()
+
+	if key1 != key2 {
+		t.Fatalf("failed to test result cache key: expected same keys for same rendered inputs: actual '%s' '%s'", key1, key2)
+	}
+	if key1 == key3 {
+		t.Fatalf("failed to test result cache key: expected different keys for different rendered inputs: actual '%s'", key1)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// defaultCapacityPath is the jsonpath of the capacity requested by a task
+// if the task does not set one i.e. the capacity of the volume
+var defaultCapacityPath = fmt.Sprintf("{.%s.%s}", v1alpha1.VolumeTLP, v1alpha1.CapacityVTP)
+
+// verifyCapacity verifies if the capacity requested by the provided task is
+// within the task's max capacity. The requested capacity is found at the
+// task's capacity path of the provided template values.
+//
+// NOTE:
+//  Nothing is verified if the task does not set a max capacity. An error is
+// returned if the requested capacity can not be found since the limit can
+// not be enforced in this case.
+func verifyCapacity(runtask *v1alpha1.RunTask, values map[string]interface{}) error {
+	max := runtask.Spec.MaxCapacity
+	if max.IsZero() {
+		return nil
+	}
+
+	path := runtask.Spec.CapacityPath
+	if len(path) == 0 {
+		path = defaultCapacityPath
+	}
+
+	j := jsonpath.New("capacity")
+	err := j.Parse(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to verify capacity of runtask '%s': invalid capacity path '%s'", runtask.Name, path)
+	}
+	buf := new(bytes.Buffer)
+	err = j.Execute(buf, values)
+	if err != nil {
+		return errors.Wrapf(err, "failed to verify capacity of runtask '%s': capacity not found at '%s'", runtask.Name, path)
+	}
+
+	requested := strings.TrimSpace(buf.String())
+	if len(requested) == 0 {
+		return fmt.Errorf("failed to verify capacity of runtask '%s': capacity not found at '%s'", runtask.Name, path)
+	}
+	capacity, err := resource.ParseQuantity(requested)
+	if err != nil {
+		return errors.Wrapf(err, "failed to verify capacity of runtask '%s': invalid capacity '%s' at '%s'", runtask.Name, requested, path)
+	}
+
+	if capacity.Cmp(max) > 0 {
+		return fmt.Errorf("failed to verify capacity of runtask '%s': requested capacity '%s' exceeds max capacity '%s'", runtask.Name, capacity.String(), max.String())
+	}
+	return nil
+}
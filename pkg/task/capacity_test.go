@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestVerifyCapacity(t *testing.T) {
+	tests := map[string]struct {
+		maxCapacity  string
+		capacityPath string
+		values       map[string]interface{}
+		isErr        bool
+	}{
+		"verify capacity - +ve test case - no max capacity": {
+			values: map[string]interface{}{"Volume": map[string]interface{}{"capacity": "10Ti"}},
+		},
+		"verify capacity - +ve test case - capacity within limit": {
+			maxCapacity: "100Gi",
+			values:      map[string]interface{}{"Volume": map[string]interface{}{"capacity": "5G"}},
+		},
+		"verify capacity - +ve test case - capacity equals limit": {
+			maxCapacity: "100Gi",
+			values:      map[string]interface{}{"Volume": map[string]interface{}{"capacity": "100Gi"}},
+		},
+		"verify capacity - -ve test case - capacity exceeds limit": {
+			maxCapacity: "100Gi",
+			values:      map[string]interface{}{"Volume": map[string]interface{}{"capacity": "2Ti"}},
+			isErr:       true,
+		},
+		"verify capacity - +ve test case - capacity at custom path": {
+			maxCapacity:  "10G",
+			capacityPath: "{.Pool.size}",
+			values:       map[string]interface{}{"Pool": map[string]interface{}{"size": "8G"}},
+		},
+		"verify capacity - -ve test case - capacity at custom path exceeds limit": {
+			maxCapacity:  "10G",
+			capacityPath: "{.Pool.size}",
+			values:       map[string]interface{}{"Pool": map[string]interface{}{"size": "11G"}},
+			isErr:        true,
+		},
+		"verify capacity - -ve test case - capacity not found": {
+			maxCapacity: "100Gi",
+			values:      map[string]interface{}{"Volume": map[string]interface{}{}},
+			isErr:       true,
+		},
+		"verify capacity - -ve test case - invalid capacity": {
+			maxCapacity: "100Gi",
+			values:      map[string]interface{}{"Volume": map[string]interface{}{"capacity": "huge"}},
+			isErr:       true,
+		},
+		"verify capacity - -ve test case - invalid capacity path": {
+			maxCapacity:  "100Gi",
+			capacityPath: "{.Volume.capacity",
+			values:       map[string]interface{}{"Volume": map[string]interface{}{"capacity": "5G"}},
+			isErr:        true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			runtask := &v1alpha1.RunTask{}
+			runtask.Name = "rt1"
+			runtask.Spec.CapacityPath = mock.capacityPath
+			if len(mock.maxCapacity) != 0 {
+				runtask.Spec.MaxCapacity = resource.MustParse(mock.maxCapacity)
+			}
+
+			err := verifyCapacity(runtask, mock.values)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test verify capacity: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+		})
+	}
+}
+
+func TestNewTaskExecutorMaxCapacity(t *testing.T) {
+	runtask := fakeCommandRunTask("t1", "")
+	runtask.Spec.MaxCapacity = resource.MustParse("1Ti")
+	values := fakeTemplateValues()
+	values["Volume"] = map[string]interface{}{"capacity": "10Ti"}
+
+	te, err := newTaskExecutor(runtask, values)
+	if err == nil || te != nil {
+		t.Fatalf("failed to test max capacity: expected error: actual 'no error'")
+	}
+}
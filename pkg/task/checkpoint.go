@@ -0,0 +1,349 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	k8s "github.com/openebs/maya/pkg/client/k8s/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+	api_core_v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	mach_apis_meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	typed_core_v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// checkpointSecretPrefix is the prefix of the name of the Secret that
+	// holds a runner's checkpoint
+	checkpointSecretPrefix = "maya-checkpoint-"
+	// checkpointSecretKey is the key of the Secret's data that holds a
+	// runner's checkpoint
+	checkpointSecretKey = "checkpoint"
+	// checkpointLabelKey is the label set against the Secrets holding the
+	// checkpoints
+	checkpointLabelKey = "openebs.io/checkpoint"
+)
+
+// Checkpoint is the progress of a task group runner's run
+type Checkpoint struct {
+	// RunnerID is the identity of the runner this checkpoint belongs to
+	RunnerID string `json:"runnerID"`
+	// CompletedTasks are the identities of the tasks that completed
+	CompletedTasks []string `json:"completedTasks"`
+	// Values are the template values produced by the completed tasks
+	Values map[string]interface{} `json:"values"`
+	// Rollbacks are the names of the objects planned for rollback by the
+	// identities of the completed tasks that created them
+	Rollbacks map[string][]string `json:"rollbacks,omitempty"`
+}
+
+// Checkpointer is the contract to save & load the checkpoints of task group
+// runners. A saved checkpoint lets a run that was interrupted e.g. due to a
+// restart of maya api server resume from the task that did not complete.
+type Checkpointer interface {
+	// SaveCheckpoint saves the provided checkpoint against the identity of
+	// its runner
+	SaveCheckpoint(checkpoint *Checkpoint) error
+	// LoadCheckpoint returns the checkpoint of the runner with the provided
+	// identity; nil is returned if there is no checkpoint
+	LoadCheckpoint(runnerID string) (*Checkpoint, error)
+	// DeleteCheckpoint deletes the checkpoint of the runner with the provided
+	// identity if any
+	DeleteCheckpoint(runnerID string) error
+}
+
+// SecretCheckpointer saves the checkpoints of task group runners as K8s
+// Secrets
+//
+// NOTE:
+//  Secrets are used since the template values of a checkpoint may hold the
+// values of the secrets referred to by the tasks
+//
+// NOTE:
+//  This is an implementation of Checkpointer interface
+type SecretCheckpointer struct {
+	// namespace is where the Secrets are saved
+	namespace string
+	// cs is the kubernetes clientset; defaults to the in cluster clientset
+	cs kubernetes.Interface
+}
+
+// NewSecretCheckpointer returns a new instance of SecretCheckpointer that
+// saves the checkpoints in the provided namespace
+func NewSecretCheckpointer(namespace string) *SecretCheckpointer {
+	return &SecretCheckpointer{namespace: namespace}
+}
+
+// checkpointSecretName returns the name of the Secret holding the checkpoint
+// of the provided runner
+func checkpointSecretName(runnerID string) string {
+	return checkpointSecretPrefix + strings.ToLower(runnerID)
+}
+
+// secrets returns the Secret operations of this checkpointer's namespace
+func (c *SecretCheckpointer) secrets() (typed_core_v1.SecretInterface, error) {
+	if c.cs == nil {
+		cs, err := k8s.Clientset().Get()
+		if err != nil {
+			return nil, err
+		}
+		c.cs = cs
+	}
+	return c.cs.CoreV1().Secrets(c.namespace), nil
+}
+
+// SaveCheckpoint saves the provided checkpoint as a Secret. The Secret is
+// created if it does not exist.
+func (c *SecretCheckpointer) SaveCheckpoint(checkpoint *Checkpoint) error {
+	runnerID := checkpoint.RunnerID
+	b, err := json.Marshal(checkpoint)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save checkpoint of runner '%s'", runnerID)
+	}
+
+	secrets, err := c.secrets()
+	if err != nil {
+		return errors.Wrapf(err, "failed to save checkpoint of runner '%s'", runnerID)
+	}
+
+	name := checkpointSecretName(runnerID)
+	secret, err := secrets.Get(name, mach_apis_meta_v1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		secret = &api_core_v1.Secret{}
+		secret.Name = name
+		secret.Namespace = c.namespace
+		secret.Labels = map[string]string{checkpointLabelKey: "true"}
+		secret.Type = api_core_v1.SecretTypeOpaque
+		secret.Data = map[string][]byte{checkpointSecretKey: b}
+		_, err = secrets.Create(secret)
+		return errors.Wrapf(err, "failed to save checkpoint of runner '%s'", runnerID)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to save checkpoint of runner '%s'", runnerID)
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[checkpointSecretKey] = b
+	_, err = secrets.Update(secret)
+	return errors.Wrapf(err, "failed to save checkpoint of runner '%s'", runnerID)
+}
+
+// LoadCheckpoint returns the checkpoint of the provided runner from its
+// Secret. Nil is returned if the Secret does not exist.
+func (c *SecretCheckpointer) LoadCheckpoint(runnerID string) (*Checkpoint, error) {
+	secrets, err := c.secrets()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load checkpoint of runner '%s'", runnerID)
+	}
+
+	secret, err := secrets.Get(checkpointSecretName(runnerID), mach_apis_meta_v1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load checkpoint of runner '%s'", runnerID)
+	}
+
+	data, ok := secret.Data[checkpointSecretKey]
+	if !ok {
+		return nil, nil
+	}
+	checkpoint := &Checkpoint{}
+	err = json.Unmarshal(data, checkpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load checkpoint of runner '%s': invalid checkpoint", runnerID)
+	}
+	return checkpoint, nil
+}
+
+// DeleteCheckpoint deletes the Secret holding the checkpoint of the provided
+// runner if any
+func (c *SecretCheckpointer) DeleteCheckpoint(runnerID string) error {
+	secrets, err := c.secrets()
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete checkpoint of runner '%s'", runnerID)
+	}
+
+	err = secrets.Delete(checkpointSecretName(runnerID), &mach_apis_meta_v1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete checkpoint of runner '%s'", runnerID)
+	}
+	return nil
+}
+
+// checkpointedTLPs are the top level properties of template values that are
+// produced by the tasks & hence are saved in a checkpoint. Rest of the
+// template values are provided by the caller of the run.
+var checkpointedTLPs = []v1alpha1.TopLevelProperty{
+	v1alpha1.TaskResultTLP,
+	v1alpha1.ListItemsTLP,
+}
+
+// SetCheckpointer sets the checkpointer that saves the progress of this
+// runner after each of its stages. A run resumes from the checkpoint saved
+// against the provided runner identity if any i.e. the completed tasks are
+// not executed again & their results are restored. The checkpoint is
+// deleted once the run completes.
+//
+// NOTE:
+//  Rollbacks of the tasks restored from a checkpoint are planned again so
+// that their objects are rolled back if the resumed run fails
+func (m *TaskGroupRunner) SetCheckpointer(runnerID string, c Checkpointer) {
+	m.runnerID = runnerID
+	m.checkpointer = c
+}
+
+// isCheckpointed flags if the progress of this runner is saved
+func (m *TaskGroupRunner) isCheckpointed() bool {
+	return m.checkpointer != nil && len(m.runnerID) != 0
+}
+
+// resume restores the results of the tasks that completed as per the saved
+// checkpoint if any
+//
+// NOTE:
+//  Failure to load the checkpoint is only logged; all the tasks are
+// executed in this case
+func (m *TaskGroupRunner) resume(values map[string]interface{}) {
+	if !m.isCheckpointed() {
+		return
+	}
+
+	checkpoint, err := m.checkpointer.LoadCheckpoint(m.runnerID)
+	if err != nil {
+		m.logger().Error(err, "will execute all the runtasks")
+		return
+	}
+	if checkpoint == nil {
+		return
+	}
+
+	m.logger().Info("resuming runner from checkpoint", "runner", m.runnerID, "completedTasks", checkpoint.CompletedTasks)
+	for k, v := range checkpoint.Values {
+		values[k] = v
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.resumed = append([]string(nil), checkpoint.CompletedTasks...)
+	m.completed = append([]string(nil), checkpoint.CompletedTasks...)
+	m.resumedRollbacks = checkpoint.Rollbacks
+}
+
+// isResumed flags if the task with the provided identity was completed as
+// per the checkpoint this run resumed from
+func (m *TaskGroupRunner) isResumed(identity string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return util.ContainsString(m.resumed, identity)
+}
+
+// resumeOrExecute executes the task unless it completed as per the
+// checkpoint this run resumed from. The task is recorded as completed if it
+// gets executed successfully.
+//
+// NOTE:
+//  A task that completed as per the checkpoint is not executed but its
+// rollback is planned for the objects it created
+func (m *TaskGroupRunner) resumeOrExecute(ctx context.Context, te *taskExecutor) (err error) {
+	if m.isResumed(te.getTaskIdentity()) {
+		m.logger().Info("skipping task because it completed as per checkpoint", "task", te.getTaskIdentity())
+		redactJsonResult(te.templateValues)
+		m.recordSkipped(te.runtask.Name, te.getTaskIdentity())
+
+		m.mutex.Lock()
+		objectNames := m.resumedRollbacks[te.getTaskIdentity()]
+		m.mutex.Unlock()
+		err = m.planForRollback(te, objectNames)
+		if err != nil {
+			m.logger().Error(err, "failed to plan for rollback", "task", te.getTaskIdentity())
+		}
+		return
+	}
+
+	err = m.executeATask(ctx, te)
+	if err == nil {
+		m.markCompleted(te.getTaskIdentity())
+	}
+	return
+}
+
+// markCompleted records the task with the provided identity as completed
+func (m *TaskGroupRunner) markCompleted(identity string) {
+	if !m.isCheckpointed() {
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.completed = append(m.completed, identity)
+}
+
+// saveCheckpoint saves the identities of the completed tasks along with the
+// template values produced by them & the objects planned for rollback
+//
+// NOTE:
+//  Failure to save the checkpoint is only logged since it does not affect
+// the run in progress
+func (m *TaskGroupRunner) saveCheckpoint(values map[string]interface{}) {
+	if !m.isCheckpointed() {
+		return
+	}
+
+	saved := map[string]interface{}{}
+	for _, tlp := range checkpointedTLPs {
+		if v, ok := values[string(tlp)]; ok {
+			saved[string(tlp)] = v
+		}
+	}
+
+	m.mutex.Lock()
+	completed := append([]string(nil), m.completed...)
+	m.mutex.Unlock()
+
+	err := m.checkpointer.SaveCheckpoint(&Checkpoint{
+		RunnerID:       m.runnerID,
+		CompletedTasks: completed,
+		Values:         saved,
+		Rollbacks:      m.rollbacks.objectNames(),
+	})
+	if err != nil {
+		m.logger().Error(err, "failed to save checkpoint", "runner", m.runnerID)
+	}
+}
+
+// deleteCheckpoint deletes the checkpoint once the run completes
+func (m *TaskGroupRunner) deleteCheckpoint() {
+	if !m.isCheckpointed() {
+		return
+	}
+
+	err := m.checkpointer.DeleteCheckpoint(m.runnerID)
+	if err != nil {
+		m.logger().Error(err, "failed to delete checkpoint", "runner", m.runnerID)
+	}
+}
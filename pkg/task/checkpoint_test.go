@@ -0,0 +1,267 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// fakeCheckpointer saves the checkpoints in memory
+type fakeCheckpointer struct {
+	checkpoints map[string]*Checkpoint
+	// saved holds the completed tasks of each save
+	saved   [][]string
+	deleted bool
+}
+
+func (f *fakeCheckpointer) SaveCheckpoint(checkpoint *Checkpoint) error {
+	f.saved = append(f.saved, checkpoint.CompletedTasks)
+	f.checkpoints[checkpoint.RunnerID] = checkpoint
+	return nil
+}
+
+func (f *fakeCheckpointer) LoadCheckpoint(runnerID string) (*Checkpoint, error) {
+	return f.checkpoints[runnerID], nil
+}
+
+func (f *fakeCheckpointer) DeleteCheckpoint(runnerID string) error {
+	f.deleted = true
+	delete(f.checkpoints, runnerID)
+	return nil
+}
+
+func TestCheckpointResume(t *testing.T) {
+	tests := map[string]struct {
+		checkpoint     *Checkpoint
+		parallel       bool
+		expectedOutput string
+		expectedSaved  [][]string
+	}{
+		"checkpoint - +ve test case - no checkpoint": {
+			expectedOutput: "t1-new",
+			expectedSaved:  [][]string{{"t1"}, {"t1", "t2"}},
+		},
+		"checkpoint - +ve test case - resume from checkpoint": {
+			checkpoint: &Checkpoint{
+				RunnerID:       "vol-1",
+				CompletedTasks: []string{"t1"},
+				Values: map[string]interface{}{
+					string(v1alpha1.TaskResultTLP): map[string]interface{}{
+						"t1": map[string]interface{}{"objectName": "t1-old"},
+					},
+				},
+			},
+			expectedOutput: "t1-old",
+			expectedSaved:  [][]string{{"t1"}, {"t1", "t2"}},
+		},
+		"checkpoint - +ve test case - resume parallel tasks from checkpoint": {
+			checkpoint: &Checkpoint{
+				RunnerID:       "vol-1",
+				CompletedTasks: []string{"t1"},
+				Values: map[string]interface{}{
+					string(v1alpha1.TaskResultTLP): map[string]interface{}{
+						"t1": map[string]interface{}{"objectName": "t1-old"},
+					},
+				},
+			},
+			parallel:       true,
+			expectedOutput: "t1-old",
+			expectedSaved:  [][]string{{"t1", "t2"}},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &fakeCheckpointer{checkpoints: map[string]*Checkpoint{}}
+			if mock.checkpoint != nil {
+				c.checkpoints["vol-1"] = mock.checkpoint
+			}
+
+			r := NewTaskGroupRunner()
+			r.SetCheckpointer("vol-1", c)
+			t1 := fakeCommandRunTask("t1", `{{- "t1-new" | saveAs "t1.objectName" .TaskResult | noop -}}`)
+			t2 := fakeCommandRunTask("t2", `{{- "t2-new" | saveAs "t2.objectName" .TaskResult | noop -}}`)
+			if mock.parallel {
+				r.AddParallelRunTasks(t1, t2)
+			} else {
+				r.AddRunTask(t1)
+				r.AddRunTask(t2)
+			}
+			r.SetOutputTask(&v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{
+				Meta: "id: o1\nkind: Command\naction: output",
+				Task: `{{ nestedString .TaskResult "t1" "objectName" }}`,
+			}})
+
+			output, err := r.Run(context.Background(), fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test checkpoint: expected 'no error': actual '%s'", err)
+			}
+			if string(output) != mock.expectedOutput {
+				t.Fatalf("failed to test checkpoint: expected output '%s': actual '%s'", mock.expectedOutput, output)
+			}
+			if !reflect.DeepEqual(c.saved, mock.expectedSaved) {
+				t.Fatalf("failed to test checkpoint: expected saves '%v': actual '%v'", mock.expectedSaved, c.saved)
+			}
+			if !c.deleted || len(c.checkpoints) != 0 {
+				t.Fatalf("failed to test checkpoint: expected checkpoint to be deleted after the run")
+			}
+		})
+	}
+}
+
+func TestCheckpointRollback(t *testing.T) {
+	tests := map[string]struct {
+		checkpoint         *Checkpoint
+		expectedRolledBack []string
+	}{
+		"checkpoint rollback - +ve test case - no checkpoint": {
+			expectedRolledBack: []string{"t1-new"},
+		},
+		"checkpoint rollback - +ve test case - resume from checkpoint": {
+			checkpoint: &Checkpoint{
+				RunnerID:       "vol-1",
+				CompletedTasks: []string{"t1"},
+				Values: map[string]interface{}{
+					string(v1alpha1.TaskResultTLP): map[string]interface{}{
+						"t1": map[string]interface{}{"objectName": "t1-old"},
+					},
+				},
+				Rollbacks: map[string][]string{"t1": {"t1-old"}},
+			},
+			expectedRolledBack: []string{"t1-old"},
+		},
+		"checkpoint rollback - +ve test case - resume from checkpoint without rollbacks": {
+			checkpoint: &Checkpoint{
+				RunnerID:       "vol-1",
+				CompletedTasks: []string{"t1"},
+				Values: map[string]interface{}{
+					string(v1alpha1.TaskResultTLP): map[string]interface{}{
+						"t1": map[string]interface{}{"objectName": "t1-old"},
+					},
+				},
+			},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &fakeCheckpointer{checkpoints: map[string]*Checkpoint{}}
+			if mock.checkpoint != nil {
+				c.checkpoints["vol-1"] = mock.checkpoint
+			}
+
+			r := NewTaskGroupRunner()
+			r.SetCheckpointer("vol-1", c)
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-new" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+			r.AddRunTask(fakeCommandRunTask("t2", `{{- fail "t2 failed" -}}`))
+
+			result := r.RunWithReport(fakeTemplateValues())
+			if result.Err == nil {
+				t.Fatalf("failed to test checkpoint rollback: expected 'error': actual 'no error'")
+			}
+			var rolledBack []string
+			for _, rte := range r.rollbacks.planned {
+				rolledBack = append(rolledBack, rte.getTaskObjectName())
+			}
+			if len(result.RolledBackTasks) != len(mock.expectedRolledBack) || !reflect.DeepEqual(rolledBack, mock.expectedRolledBack) {
+				t.Fatalf("failed to test checkpoint rollback: expected rolled back objects '%v': actual '%v'", mock.expectedRolledBack, rolledBack)
+			}
+		})
+	}
+}
+
+func TestCheckpointSavesRollbacks(t *testing.T) {
+	c := &fakeCheckpointer{checkpoints: map[string]*Checkpoint{}}
+	saved := map[string][]string{}
+	r := NewTaskGroupRunner()
+	r.SetCheckpointer("vol-1", c)
+	r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-new" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+	r.AddRunTask(fakeCommandRunTask("t2", `{{- "t2-new" | saveAs "t2.objectName" .TaskResult | noop -}}`))
+	r.SetPreTaskRunFn(func(taskID string, values map[string]interface{}) error {
+		// checkpoint saved after t1 is inspected before t2 is executed
+		if cp := c.checkpoints["vol-1"]; cp != nil {
+			for k, v := range cp.Rollbacks {
+				saved[k] = v
+			}
+		}
+		return nil
+	})
+
+	_, err := r.Run(context.Background(), fakeTemplateValues())
+	if err != nil {
+		t.Fatalf("failed to test checkpoint saves rollbacks: expected 'no error': actual '%s'", err)
+	}
+	expected := map[string][]string{"t1": {"t1-new"}}
+	if !reflect.DeepEqual(saved, expected) {
+		t.Fatalf("failed to test checkpoint saves rollbacks: expected '%v': actual '%v'", expected, saved)
+	}
+}
+
+func TestSecretCheckpointer(t *testing.T) {
+	c := NewSecretCheckpointer("openebs")
+	c.cs = fake.NewSimpleClientset()
+
+	checkpoint, err := c.LoadCheckpoint("vol-1")
+	if err != nil || checkpoint != nil {
+		t.Fatalf("failed to test secret checkpointer: expected no checkpoint: actual '%v': error '%v'", checkpoint, err)
+	}
+
+	for idx, completed := range [][]string{{"t1"}, {"t1", "t2"}} {
+		values := map[string]interface{}{"TaskResult": map[string]interface{}{"t1": fmt.Sprintf("save-%d", idx)}}
+		err = c.SaveCheckpoint(&Checkpoint{RunnerID: "vol-1", CompletedTasks: completed, Values: values, Rollbacks: map[string][]string{"t1": {"t1-obj"}}})
+		if err != nil {
+			t.Fatalf("failed to test secret checkpointer: expected 'no error' on save '%d': actual '%s'", idx, err)
+		}
+
+		checkpoint, err = c.LoadCheckpoint("vol-1")
+		if err != nil {
+			t.Fatalf("failed to test secret checkpointer: expected 'no error' on load '%d': actual '%s'", idx, err)
+		}
+		expected := &Checkpoint{RunnerID: "vol-1", CompletedTasks: completed, Values: values, Rollbacks: map[string][]string{"t1": {"t1-obj"}}}
+		if !reflect.DeepEqual(checkpoint, expected) {
+			t.Fatalf("failed to test secret checkpointer: expected '%v': actual '%v'", expected, checkpoint)
+		}
+	}
+
+	// checkpoint is not saved in plain text e.g. as a config map
+	cms, _ := c.cs.CoreV1().ConfigMaps("openebs").List(metav1.ListOptions{})
+	secrets, _ := c.cs.CoreV1().Secrets("openebs").List(metav1.ListOptions{})
+	if len(cms.Items) != 0 || len(secrets.Items) != 1 {
+		t.Fatalf("failed to test secret checkpointer: expected checkpoint to be saved as a secret: actual '%d' config maps & '%d' secrets", len(cms.Items), len(secrets.Items))
+	}
+
+	err = c.DeleteCheckpoint("vol-1")
+	if err != nil {
+		t.Fatalf("failed to test secret checkpointer: expected 'no error' on delete: actual '%s'", err)
+	}
+	checkpoint, err = c.LoadCheckpoint("vol-1")
+	if err != nil || checkpoint != nil {
+		t.Fatalf("failed to test secret checkpointer: expected no checkpoint after delete: actual '%v': error '%v'", checkpoint, err)
+	}
+	// deleting a checkpoint that does not exist is not an error
+	err = c.DeleteCheckpoint("vol-1")
+	if err != nil {
+		t.Fatalf("failed to test secret checkpointer: expected 'no error' on repeat delete: actual '%s'", err)
+	}
+}
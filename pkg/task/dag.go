@@ -0,0 +1,201 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// SetTaskDependencies sets the dependencies between the tasks of this runner.
+// Keys are the task identities & values are the identities of the tasks that
+// must complete before the task is executed.
+//
+// NOTE:
+//  Tasks are ordered as per these dependencies instead of the order in which
+// they were added. Tasks without dependencies between them are executed in
+// parallel if this runner's concurrency is more than 1. Tasks are executed in
+// sequence otherwise.
+//
+// NOTE:
+//  Runner without dependencies executes its tasks in the order in which they
+// were added
+//
+// NOTE:
+//  Dependencies can not be set along with the tasks added via
+// AddParallelRunTasks since the order of execution of these tasks is
+// determined by their groups. Running or validating such a runner results
+// in an error.
+func (m *TaskGroupRunner) SetTaskDependencies(deps map[string][]string) {
+	m.dependencies = deps
+}
+
+// taskIdentities returns the identities of this runner's tasks. Identities
+// are resolved by templating the meta specifications of the tasks against
+// the provided template values.
+func (m *TaskGroupRunner) taskIdentities(values map[string]interface{}) (ids []string, err error) {
+	values = util.DeepCopyMap(values)
+	for _, runtask := range m.allTasks {
+		mts, _, _, err := getMetaInstances(runtask.Spec.Meta, values, m.templateFuncs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve id of runtask '%s'", runtask.Name)
+		}
+		ids = append(ids, mts.Identity)
+	}
+	return
+}
+
+// dependencyStages groups the tasks of this runner in stages as per the
+// task dependencies. Each stage holds the tasks whose dependencies belong to
+// the previous stages. Tasks of a stage are in the order in which they were
+// added.
+//
+// NOTE:
+//  Stages are built using Kahn's algorithm. Dependencies that form a cycle
+// or refer to unknown tasks result in an error. Dependencies along with
+// parallel groups result in an error as well.
+func (m *TaskGroupRunner) dependencyStages(values map[string]interface{}) (stages [][]*v1alpha1.RunTask, err error) {
+	if len(m.parallelGroups) != 0 {
+		return nil, fmt.Errorf("invalid task dependencies: dependencies can not be set along with parallel run tasks")
+	}
+
+	ids, err := m.taskIdentities(values)
+	if err != nil {
+		return
+	}
+
+	indexOf := map[string]int{}
+	for idx, id := range ids {
+		indexOf[id] = idx
+	}
+
+	indegree := make([]int, len(ids))
+	dependents := make([][]int, len(ids))
+	for id, deps := range m.dependencies {
+		idx, ok := indexOf[id]
+		if !ok {
+			return nil, fmt.Errorf("invalid task dependencies: unknown task '%s'", id)
+		}
+		for _, dep := range deps {
+			didx, ok := indexOf[dep]
+			if !ok {
+				return nil, fmt.Errorf("invalid task dependencies: unknown dependency '%s' of task '%s'", dep, id)
+			}
+			indegree[idx]++
+			dependents[didx] = append(dependents[didx], idx)
+		}
+	}
+
+	var ready []int
+	for idx := range ids {
+		if indegree[idx] == 0 {
+			ready = append(ready, idx)
+		}
+	}
+
+	resolved := 0
+	for len(ready) != 0 {
+		var stage []*v1alpha1.RunTask
+		var next []int
+		for _, idx := range ready {
+			stage = append(stage, m.allTasks[idx])
+			for _, dependent := range dependents[idx] {
+				indegree[dependent]--
+				if indegree[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+		stages = append(stages, stage)
+		resolved += len(ready)
+
+		// tasks of a stage are in the order in which they were added
+		sort.Ints(next)
+		ready = next
+	}
+
+	if resolved != len(ids) {
+		var cyclic []string
+		for idx, id := range ids {
+			if indegree[idx] > 0 {
+				cyclic = append(cyclic, id)
+			}
+		}
+		return nil, fmt.Errorf("invalid task dependencies: cycle found between tasks '%s'", strings.Join(cyclic, ", "))
+	}
+
+	if m.concurrency > 1 {
+		return
+	}
+
+	// tasks are executed in sequence
+	var sequential [][]*v1alpha1.RunTask
+	for _, stage := range stages {
+		for _, runtask := range stage {
+			sequential = append(sequential, []*v1alpha1.RunTask{runtask})
+		}
+	}
+	return sequential, nil
+}
+
+// PlannedTaskIDs returns the identities of this runner's tasks in the order
+// in which these tasks would be executed. None of the tasks are executed.
+// Task identities are verified for uniqueness similar to a run.
+//
+// NOTE:
+//  Identities are resolved by rendering the meta specifications of the tasks
+// against a copy of the provided template values. Results of the tasks are
+// not available while rendering since the tasks are not executed.
+//
+// NOTE:
+//  Tasks that would be skipped e.g. due to their run if predicate are
+// included since the predicates are evaluated only while running
+func (m *TaskGroupRunner) PlannedTaskIDs(values map[string]interface{}) (ids []string, err error) {
+	values = util.DeepCopyMap(values)
+	m.applySeededResults(values)
+
+	stages := m.stages()
+	if len(m.dependencies) != 0 {
+		stages, err = m.dependencyStages(values)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	planned := map[string]bool{}
+	for _, stage := range stages {
+		for _, runtask := range stage {
+			mts, _, _, err := getMetaInstances(runtask.Spec.Meta, values, m.templateFuncs)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to resolve id of runtask '%s'", runtask.Name)
+			}
+
+			id := strings.ToLower(mts.Identity)
+			if planned[id] || m.isSeeded(id) {
+				return nil, fmt.Errorf("invalid runtask '%s': multiple tasks having same identity is not allowed in a group run: duplicate id '%s'", runtask.Name, mts.Identity)
+			}
+			planned[id] = true
+			ids = append(ids, mts.Identity)
+		}
+	}
+	return
+}
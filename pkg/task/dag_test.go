@@ -0,0 +1,189 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+func TestDependencyStages(t *testing.T) {
+	tests := map[string]struct {
+		deps           map[string][]string
+		concurrency    int
+		parallel       bool
+		expectedStages [][]string
+		expectedErr    string
+	}{
+		"dependency stages - +ve test case - parallel stages": {
+			deps:           map[string][]string{"t1": {"t3"}, "t2": {"t3"}, "t4": {"t1", "t2"}},
+			concurrency:    2,
+			expectedStages: [][]string{{"t3"}, {"t1", "t2"}, {"t4"}},
+		},
+		"dependency stages - +ve test case - sequential stages": {
+			deps:           map[string][]string{"t1": {"t3"}, "t2": {"t3"}, "t4": {"t1", "t2"}},
+			expectedStages: [][]string{{"t3"}, {"t1"}, {"t2"}, {"t4"}},
+		},
+		"dependency stages - +ve test case - independent tasks": {
+			deps:           map[string][]string{"t4": {}},
+			concurrency:    4,
+			expectedStages: [][]string{{"t1", "t2", "t3", "t4"}},
+		},
+		"dependency stages - -ve test case - cycle": {
+			deps:        map[string][]string{"t1": {"t2"}, "t2": {"t3"}, "t3": {"t1"}},
+			expectedErr: "cycle found between tasks 't1, t2, t3'",
+		},
+		"dependency stages - -ve test case - unknown dependency": {
+			deps:        map[string][]string{"t1": {"t9"}},
+			expectedErr: "unknown dependency 't9' of task 't1'",
+		},
+		"dependency stages - -ve test case - unknown task": {
+			deps:        map[string][]string{"t9": {"t1"}},
+			expectedErr: "unknown task 't9'",
+		},
+		"dependency stages - -ve test case - parallel run tasks": {
+			deps:        map[string][]string{"t4": {"t3"}},
+			concurrency: 2,
+			parallel:    true,
+			expectedErr: "dependencies can not be set along with parallel run tasks",
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.SetConcurrency(mock.concurrency)
+			ids := []string{"t1", "t2", "t3", "t4"}
+			if mock.parallel {
+				r.AddParallelRunTasks(fakeCommandRunTask("t1", ""), fakeCommandRunTask("t2", ""))
+				ids = ids[2:]
+			}
+			for _, id := range ids {
+				r.AddRunTask(fakeCommandRunTask(id, ""))
+			}
+			r.SetTaskDependencies(mock.deps)
+
+			stages, err := r.dependencyStages(fakeTemplateValues())
+			if len(mock.expectedErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), mock.expectedErr) {
+					t.Fatalf("failed to test dependency stages: expected error '%s': actual '%v'", mock.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to test dependency stages: expected 'no error': actual '%s'", err)
+			}
+
+			var actual [][]string
+			for _, stage := range stages {
+				var names []string
+				for _, runtask := range stage {
+					names = append(names, runtask.Name)
+				}
+				actual = append(actual, names)
+			}
+			if !reflect.DeepEqual(actual, mock.expectedStages) {
+				t.Fatalf("failed to test dependency stages: expected '%v': actual '%v'", mock.expectedStages, actual)
+			}
+		})
+	}
+}
+
+func TestRunWithTaskDependencies(t *testing.T) {
+	var mutex sync.Mutex
+	var order []string
+
+	r := NewTaskGroupRunner()
+	r.SetConcurrency(2)
+	r.SetPostTaskRunFn(func(result map[string]interface{}) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		order = append(order, result["objectName"].(string))
+	})
+	r.AddRunTasks([]*v1alpha1.RunTask{
+		fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`),
+		fakeCommandRunTask("t2", `{{- "t2-obj" | saveAs "t2.objectName" .TaskResult | noop -}}`),
+	})
+	// t1 is executed after t2 in spite of being added first
+	r.SetTaskDependencies(map[string][]string{"t1": {"t2"}})
+
+	_, err := r.Run(context.Background(), fakeTemplateValues())
+	if err != nil {
+		t.Fatalf("failed to test run with task dependencies: expected 'no error': actual '%s'", err)
+	}
+	expected := []string{"t2-obj", "t1-obj"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Fatalf("failed to test run with task dependencies: expected '%v': actual '%v'", expected, order)
+	}
+}
+
+func TestPlannedTaskIDs(t *testing.T) {
+	tests := map[string]struct {
+		runtasks []*v1alpha1.RunTask
+		deps     map[string][]string
+		seeded   string
+		expected []string
+		isErr    bool
+	}{
+		"planned task ids - +ve test case - order of addition": {
+			runtasks: []*v1alpha1.RunTask{fakeCommandRunTask("t1", ""), fakeCommandRunTask("t2", ""), fakeCommandRunTask("t3", "")},
+			expected: []string{"t1", "t2", "t3"},
+		},
+		"planned task ids - +ve test case - order of dependencies": {
+			runtasks: []*v1alpha1.RunTask{fakeCommandRunTask("t1", ""), fakeCommandRunTask("t2", ""), fakeCommandRunTask("t3", "")},
+			deps:     map[string][]string{"t1": {"t3"}, "t2": {"t1"}},
+			expected: []string{"t3", "t1", "t2"},
+		},
+		"planned task ids - -ve test case - duplicate id": {
+			runtasks: []*v1alpha1.RunTask{fakeCommandRunTask("t1", ""), fakeCommandRunTask("T1", "")},
+			isErr:    true,
+		},
+		"planned task ids - -ve test case - seeded id": {
+			runtasks: []*v1alpha1.RunTask{fakeCommandRunTask("t1", "")},
+			seeded:   "t1",
+			isErr:    true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.AddRunTasks(mock.runtasks)
+			r.SetTaskDependencies(mock.deps)
+			if len(mock.seeded) != 0 {
+				r.SeedTaskResult(mock.seeded, map[string]interface{}{})
+			}
+
+			ids, err := r.PlannedTaskIDs(fakeTemplateValues())
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test planned task ids: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			if !reflect.DeepEqual(ids, mock.expected) {
+				t.Fatalf("failed to test planned task ids: expected '%v': actual '%v'", mock.expected, ids)
+			}
+			// nothing is executed
+			if len(r.executed) != 0 || len(r.allTaskIDs) != 0 {
+				t.Fatalf("failed to test planned task ids: expected no executed tasks: actual '%d'", len(r.executed))
+			}
+		})
+	}
+}
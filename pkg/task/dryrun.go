@@ -0,0 +1,309 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/template"
+	"github.com/openebs/maya/pkg/util"
+)
+
+// PlannedAction is the action that a task would perform against its
+// objects if it was executed
+type PlannedAction string
+
+const (
+	// CreatePA flags the objects as the ones that would be created
+	CreatePA PlannedAction = "create"
+	// PatchPA flags the objects as the ones that would be patched
+	PatchPA PlannedAction = "patch"
+	// DeletePA flags the objects as the ones that would be deleted
+	DeletePA PlannedAction = "delete"
+	// GetPA flags the objects as the ones that would be fetched
+	GetPA PlannedAction = "get"
+	// ListPA flags the objects as the ones that would be listed
+	ListPA PlannedAction = "list"
+	// WatchPA flags the objects as the ones that would be watched
+	WatchPA PlannedAction = "watch"
+)
+
+// plannedActions maps a meta task action to its planned action
+var plannedActions = map[MetaTaskAction]PlannedAction{
+	PutTA:            CreatePA,
+	PatchTA:          PatchPA,
+	PatchStrategicTA: PatchPA,
+	PatchMergeTA:     PatchPA,
+	PatchJSONTA:      PatchPA,
+	PatchCASTA:       PatchPA,
+	DeleteTA:         DeletePA,
+	GetTA:            GetPA,
+	ListTA:           ListPA,
+	WatchTA:          WatchPA,
+}
+
+// RenderedTask represents a run task whose templates were rendered against
+// the template values without executing the task
+type RenderedTask struct {
+	// Name of the run task
+	Name string
+	// Identity of the task
+	Identity string
+	// Action that the task would perform
+	Action PlannedAction
+	// ObjectNames are the names of the objects that the task would operate
+	// on; is empty if these names are known only after executing the task
+	ObjectNames []string
+	// Skipped flags if the task would be skipped due to its predicates
+	Skipped bool
+	// RenderedMeta is the meta specifications of the run task after templating
+	RenderedMeta string
+	// RenderedSpec is the task specifications of the run task after
+	// templating. This is what would have been submitted if the task was
+	// executed.
+	RenderedSpec string
+}
+
+// PlannedRollback represents a rollback step that would be registered if
+// the tasks were executed
+type PlannedRollback struct {
+	// Identity of the task that would be rolled back
+	Identity string
+	// Action that the rollback would perform
+	Action PlannedAction
+	// ObjectName is the name of the object that would be rolled back
+	ObjectName string
+	// Priority is the rollback priority of the task
+	Priority int
+}
+
+// DryRunPlan represents the plan of a task group runner i.e. what the runner
+// would do if it was run
+type DryRunPlan struct {
+	// Tasks are the rendered tasks in their order of execution
+	Tasks []RenderedTask
+	// Output is the rendered output task if any
+	//
+	// NOTE:
+	//  Multiple output tasks are rendered as a single output task whose name
+	// is the comma separated names of these output tasks. Their specifications
+	// are joined by the output separator.
+	Output *RenderedTask
+	// Rollbacks are the rollback steps that would be registered in the order
+	// they would be executed i.e. reverse of task execution order with the
+	// steps of higher rollback priority ordered first
+	Rollbacks []PlannedRollback
+}
+
+// DryRun plans all the tasks as well as the output task of this runner
+// without executing them. No kubernetes objects are created, fetched or
+// deleted while doing so.
+//
+// NOTE:
+//  Tasks of kind Command do not make any API calls. Hence their post run
+// templates are executed to report version mismatch & verification errors
+// if any. Results of these tasks are made available to the tasks that follow.
+//
+// NOTE:
+//  The provided template values are not mutated
+func (m *TaskGroupRunner) DryRun(values map[string]interface{}) (plan *DryRunPlan, err error) {
+	values = util.DeepCopyMap(values)
+	m.applySeededResults(values)
+	ids := map[string]bool{}
+	for identity := range m.seededResults {
+		ids[strings.ToLower(identity)] = true
+	}
+	plan = &DryRunPlan{}
+
+	for _, runtask := range m.allTasks {
+		rt, rollbacks, err := dryRunATask(runtask, values, ids, m.skippedGroups, m.templateFuncs)
+		if err != nil {
+			return nil, err
+		}
+		plan.Tasks = append(plan.Tasks, rt)
+		// rollbacks are executed in reverse order
+		plan.Rollbacks = append(rollbacks, plan.Rollbacks...)
+	}
+
+	sort.SliceStable(plan.Rollbacks, func(i, j int) bool {
+		return plan.Rollbacks[i].Priority > plan.Rollbacks[j].Priority
+	})
+
+	var names, metas, outputs []string
+	for _, outputTask := range m.outputTasks {
+		if len(outputTask.Spec.Task) == 0 {
+			continue
+		}
+
+		output, err := template.AsTemplatedBytesWithFuncs("Output", outputTask.Spec.Task, values, m.templateFuncs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dry run output task '%s': %s", outputTask.Name, err)
+		}
+		names = append(names, outputTask.Name)
+		metas = append(metas, outputTask.Spec.Meta)
+		outputs = append(outputs, string(output))
+	}
+
+	if len(outputs) == 0 {
+		return
+	}
+
+	separator := m.getOutputSeparator()
+	plan.Output = &RenderedTask{
+		Name:         strings.Join(names, ","),
+		RenderedMeta: strings.Join(metas, separator),
+		RenderedSpec: strings.Join(outputs, separator),
+	}
+	return
+}
+
+// dryRunATask renders the provided task against the template values & plans
+// the rollbacks that this task would register
+//
+// NOTE:
+//  ids is used to verify if this task's identity is unique
+//
+// NOTE:
+//  Task is planned as skipped if it belongs to any of the skipped groups
+//
+// NOTE:
+//  funcs are the template functions available to the task's templates in
+// addition to the ones supported by the template library
+func dryRunATask(runtask *v1alpha1.RunTask, values map[string]interface{}, ids map[string]bool, skippedGroups []string, funcs template.FuncMap) (rt RenderedTask, rollbacks []PlannedRollback, err error) {
+	rt.Name = runtask.Name
+
+	meta, err := template.AsTemplatedBytesWithFuncs("MetaTaskSpec", runtask.Spec.Meta, values, funcs)
+	if err != nil {
+		err = fmt.Errorf("failed to dry run task '%s': %s", runtask.Name, err)
+		return
+	}
+	rt.RenderedMeta = string(meta)
+
+	mts, identifier, _, err := asMetaInstances(meta)
+	if err != nil {
+		err = fmt.Errorf("failed to dry run task '%s': %s", runtask.Name, err)
+		return
+	}
+
+	// identities are compared case insensitively similar to a run
+	id := strings.ToLower(mts.Identity)
+	if ids[id] {
+		err = fmt.Errorf("failed to dry run task '%s': multiple tasks having same identity is not allowed in a group run: duplicate id '%s'", runtask.Name, mts.Identity)
+		return
+	}
+	ids[id] = true
+
+	rt.Identity = mts.Identity
+	rt.Action = plannedActions[mts.Action]
+	if strings.HasPrefix(string(mts.Action), pluginActionPrefix) {
+		// plugin actions are planned as is
+		rt.Action = PlannedAction(mts.Action)
+	}
+
+	// meta task executor without a k8s client since no API calls are made
+	mte := &metaTaskExecutor{
+		metaTask:   mts,
+		identifier: identifier,
+	}
+
+	if mte.isSkip() || mte.isSkippedGroup(skippedGroups) {
+		// task would have been skipped
+		rt.Skipped = true
+		return
+	}
+
+	if mte.isCommand() {
+		err = dryRunACommand(runtask, mte, values, funcs)
+		if err != nil {
+			return
+		}
+		rt.ObjectNames = taskObjectNames(values, mts.Identity)
+	} else {
+		var spec []byte
+		spec, err = template.AsTemplatedBytesWithFuncs("RunTask", runtask.Spec.Task, values, funcs)
+		if err != nil {
+			err = fmt.Errorf("failed to dry run task '%s': %s", runtask.Name, err)
+			return
+		}
+		rt.RenderedSpec = string(spec)
+		rt.ObjectNames = plannedObjectNames(mts, spec)
+	}
+
+	if !mte.isPut() {
+		// only put tasks are rolled back
+		return
+	}
+
+	for _, name := range rt.ObjectNames {
+		var rbSpec MetaTaskSpec
+		rbSpec, _, err = getRollbackMetaInstances(mts, name)
+		if err != nil {
+			return
+		}
+		// rollbacks of a task are executed in reverse order as well
+		rollbacks = append([]PlannedRollback{{
+			Identity:   rbSpec.Identity,
+			Action:     plannedActions[rbSpec.Action],
+			ObjectName: rbSpec.ObjectName,
+			Priority:   rbSpec.RollbackPriority,
+		}}, rollbacks...)
+	}
+	return
+}
+
+// dryRunACommand executes the post run template of the provided command
+// task; a command does not make API calls
+func dryRunACommand(runtask *v1alpha1.RunTask, mte *metaTaskExecutor, values map[string]interface{}, funcs template.FuncMap) (err error) {
+	te := &taskExecutor{
+		templateValues: values,
+		runtask:        runtask,
+		metaTaskExec:   mte,
+		funcs:          funcs,
+	}
+	err = te.postExecuteIt()
+	if err != nil {
+		// version mismatch error is returned as is so that it can be checked
+		// by the caller
+		return
+	}
+
+	// verification error of a command is a failure as well
+	if verifyErr, ok := te.getTaskResultVerifyError().(*template.VerifyError); ok {
+		err = verifyErr
+	}
+	return
+}
+
+// plannedObjectNames returns the names of the objects the task would operate
+// on. Object name set in meta specifications is preferred over the name set
+// in the rendered task specifications.
+func plannedObjectNames(mts MetaTaskSpec, spec []byte) []string {
+	if len(mts.ObjectName) != 0 {
+		return splitObjectNames(mts.ObjectName)
+	}
+
+	var obj map[string]interface{}
+	err := yaml.Unmarshal(spec, &obj)
+	if err != nil {
+		return nil
+	}
+	return splitObjectNames(util.GetNestedString(obj, "metadata", "name"))
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/template"
+)
+
+func fakeServiceRunTask(id, spec string) *v1alpha1.RunTask {
+	r := &v1alpha1.RunTask{}
+	r.Name = id
+	r.Spec.Meta = "id: " + id + "\napiVersion: v1\nkind: Service\naction: put"
+	r.Spec.Task = spec
+	return r
+}
+
+func TestDryRun(t *testing.T) {
+	tests := map[string]struct {
+		runtasks          []*v1alpha1.RunTask
+		expectedCount     int
+		expectedSpec      string
+		isErr             bool
+		isVersionMismatch bool
+	}{
+		"dry run - +ve test case - command result used by next task": {
+			runtasks: []*v1alpha1.RunTask{
+				fakeCommandRunTask("t1", `{{- "my-svc" | saveAs "t1.name" .TaskResult | noop -}}`),
+				fakeServiceRunTask("t2", `name: {{ .TaskResult.t1.name }}`),
+			},
+			expectedCount: 2,
+			expectedSpec:  "name: my-svc",
+		},
+		"dry run - -ve test case - invalid task template": {
+			runtasks: []*v1alpha1.RunTask{
+				fakeServiceRunTask("t1", `name: {{ .TaskResult.t1.name`),
+			},
+			isErr: true,
+		},
+		"dry run - -ve test case - version mismatch": {
+			runtasks: []*v1alpha1.RunTask{
+				fakeCommandRunTask("t1", `{{- true | versionMismatchErr "not supported" | saveIf "t1.versionMismatchErr" .TaskResult | noop -}}`),
+			},
+			isErr:             true,
+			isVersionMismatch: true,
+		},
+		"dry run - -ve test case - duplicate ids": {
+			runtasks: []*v1alpha1.RunTask{
+				fakeServiceRunTask("t1", `name: svc`),
+				fakeServiceRunTask("t1", `name: svc`),
+			},
+			isErr: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			for _, rt := range mock.runtasks {
+				r.AddRunTask(rt)
+			}
+
+			values := fakeTemplateValues()
+			plan, err := r.DryRun(values)
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("failed to test dry run: expected 'error': actual 'no error'")
+				}
+				if mock.isVersionMismatch && !template.IsVersionMismatch(err) {
+					t.Fatalf("failed to test dry run: expected 'version mismatch error': actual '%s'", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to test dry run: expected 'no error': actual '%s'", err)
+			}
+
+			rendered := plan.Tasks
+			if len(rendered) != mock.expectedCount {
+				t.Fatalf("failed to test dry run: expected rendered tasks '%d': actual '%d'", mock.expectedCount, len(rendered))
+			}
+			if strings.TrimSpace(rendered[len(rendered)-1].RenderedSpec) != mock.expectedSpec {
+				t.Fatalf("failed to test dry run: expected spec '%s': actual '%s'", mock.expectedSpec, rendered[len(rendered)-1].RenderedSpec)
+			}
+			if len(values[string(v1alpha1.TaskResultTLP)].(map[string]interface{})) != 0 {
+				t.Fatalf("failed to test dry run: expected template values not to be mutated")
+			}
+		})
+	}
+}
+
+func TestDryRunPlan(t *testing.T) {
+	r := NewTaskGroupRunner()
+	r.AddRunTask(fakeCommandRunTask("t1", `{{- "vol-1" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+	r.AddRunTask(fakeServiceRunTask("t2", "metadata:\n  name: {{ .TaskResult.t1.objectName }}-svc"))
+	del := fakeServiceRunTask("t3", "")
+	del.Spec.Meta = "id: t3\napiVersion: v1\nkind: Service\naction: delete\nobjectName: old-svc"
+	r.AddRunTask(del)
+
+	plan, err := r.DryRun(fakeTemplateValues())
+	if err != nil {
+		t.Fatalf("failed to test dry run plan: expected 'no error': actual '%s'", err)
+	}
+
+	expectedTasks := []struct {
+		action PlannedAction
+		names  []string
+	}{
+		{CreatePA, []string{"vol-1"}},
+		{CreatePA, []string{"vol-1-svc"}},
+		{DeletePA, []string{"old-svc"}},
+	}
+	for idx, expected := range expectedTasks {
+		actual := plan.Tasks[idx]
+		if actual.Action != expected.action || !reflect.DeepEqual(actual.ObjectNames, expected.names) {
+			t.Fatalf("failed to test dry run plan: expected task '%d' to '%s' '%v': actual '%s' '%v'", idx, expected.action, expected.names, actual.Action, actual.ObjectNames)
+		}
+	}
+
+	expectedRollbacks := []PlannedRollback{
+		{Identity: "t2", Action: DeletePA, ObjectName: "vol-1-svc"},
+		{Identity: "t1", Action: DeletePA, ObjectName: "vol-1"},
+	}
+	if !reflect.DeepEqual(plan.Rollbacks, expectedRollbacks) {
+		t.Fatalf("failed to test dry run plan: expected rollbacks '%+v': actual '%+v'", expectedRollbacks, plan.Rollbacks)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"time"
+)
+
+// TaskEventRecorder abstracts recording of the events that occur while a
+// task group runner executes its tasks
+//
+// NOTE:
+//  Implementations should be safe for concurrent use if tasks are executed
+// in parallel
+type TaskEventRecorder interface {
+	// RecordStart records the start of a task's execution
+	RecordStart(taskID string)
+	// RecordEnd records the end of a task's execution
+	RecordEnd(taskID string, err error, dur time.Duration)
+	// RecordRollback records the rollback of a task
+	RecordRollback(taskID string, err error)
+}
+
+// TaskEventType represents the type of a task event
+type TaskEventType string
+
+const (
+	// TaskStartedEvent is emitted when the execution of a task starts
+	TaskStartedEvent TaskEventType = "TaskStarted"
+	// TaskSucceededEvent is emitted when a task is executed successfully
+	TaskSucceededEvent TaskEventType = "TaskSucceeded"
+	// TaskFailedEvent is emitted when the execution of a task fails
+	TaskFailedEvent TaskEventType = "TaskFailed"
+	// RollbackStartedEvent is emitted when the rollback of the executed tasks
+	// starts
+	RollbackStartedEvent TaskEventType = "RollbackStarted"
+	// RollbackCompletedEvent is emitted when the rollback of the executed
+	// tasks completes
+	RollbackCompletedEvent TaskEventType = "RollbackCompleted"
+)
+
+// TaskEvent represents the progress of a task group runner
+type TaskEvent struct {
+	// Type of the event
+	Type TaskEventType
+	// TaskName is the name of the run task; is not set for rollback events
+	TaskName string
+	// TaskID is the identity of the task; is not set for rollback events
+	TaskID string
+	// Duration taken by the task or the rollback; is not set for start
+	// events
+	Duration time.Duration
+	// Err is the error if any that resulted from the task or the rollback
+	Err error
+}
+
+// WithEventChannel sets the channel to which the events that occur while
+// running the tasks are sent. This lets the callers stream the progress of
+// this runner.
+//
+// NOTE:
+//  Events are sent synchronously. Hence the channel should be buffered
+// sufficiently or be drained concurrently to avoid blocking the runner.
+func (m *TaskGroupRunner) WithEventChannel(ch chan<- TaskEvent) {
+	m.eventCh = ch
+}
+
+// emit sends the provided event to the event channel if there is one
+func (m *TaskGroupRunner) emit(event TaskEvent) {
+	if m.eventCh == nil {
+		return
+	}
+	m.eventCh <- event
+}
+
+// SetEventRecorder sets the recorder of the events that occur while executing
+// the tasks
+func (m *TaskGroupRunner) SetEventRecorder(r TaskEventRecorder) {
+	m.eventRecorder = r
+}
+
+// recordStart records the start of a task's execution if there is a recorder
+func (m *TaskGroupRunner) recordStart(name, taskID string) {
+	m.emit(TaskEvent{Type: TaskStartedEvent, TaskName: name, TaskID: taskID})
+
+	if m.eventRecorder == nil {
+		return
+	}
+	m.eventRecorder.RecordStart(taskID)
+}
+
+// recordEnd records the end of a task's execution if there is a recorder
+func (m *TaskGroupRunner) recordEnd(name, taskID string, err error, dur time.Duration) {
+	event := TaskEvent{Type: TaskSucceededEvent, TaskName: name, TaskID: taskID, Duration: dur, Err: err}
+	if err != nil {
+		event.Type = TaskFailedEvent
+	}
+	m.emit(event)
+
+	if m.eventRecorder == nil {
+		return
+	}
+	m.eventRecorder.RecordEnd(taskID, err, dur)
+}
+
+// recordRollback records the rollback of a task if there is a recorder
+func (m *TaskGroupRunner) recordRollback(taskID string, err error) {
+	if m.eventRecorder == nil {
+		return
+	}
+	m.eventRecorder.RecordRollback(taskID, err)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeEventRecorder records the events as strings
+type fakeEventRecorder struct {
+	events []string
+}
+
+func (r *fakeEventRecorder) RecordStart(taskID string) {
+	r.events = append(r.events, "start:"+taskID)
+}
+
+func (r *fakeEventRecorder) RecordEnd(taskID string, err error, dur time.Duration) {
+	if err != nil {
+		r.events = append(r.events, "fail:"+taskID)
+		return
+	}
+	r.events = append(r.events, "end:"+taskID)
+}
+
+func (r *fakeEventRecorder) RecordRollback(taskID string, err error) {
+	r.events = append(r.events, "rollback:"+taskID)
+}
+
+func TestEventRecorder(t *testing.T) {
+	tests := map[string]struct {
+		posts    []string
+		expected []string
+	}{
+		"event recorder - +ve test case - all tasks succeed": {
+			posts: []string{
+				`{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+				`{{- "t2-obj" | saveAs "t2.objectName" .TaskResult | noop -}}`,
+			},
+			expected: []string{"start:t1", "end:t1", "start:t2", "end:t2"},
+		},
+		"event recorder - -ve test case - second task fails": {
+			posts: []string{
+				`{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+				`{{- fail "t2 failed" -}}`,
+			},
+			expected: []string{"start:t1", "end:t1", "start:t2", "fail:t2", "rollback:t1"},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := &fakeEventRecorder{}
+			r := NewTaskGroupRunner()
+			r.SetEventRecorder(rec)
+			for idx, post := range mock.posts {
+				r.AddRunTask(fakeCommandRunTask(fmt.Sprintf("t%d", idx+1), post))
+			}
+
+			r.Run(context.Background(), fakeTemplateValues())
+			if !reflect.DeepEqual(rec.events, mock.expected) {
+				t.Fatalf("failed to test event recorder: expected '%v': actual '%v'", mock.expected, rec.events)
+			}
+		})
+	}
+}
+
+func TestEventChannel(t *testing.T) {
+	tests := map[string]struct {
+		posts    []string
+		expected []string
+	}{
+		"event channel - +ve test case - all tasks succeed": {
+			posts: []string{
+				`{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+				`{{- "t2-obj" | saveAs "t2.objectName" .TaskResult | noop -}}`,
+			},
+			expected: []string{"TaskStarted:t1", "TaskSucceeded:t1", "TaskStarted:t2", "TaskSucceeded:t2"},
+		},
+		"event channel - -ve test case - second task fails": {
+			posts: []string{
+				`{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+				`{{- fail "t2 failed" -}}`,
+			},
+			expected: []string{"TaskStarted:t1", "TaskSucceeded:t1", "TaskStarted:t2", "TaskFailed:t2", "RollbackStarted:", "RollbackCompleted:"},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			ch := make(chan TaskEvent, 10)
+			r := NewTaskGroupRunner()
+			r.WithEventChannel(ch)
+			for idx, post := range mock.posts {
+				r.AddRunTask(fakeCommandRunTask(fmt.Sprintf("t%d", idx+1), post))
+			}
+
+			r.Run(context.Background(), fakeTemplateValues())
+			close(ch)
+
+			var actual []string
+			for event := range ch {
+				actual = append(actual, fmt.Sprintf("%s:%s", event.Type, event.TaskName))
+			}
+			if !reflect.DeepEqual(actual, mock.expected) {
+				t.Fatalf("failed to test event channel: expected '%v': actual '%v'", mock.expected, actual)
+			}
+		})
+	}
+}
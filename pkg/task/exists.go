@@ -0,0 +1,79 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// getExistingFn returns the function that fetches the object of this put
+// task as raw bytes
+func (m *taskExecutor) getExistingFn() (get func(name string) ([]byte, error), err error) {
+	c := m.getK8sClient()
+	switch {
+	case m.metaTaskExec.isPutExtnV1B1Deploy():
+		get = c.GetExtnV1B1DeploymentAsRaw
+	case m.metaTaskExec.isPutAppsV1B1Deploy():
+		get = c.GetAppsV1B1DeploymentAsRaw
+	case m.metaTaskExec.isPutCoreV1Service():
+		get = c.GetCoreV1ServiceAsRaw
+	case m.metaTaskExec.isPutOEV1alpha1SP():
+		get = c.GetOEV1alpha1SPAsRaw
+	case m.metaTaskExec.isPutOEV1alpha1CSP():
+		get = c.GetOEV1alpha1CSPAsRaw
+	case m.metaTaskExec.isPutOEV1alpha1CSV():
+		get = c.GetOEV1alpha1CVAsRaw
+	case m.metaTaskExec.isPutOEV1alpha1CVR():
+		get = c.GetOEV1alpha1CVRAsRaw
+	default:
+		err = fmt.Errorf("failed to verify if object exists: ifNotExists is not supported: task '%s'", m.getTaskIdentity())
+	}
+	return
+}
+
+// getExistingObject fetches the object of this put task. It returns true if
+// the object exists & sets the existing object at .JsonResult
+//
+// NOTE:
+//  The object is identified by the objectName set in meta specifications
+func (m *taskExecutor) getExistingObject() (exists bool, err error) {
+	name := strings.TrimSpace(m.getTaskObjectName())
+	if len(name) == 0 {
+		return false, fmt.Errorf("failed to verify if object exists: objectName is required with ifNotExists: task '%s'", m.getTaskIdentity())
+	}
+
+	get, err := m.getExistingFn()
+	if err != nil {
+		return
+	}
+
+	obj, err := get(name)
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to verify if object '%s' exists: task '%s': %s", name, m.getTaskIdentity(), err)
+	}
+
+	util.SetNestedField(m.templateValues, obj, string(v1alpha1.CurrentJSONResultTLP))
+	return true, nil
+}
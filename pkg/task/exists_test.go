@@ -0,0 +1,97 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+// fakeServiceAPIServer serves the services API. Only the service named
+// existing-svc exists; created services are echoed back.
+func fakeServiceAPIServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/namespaces/default/services/existing-svc":
+			w.Write([]byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"existing-svc","namespace":"default"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/namespaces/default/services":
+			body, _ := ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"apiVersion":"v1","kind":"Status","status":"Failure","reason":"NotFound","code":404}`))
+		}
+	}))
+}
+
+func TestIfNotExists(t *testing.T) {
+	server := fakeServiceAPIServer()
+	defer server.Close()
+
+	defer setK8sMaster(server.URL)()
+
+	tests := map[string]struct {
+		objectName        string
+		expectedStatus    TaskStatus
+		expectedRollbacks int
+	}{
+		"if not exists - +ve test case - object exists": {
+			objectName:     "existing-svc",
+			expectedStatus: TaskSkipped,
+		},
+		"if not exists - +ve test case - object does not exist": {
+			objectName:        "missing-svc",
+			expectedStatus:    TaskSucceeded,
+			expectedRollbacks: 1,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			runtask := &v1alpha1.RunTask{}
+			runtask.Name = "putsvc"
+			runtask.Spec.Meta = "id: putsvc\napiVersion: v1\nkind: Service\naction: put\nrunNamespace: default\nifNotExists: true\nobjectName: " + mock.objectName
+			runtask.Spec.Task = "apiVersion: v1\nkind: Service\nmetadata:\n  name: " + mock.objectName
+			runtask.Spec.PostRun = `{{- jsonpath .JsonResult "{.metadata.name}" | trim | saveAs "putsvc.objectName" .TaskResult | noop -}}`
+
+			r := NewTaskGroupRunner()
+			r.AddRunTask(runtask)
+			values := fakeTemplateValues()
+			result := r.RunWithReport(values)
+			if result.Err != nil {
+				t.Fatalf("failed to test if not exists: expected 'no error': actual '%s'", result.Err)
+			}
+
+			if len(result.ExecutedTasks) != 1 || result.ExecutedTasks[0].Status != mock.expectedStatus {
+				t.Fatalf("failed to test if not exists: expected status '%s': actual '%+v'", mock.expectedStatus, result.ExecutedTasks)
+			}
+			if len(r.rollbacks.planned) != mock.expectedRollbacks {
+				t.Fatalf("failed to test if not exists: expected '%d' rollbacks: actual '%d'", mock.expectedRollbacks, len(r.rollbacks.planned))
+			}
+			objectName, _ := values[string(v1alpha1.TaskResultTLP)].(map[string]interface{})["putsvc"].(map[string]interface{})["objectName"].(string)
+			if objectName != mock.objectName {
+				t.Fatalf("failed to test if not exists: expected object name '%s': actual '%s'", mock.objectName, objectName)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openebs/maya/pkg/template"
+	"github.com/openebs/maya/pkg/util"
+)
+
+// explainedTask is the explanation of a task of a runner
+type explainedTask struct {
+	// Index of the task in the order of execution
+	Index int `json:"index"`
+	// Name of the run task
+	Name string `json:"name"`
+	// Identity of the task
+	Identity string `json:"identity,omitempty"`
+	// Action of the task
+	Action MetaTaskAction `json:"action,omitempty"`
+	// Kind of the object the task operates on
+	Kind string `json:"kind,omitempty"`
+	// ObjectName is the name of the object the task operates on
+	ObjectName string `json:"objectName,omitempty"`
+	// Namespace where the task is executed
+	Namespace string `json:"namespace,omitempty"`
+	// RunIf is the evaluated run if predicate of the task
+	RunIf string `json:"runIf,omitempty"`
+	// Skipped flags if the task would be skipped due to its predicates
+	Skipped bool `json:"skipped"`
+	// SkipReason is the reason the task would be skipped
+	SkipReason string `json:"skipReason,omitempty"`
+	// Error is the error in rendering the meta specifications of the task
+	Error string `json:"error,omitempty"`
+}
+
+// explanation is the explanation of a runner
+type explanation struct {
+	Tasks []explainedTask `json:"tasks"`
+}
+
+// Explain returns a yaml document that explains the tasks of this runner in
+// their order of execution. Meta specifications of each task are rendered
+// against the provided template values to report the task's identity,
+// action, kind, object name, namespace & its run if predicate. None of the
+// tasks are executed.
+//
+// Example:
+//  tasks:
+//  - action: put
+//    identity: createsvc
+//    index: 0
+//    kind: Service
+//    name: cstor-volume-create-service
+//    namespace: openebs
+//    runIf: "true"
+//    skipped: false
+//
+// NOTE:
+//  Tasks are not executed. Hence the meta specifications that depend on the
+// results of the previous tasks may not be rendered as they would be in a
+// run. A task whose meta specifications can not be rendered is explained
+// with the error & rest of the tasks are explained as usual.
+//
+// NOTE:
+//  The provided template values are not mutated
+func (m *TaskGroupRunner) Explain(values map[string]interface{}) (string, error) {
+	values = util.DeepCopyMap(values)
+	m.applySeededResults(values)
+
+	e := explanation{Tasks: []explainedTask{}}
+	for idx, runtask := range m.allTasks {
+		task := explainedTask{Index: idx, Name: runtask.Name}
+
+		meta, err := template.AsTemplatedBytesWithFuncs("MetaTaskSpec", runtask.Spec.Meta, values, m.templateFuncs)
+		var mts MetaTaskSpec
+		if err == nil {
+			mts, _, _, err = asMetaInstances(meta)
+		}
+		if err != nil {
+			task.Error = err.Error()
+			e.Tasks = append(e.Tasks, task)
+			continue
+		}
+
+		mte := &metaTaskExecutor{metaTask: mts}
+		task.Identity = mts.Identity
+		task.Action = mts.Action
+		task.Kind = mts.Kind
+		task.ObjectName = mts.ObjectName
+		task.Namespace = mts.RunNamespace
+		task.RunIf = string(mts.RunIf)
+		task.SkipReason = mte.skipReason()
+		if len(task.SkipReason) == 0 && mte.isSkippedGroup(m.skippedGroups) {
+			task.SkipReason = "its group '" + mts.GroupLabel + "' is skipped"
+		}
+		task.Skipped = len(task.SkipReason) != 0
+		e.Tasks = append(e.Tasks, task)
+	}
+
+	b, err := yaml.Marshal(e)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to explain runtasks")
+	}
+	return string(b), nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+func TestExplain(t *testing.T) {
+	svc := &v1alpha1.RunTask{}
+	svc.Name = "create-svc"
+	svc.Spec.Meta = "id: createsvc\napiVersion: v1\nkind: Service\naction: put\nrunNamespace: {{ .Volume.runNamespace }}\nobjectName: {{ .Volume.owner }}-svc"
+	svc.Spec.Task = `{{- fail "task is not rendered" -}}`
+
+	skipped := fakeCommandRunTask("cmd", `{{- fail "command is not executed" -}}`)
+	skipped.Spec.Meta = skipped.Spec.Meta + "\nrunIf: {{ eq .Volume.owner \"none\" }}"
+
+	invalid := fakeCommandRunTask("invalid", "")
+	invalid.Spec.Meta = `{{- fail "invalid meta" -}}`
+
+	r := NewTaskGroupRunner()
+	r.AddRunTask(svc)
+	r.AddRunTask(skipped)
+	r.AddRunTask(invalid)
+
+	values := fakeTemplateValues()
+	values["Volume"] = map[string]interface{}{"runNamespace": "openebs", "owner": "vol1"}
+
+	explained, err := r.Explain(values)
+	if err != nil {
+		t.Fatalf("failed to test explain: expected no error: actual '%+v'", err)
+	}
+	again, _ := r.Explain(values)
+	if explained != again {
+		t.Fatalf("failed to test explain: expected stable explanation '%s': actual '%s'", explained, again)
+	}
+
+	var e explanation
+	err = yaml.Unmarshal([]byte(explained), &e)
+	if err != nil {
+		t.Fatalf("failed to test explain: expected yaml: actual '%+v': explanation '%s'", err, explained)
+	}
+	if len(e.Tasks) != 3 {
+		t.Fatalf("failed to test explain: expected '3' tasks: actual '%d': explanation '%s'", len(e.Tasks), explained)
+	}
+
+	expected := explainedTask{
+		Index:      0,
+		Name:       "create-svc",
+		Identity:   "createsvc",
+		Action:     PutTA,
+		Kind:       "Service",
+		ObjectName: "vol1-svc",
+		Namespace:  "openebs",
+	}
+	if !reflect.DeepEqual(e.Tasks[0], expected) {
+		t.Fatalf("failed to test explain: expected task '%+v': actual '%+v'", expected, e.Tasks[0])
+	}
+	if !e.Tasks[1].Skipped || e.Tasks[1].RunIf != "false" || e.Tasks[1].Index != 1 {
+		t.Fatalf("failed to test explain: expected task 'cmd' to be skipped: actual '%+v'", e.Tasks[1])
+	}
+	if len(e.Tasks[2].Error) == 0 || e.Tasks[2].Name != "invalid" {
+		t.Fatalf("failed to test explain: expected task 'invalid' to report error: actual '%+v'", e.Tasks[2])
+	}
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package task
 
 import (
+	"context"
 	"fmt"
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
 	"github.com/openebs/maya/pkg/client/k8s"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -65,6 +67,19 @@ func WithTaskFetcher(namespace string) RunOptionsMiddleware {
 	}
 }
 
+// WithTaskSpecFetcher updates the given RunOptions instance with the
+// provided task fetcher instance e.g. a RunTaskCache
+func WithTaskSpecFetcher(fetcher TaskSpecFetcher) RunOptionsMiddleware {
+	return func(given *RunOptions) (updated *RunOptions, err error) {
+		if fetcher == nil {
+			err = fmt.Errorf("nil task fetcher: failed to update task runner with task fetcher")
+			return
+		}
+		given.taskFetcher = fetcher
+		return given, nil
+	}
+}
+
 // WithRunTaskList updates the given RunOptions instance with list of RunTasks
 func WithRunTaskList(taskNames []string) RunOptionsMiddleware {
 	return func(given *RunOptions) (updated *RunOptions, err error) {
@@ -127,6 +142,16 @@ func WithOutputTask(taskName string) RunOptionsMiddleware {
 
 // NewFallbackRunner returns a new instance of task group runner
 func NewFallbackRunner(template string, values map[string]interface{}) (*RunOptions, error) {
+	cast, err := getFallbackCAST(template)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFallbackRunner(cast, values, nil)
+}
+
+// getFallbackCAST fetches the fallback CAS Template
+func getFallbackCAST(template string) (*v1alpha1.CASTemplate, error) {
 	if len(strings.TrimSpace(template)) == 0 {
 		return nil, fmt.Errorf("missing fallback template name: failed to create fallback runner")
 	}
@@ -141,11 +166,23 @@ func NewFallbackRunner(template string, values map[string]interface{}) (*RunOpti
 		return nil, errors.Wrapf(err, "failed to create fallback runner")
 	}
 
+	return cast, nil
+}
+
+// newFallbackRunner returns a new instance of task group runner based on the
+// provided fallback CAS Template. Runtasks are fetched via the provided task
+// fetcher if set or from the cluster otherwise.
+func newFallbackRunner(cast *v1alpha1.CASTemplate, values map[string]interface{}, fetcher TaskSpecFetcher) (*RunOptions, error) {
 	options := &RunOptions{values: values}
 
-	options, err = UpdateTaskRunner(
+	withFetcher := WithTaskFetcher(cast.Spec.TaskNamespace)
+	if fetcher != nil {
+		withFetcher = WithTaskSpecFetcher(fetcher)
+	}
+
+	options, err := UpdateTaskRunner(
 		[]RunOptionsMiddleware{
-			WithTaskFetcher(cast.Spec.TaskNamespace),
+			withFetcher,
 			WithRunTaskList(cast.Spec.RunTasks.Tasks),
 			WithOutputTask(cast.Spec.OutputTask),
 		})(options)
@@ -157,6 +194,6 @@ func NewFallbackRunner(template string, values map[string]interface{}) (*RunOpti
 }
 
 // RunFallback executes the fallback tasks
-func RunFallback(options *RunOptions) (output []byte, err error) {
-	return options.Run(options.values)
+func RunFallback(ctx context.Context, options *RunOptions) (output []byte, err error) {
+	return options.Run(ctx, options.values)
 }
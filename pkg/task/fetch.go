@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/golang/glog"
 	"github.com/pkg/errors"
 
 	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
@@ -179,7 +178,7 @@ func (g *getRunTask) Get() (runtask *v1alpha1.RunTask, err error) {
 		}
 
 		err = errors.Wrap(err, fmt.Sprintf("failed to get runtask '%s'", g.taskName))
-		glog.Warningf("%s", err)
+		packageLogger().Error(err, "failed to fetch runtask")
 	}
 
 	// at this point, we have a real error we can not recover from
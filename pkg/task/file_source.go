@@ -0,0 +1,151 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+// FileTaskSource fetches the runtasks stored as yaml files e.g. the files
+// of a ConfigMap mounted as a volume. Each file holds a RunTask. A runtask is
+// identified by its metadata name or by its file name without the extension
+// if the metadata name is not set.
+//
+// Example:
+//  apiVersion: openebs.io/v1alpha1
+//  kind: RunTask
+//  metadata:
+//    name: cstor-volume-create-service
+//  spec:
+//    meta: |
+//      id: createsvc
+//      ...
+//
+// NOTE:
+//  This is an implementation of TaskSpecFetcher
+type FileTaskSource struct {
+	// Path is a file, a directory or a glob pattern of the files that hold
+	// the runtasks. Directories are traversed recursively & only their
+	// files having .yaml or .yml extension are considered.
+	Path string
+}
+
+// Fetch returns the runtask with the provided name. Files are read on each
+// fetch so that the updates to the mounted files are picked up.
+//
+// NOTE:
+//  This is an implementation of TaskSpecFetcher interface
+func (s *FileTaskSource) Fetch(taskName string) (runtask *v1alpha1.RunTask, err error) {
+	if len(strings.TrimSpace(taskName)) == 0 {
+		return nil, fmt.Errorf("missing run task name: failed to get runtask from files")
+	}
+
+	files, err := s.files()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get runtask '%s' from files at '%s'", taskName, s.Path)
+	}
+
+	var found string
+	for _, file := range files {
+		rt, err := readRunTaskFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get runtask '%s' from files at '%s'", taskName, s.Path)
+		}
+		if rt.Name != taskName {
+			continue
+		}
+		if runtask != nil {
+			return nil, fmt.Errorf("failed to get runtask '%s' from files at '%s': duplicate runtask found in '%s' and '%s'", taskName, s.Path, found, file)
+		}
+		runtask, found = rt, file
+	}
+
+	if runtask == nil {
+		return nil, fmt.Errorf("failed to get runtask '%s' from files at '%s': runtask not found", taskName, s.Path)
+	}
+	return
+}
+
+// files returns the yaml files found at this source's path in a sorted order
+func (s *FileTaskSource) files() (files []string, err error) {
+	matches, err := filepath.Glob(s.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid path")
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no files found")
+	}
+
+	for _, match := range matches {
+		err = filepath.Walk(match, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			// a file that is matched explicitly is considered as is
+			if path == match || isYAMLFile(path) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(files)
+	return
+}
+
+// isYAMLFile flags if the provided file has a yaml extension
+func isYAMLFile(file string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// readRunTaskFile returns the runtask held by the provided file. Name of
+// the file without its extension is set as the runtask's name if the
+// runtask does not have one.
+func readRunTaskFile(file string) (*v1alpha1.RunTask, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	runtask := &v1alpha1.RunTask{}
+	err = yaml.Unmarshal(b, runtask)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid runtask in '%s'", file)
+	}
+	if len(runtask.Name) == 0 {
+		base := filepath.Base(file)
+		runtask.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	return runtask, nil
+}
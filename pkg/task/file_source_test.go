@@ -0,0 +1,162 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeRunTaskFiles writes the provided files relative to a new temporary
+// directory & returns this directory
+func fakeRunTaskFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "runtasks")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %+v", err)
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		err = os.MkdirAll(filepath.Dir(file), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(file, []byte(content), 0644)
+		}
+		if err != nil {
+			t.Fatalf("failed to write file '%s': %+v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestFileTaskSourceFetch(t *testing.T) {
+	files := map[string]string{
+		"valid/create-svc.yaml":         "apiVersion: openebs.io/v1alpha1\nkind: RunTask\nmetadata:\n  name: cstor-create-svc\nspec:\n  meta: |\n    id: createsvc\n",
+		"valid/nested/list-pods.yml":    "spec:\n  meta: |\n    id: listpods\n",
+		"valid/nested/deep/output.yaml": "spec:\n  meta: |\n    id: output\n  task: '{{ .Volume.owner }}'\n",
+		"valid/nested/README.md":        "not a runtask",
+		"dup/one.yaml":                  "metadata:\n  name: dup\nspec:\n  meta: 'id: one'\n",
+		"dup/two.yaml":                  "metadata:\n  name: dup\nspec:\n  meta: 'id: two'\n",
+		"invalid/bad.yaml":              "spec: [",
+	}
+	dir := fakeRunTaskFiles(t, files)
+	defer os.RemoveAll(dir)
+	valid := filepath.Join(dir, "valid")
+
+	tests := map[string]struct {
+		path         string
+		taskName     string
+		expectedMeta string
+		isErr        bool
+	}{
+		"file task source - +ve test case - metadata name": {
+			path:         valid,
+			taskName:     "cstor-create-svc",
+			expectedMeta: "id: createsvc\n",
+		},
+		"file task source - +ve test case - file name in nested directory": {
+			path:         valid,
+			taskName:     "list-pods",
+			expectedMeta: "id: listpods\n",
+		},
+		"file task source - +ve test case - deeply nested directory": {
+			path:         filepath.Join(valid, "nested"),
+			taskName:     "output",
+			expectedMeta: "id: output\n",
+		},
+		"file task source - +ve test case - glob pattern": {
+			path:         filepath.Join(valid, "*.yaml"),
+			taskName:     "cstor-create-svc",
+			expectedMeta: "id: createsvc\n",
+		},
+		"file task source - +ve test case - single file": {
+			path:         filepath.Join(valid, "nested", "list-pods.yml"),
+			taskName:     "list-pods",
+			expectedMeta: "id: listpods\n",
+		},
+		"file task source - -ve test case - runtask not found": {
+			path:     filepath.Join(valid, "nested"),
+			taskName: "cstor-create-svc",
+			isErr:    true,
+		},
+		"file task source - -ve test case - duplicate runtasks": {
+			path:     filepath.Join(dir, "dup"),
+			taskName: "dup",
+			isErr:    true,
+		},
+		"file task source - -ve test case - invalid file": {
+			path:     filepath.Join(dir, "invalid"),
+			taskName: "bad",
+			isErr:    true,
+		},
+		"file task source - -ve test case - missing path": {
+			path:     filepath.Join(dir, "missing"),
+			taskName: "list-pods",
+			isErr:    true,
+		},
+		"file task source - -ve test case - empty task name": {
+			path:  valid,
+			isErr: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &FileTaskSource{Path: mock.path}
+			runtask, err := s.Fetch(mock.taskName)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test file task source: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			if mock.isErr {
+				return
+			}
+			if runtask.Name != mock.taskName || runtask.Spec.Meta != mock.expectedMeta {
+				t.Fatalf("failed to test file task source: expected runtask '%s' with meta '%s': actual '%s' with meta '%s'", mock.taskName, mock.expectedMeta, runtask.Name, runtask.Spec.Meta)
+			}
+		})
+	}
+}
+
+func TestBuilderWithTaskSource(t *testing.T) {
+	dir := fakeRunTaskFiles(t, map[string]string{
+		"t1.yaml": "spec:\n  meta: |\n    id: t1\n    kind: Command\n    action: put\n  post: '{{- \"node-1\" | saveAs \"t1.node\" .TaskResult | noop -}}'\n",
+		"o1.yaml": "spec:\n  meta: |\n    id: o1\n    kind: Command\n    action: output\n  task: '{{ nestedString .TaskResult \"t1\" \"node\" }}'\n",
+	})
+	defer os.RemoveAll(dir)
+
+	r, err := NewTaskGroupRunnerBuilder().
+		WithTaskSource(&FileTaskSource{Path: dir}).
+		WithRunTaskNamed("t1").
+		WithOutputTaskNamed("o1").
+		Build()
+	if err != nil {
+		t.Fatalf("failed to test builder with task source: expected no error: actual '%+v'", err)
+	}
+	output, err := r.Run(context.Background(), fakeTemplateValues())
+	if err != nil || string(output) != "node-1" {
+		t.Fatalf("failed to test builder with task source: expected output 'node-1': actual '%s': error '%v'", output, err)
+	}
+
+	_, err = NewTaskGroupRunnerBuilder().
+		WithRunTaskNamed("t1").
+		Build()
+	if err == nil || !strings.Contains(err.Error(), "nil task source") {
+		t.Fatalf("failed to test builder with task source: expected nil task source error: actual '%v'", err)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/template"
+	"github.com/openebs/maya/pkg/util"
+)
+
+// RunTask executes only the task having the provided identity followed by
+// the output tasks of this runner. This is meant to step through the tasks
+// while debugging or to re-execute a task that failed in an otherwise
+// completed run.
+//
+// NOTE:
+//  The provided values are mutated similar to Run. These are expected to
+// hold the results of the tasks this task depends on.
+//
+// NOTE:
+//  Tasks are indexed by their identities on the first invocation. Meta
+// specifications of all the tasks are rendered against the provided values
+// to build this index.
+//
+// NOTE:
+//  Only the objects created by this task are rolled back if this task fails.
+// Rollbacks planned by a previous run of this runner are not affected.
+func (m *TaskGroupRunner) RunTask(id string, values map[string]interface{}) (output []byte, err error) {
+	runtask, err := m.taskByID(id, values)
+	if err != nil {
+		return nil, err
+	}
+
+	// this task is executed independent of a previous run of this runner
+	ids := m.allTaskIDs
+	m.allTaskIDs = nil
+	rollbacks := m.rollbacks.replace(nil)
+	defer func() {
+		m.allTaskIDs = ids
+		m.rollbacks.replace(rollbacks)
+	}()
+
+	ctx := context.Background()
+	err = m.runATask(ctx, runtask, values)
+	if err != nil {
+		rollbackErr := m.rollback(ctx)
+		if rollbackErr != nil {
+			err = &RollbackError{Err: err, RollbackErr: rollbackErr}
+		}
+		return nil, err
+	}
+	return m.runOutput(ctx, values)
+}
+
+// taskByID returns the run task having the provided identity. Identities are
+// matched case insensitively similar to a run.
+func (m *TaskGroupRunner) taskByID(id string, values map[string]interface{}) (*v1alpha1.RunTask, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.taskIndex == nil {
+		index, err := m.buildTaskIndex(values)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to run task '%s'", id)
+		}
+		m.taskIndex = index
+	}
+
+	runtask, found := m.taskIndex[strings.ToLower(id)]
+	if !found {
+		return nil, fmt.Errorf("failed to run task '%s': task not found", id)
+	}
+	return runtask, nil
+}
+
+// buildTaskIndex maps the identities of the run tasks to the run tasks. The
+// identities are found by rendering the meta specifications of the tasks
+// against a copy of the provided values.
+func (m *TaskGroupRunner) buildTaskIndex(values map[string]interface{}) (map[string]*v1alpha1.RunTask, error) {
+	values = util.DeepCopyMap(values)
+	index := map[string]*v1alpha1.RunTask{}
+	for _, runtask := range m.allTasks {
+		meta, err := template.AsTemplatedBytesWithFuncs("MetaTaskSpec", runtask.Spec.Meta, values, m.templateFuncs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to index runtask '%s'", runtask.Name)
+		}
+
+		var mts MetaTaskSpec
+		err = yaml.Unmarshal(meta, &mts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to index runtask '%s': invalid meta", runtask.Name)
+		}
+
+		id := strings.ToLower(mts.Identity)
+		if _, found := index[id]; found {
+			return nil, fmt.Errorf("failed to index runtask '%s': duplicate id '%s'", runtask.Name, mts.Identity)
+		}
+		index[id] = runtask
+	}
+	return index, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+)
+
+func TestRunTask(t *testing.T) {
+	tests := map[string]struct {
+		id             string
+		isRun          bool
+		isErr          bool
+		expectedOutput string
+	}{
+		"run task - +ve test case - task is executed": {
+			id:             "t2",
+			expectedOutput: "t2-seen-node-1",
+		},
+		"run task - +ve test case - id is case insensitive": {
+			id:             "T2",
+			expectedOutput: "t2-seen-node-1",
+		},
+		"run task - +ve test case - task is re-executed after a run": {
+			id:             "t2",
+			isRun:          true,
+			expectedOutput: "t2-seen-node-1",
+		},
+		"run task - -ve test case - unknown task": {
+			id:    "t4",
+			isErr: true,
+		},
+		"run task - -ve test case - failed task": {
+			id:    "t3",
+			isErr: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "node-1" | saveAs "t1.node" .TaskResult | noop -}}`))
+			r.AddRunTask(fakeCommandRunTask("t2", `{{- nestedString .TaskResult "t1" "node" | printf "t2-seen-%s" | saveAs "t2.node" .TaskResult | noop -}}`))
+			r.AddRunTask(fakeCommandRunTask("t3", `{{- if .failT3 }}{{ fail "t3 failed" }}{{ end -}}`))
+			r.SetOutputTask(&v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{
+				Meta: "id: o1\nkind: Command\naction: output",
+				Task: `{{ nestedString .TaskResult "t2" "node" }}`,
+			}})
+
+			values := fakeTemplateValues()
+			if mock.isRun {
+				_, err := r.Run(context.Background(), values)
+				if err != nil {
+					t.Fatalf("failed to test run task: expected no error in run: actual '%+v'", err)
+				}
+				// result of t2 is re-computed
+				util.SetNestedField(values, "stale", string(v1alpha1.TaskResultTLP), "t2", "node")
+			} else {
+				util.SetNestedField(values, "node-1", string(v1alpha1.TaskResultTLP), "t1", "node")
+			}
+			values["failT3"] = true
+
+			output, err := r.RunTask(mock.id, values)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test run task: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			if !mock.isErr && string(output) != mock.expectedOutput {
+				t.Fatalf("failed to test run task: expected output '%s': actual '%s'", mock.expectedOutput, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/glog"
+	"github.com/openebs/maya/pkg/template"
+	"k8s.io/klog"
+)
+
+// debugVerbosity is the klog verbosity at which the debug messages e.g. the
+// template values diff of each task are logged
+const debugVerbosity = 4
+
+// logValuesDiff flags if the template values diff of each task is logged;
+// 1 if enabled & 0 otherwise
+var logValuesDiff int32
+
+// SetLogValuesDiff enables or disables the logging of the difference in
+// template values before & after the execution of each task. The diff is
+// logged at klog verbosity 4 & above.
+//
+// NOTE:
+//  This is disabled by default since computing the diff requires a copy of
+// the template values for each task
+func SetLogValuesDiff(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&logValuesDiff, v)
+}
+
+// isLogValuesDiff flags if the template values diff of each task needs to
+// be logged
+func isLogValuesDiff() bool {
+	return atomic.LoadInt32(&logValuesDiff) == 1 && isDebug()
+}
+
+// isDebug flags if the debug messages need to be logged
+//
+// NOTE:
+//  Verbosity is set via klog's flags irrespective of the logger in use
+func isDebug() bool {
+	return bool(klog.V(debugVerbosity))
+}
+
+// Logger logs the messages of the task package. Context of a message is
+// provided as key value pairs.
+type Logger interface {
+	// Info logs an informational message
+	Info(msg string, keysAndValues ...interface{})
+	// Error logs an error message along with the error that resulted in it
+	Error(err error, msg string, keysAndValues ...interface{})
+}
+
+// KlogAdapter is a logger that logs via klog. Key value pairs are appended
+// to the message as key="value". This is the default logger.
+type KlogAdapter struct{}
+
+// Info logs an informational message via klog
+func (KlogAdapter) Info(msg string, keysAndValues ...interface{}) {
+	klog.InfoDepth(1, withKeysAndValues(msg, keysAndValues))
+}
+
+// Error logs an error message via klog
+func (KlogAdapter) Error(err error, msg string, keysAndValues ...interface{}) {
+	klog.ErrorDepth(1, withKeysAndValues(msg, withError(err, keysAndValues)))
+}
+
+// GlogAdapter is a logger that logs via glog. Key value pairs are appended
+// to the message as key="value".
+//
+// NOTE:
+//  This is meant for binaries that configure glog & not klog
+type GlogAdapter struct{}
+
+// Info logs an informational message via glog
+func (GlogAdapter) Info(msg string, keysAndValues ...interface{}) {
+	glog.InfoDepth(1, withKeysAndValues(msg, keysAndValues))
+}
+
+// Error logs an error message via glog
+func (GlogAdapter) Error(err error, msg string, keysAndValues ...interface{}) {
+	glog.ErrorDepth(1, withKeysAndValues(msg, withError(err, keysAndValues)))
+}
+
+// withError prepends the provided error if any to the provided key value
+// pairs
+func withError(err error, keysAndValues []interface{}) []interface{} {
+	if err == nil {
+		return keysAndValues
+	}
+	return append([]interface{}{"error", err}, keysAndValues...)
+}
+
+// withKeysAndValues appends the provided key value pairs to the provided
+// message
+//
+// NOTE:
+//  A key without a value is logged with value (MISSING)
+func withKeysAndValues(msg string, keysAndValues []interface{}) string {
+	var b bytes.Buffer
+	b.WriteString(msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		var value interface{} = "(MISSING)"
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		fmt.Fprintf(&b, " %v=%q", keysAndValues[i], fmt.Sprint(value))
+	}
+	return b.String()
+}
+
+var (
+	// defaultLoggerMutex guards the default logger
+	defaultLoggerMutex sync.RWMutex
+	// defaultLogger is the logger of this package
+	defaultLogger Logger = KlogAdapter{}
+)
+
+// SetDefaultLogger sets the logger of this package. This logger is used by
+// the runners that do not set their own logger & by the rest of this package
+// e.g. the runtask cache.
+func SetDefaultLogger(l Logger) {
+	defaultLoggerMutex.Lock()
+	defer defaultLoggerMutex.Unlock()
+
+	defaultLogger = l
+}
+
+// packageLogger returns the logger of this package
+func packageLogger() Logger {
+	defaultLoggerMutex.RLock()
+	defer defaultLoggerMutex.RUnlock()
+
+	return defaultLogger
+}
+
+// WithLogger sets the logger of the task group runner
+func WithLogger(l Logger) TaskGroupRunnerOption {
+	return func(m *TaskGroupRunner) {
+		m.SetLogger(l)
+	}
+}
+
+// SetLogger sets the logger of this runner. The logger of this package is
+// used if this is not set.
+func (m *TaskGroupRunner) SetLogger(l Logger) {
+	m.log = l
+}
+
+// logTaskValuesDiff logs the template values that the provided task added,
+// removed or modified
+func (m *TaskGroupRunner) logTaskValuesDiff(identity string, before, after map[string]interface{}) {
+	diff := template.ValuesDiff(redactTemplateValues(before, m.redactKeys), redactTemplateValues(after, m.redactKeys))
+	if len(diff) == 0 {
+		m.logger().Info("task did not change the template values", "task", identity)
+		return
+	}
+	m.logger().Info("task changed the template values:\n"+diff, "task", identity)
+}
+
+// logger returns the logger of this runner
+func (m *TaskGroupRunner) logger() Logger {
+	if m.log == nil {
+		return packageLogger()
+	}
+	return m.log
+}
+
+// logger returns the logger of this task
+func (m *taskExecutor) logger() Logger {
+	if m.log == nil {
+		return packageLogger()
+	}
+	return m.log
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"flag"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"k8s.io/klog"
+)
+
+// fakeLogger captures the logged messages along with their key value pairs
+type fakeLogger struct {
+	mutex    sync.Mutex
+	messages []string
+	errors   []error
+}
+
+func (l *fakeLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.messages = append(l.messages, "info: "+withKeysAndValues(msg, keysAndValues))
+}
+
+func (l *fakeLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.errors = append(l.errors, err)
+	l.messages = append(l.messages, "error: "+withKeysAndValues(msg, withError(err, keysAndValues)))
+}
+
+// contains flags if any of the captured messages contains the provided text
+func (l *fakeLogger) contains(text string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, msg := range l.messages {
+		if strings.Contains(msg, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSetLogger(t *testing.T) {
+	logger := &fakeLogger{}
+	r := NewTaskGroupRunner()
+	r.SetLogger(logger)
+	r.AddRunTasks([]*v1alpha1.RunTask{
+		fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`),
+		fakeCommandRunTask("t2", `{{- fail "t2 failed" -}}`),
+	})
+	r.Run(context.Background(), fakeTemplateValues())
+
+	expected := []string{
+		`error: failed to execute runtask error="`,
+		`runtask="t2"`,
+		"info: will rollback previously executed runtask(s)",
+	}
+	for _, e := range expected {
+		if !logger.contains(e) {
+			t.Fatalf("failed to test set logger: expected message '%s': actual '%v'", e, logger.messages)
+		}
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	logger := &fakeLogger{}
+	r := NewTaskGroupRunner(WithLogger(logger))
+	r.AddRunTasks([]*v1alpha1.RunTask{
+		fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`),
+		fakeCommandRunTask("t2", `{{- fail "t2 failed" -}}`),
+	})
+	r.Run(context.Background(), fakeTemplateValues())
+
+	found := false
+	for _, err := range logger.errors {
+		if err != nil && strings.Contains(err.Error(), "t2 failed") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("failed to test with logger: expected error of task 't2': actual '%v'", logger.errors)
+	}
+}
+
+func TestDefaultLogger(t *testing.T) {
+	if _, ok := NewTaskGroupRunner().logger().(KlogAdapter); !ok {
+		t.Fatalf("failed to test default logger: expected 'klog adapter'")
+	}
+}
+
+func TestSetDefaultLogger(t *testing.T) {
+	defer SetDefaultLogger(KlogAdapter{})
+
+	logger := &fakeLogger{}
+	SetDefaultLogger(logger)
+	r := NewTaskGroupRunner()
+	r.AddRunTask(fakeCommandRunTask("t1", `{{- fail "t1 failed" -}}`))
+	r.Run(context.Background(), fakeTemplateValues())
+
+	if !logger.contains(`runtask="t1"`) {
+		t.Fatalf("failed to test set default logger: expected message of task 't1': actual '%v'", logger.messages)
+	}
+}
+
+func TestLogValuesDiff(t *testing.T) {
+	flags := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(flags)
+	verbosity := flags.Lookup("v").Value.String()
+	defer flags.Set("v", verbosity)
+	defer SetLogValuesDiff(false)
+
+	tests := map[string]struct {
+		enabled   bool
+		verbosity string
+		expected  bool
+	}{
+		"log values diff - +ve test case - enabled at verbosity 4": {enabled: true, verbosity: "4", expected: true},
+		"log values diff - -ve test case - enabled at verbosity 2": {enabled: true, verbosity: "2"},
+		"log values diff - -ve test case - disabled":               {verbosity: "4"},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			SetLogValuesDiff(mock.enabled)
+			flags.Set("v", mock.verbosity)
+
+			logger := &fakeLogger{}
+			r := NewTaskGroupRunner()
+			r.SetLogger(logger)
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+			r.Run(context.Background(), fakeTemplateValues())
+
+			actual := logger.contains("+ TaskResult.t1.objectName: t1-obj")
+			if actual != mock.expected {
+				t.Fatalf("failed to test log values diff: expected diff logged '%t': actual '%v'", mock.expected, logger.messages)
+			}
+		})
+	}
+}
+
+func TestWithKeysAndValues(t *testing.T) {
+	tests := map[string]struct {
+		keysAndValues []interface{}
+		expected      string
+	}{
+		"with keys and values - +ve test case - no pairs": {
+			expected: "task failed",
+		},
+		"with keys and values - +ve test case - pairs": {
+			keysAndValues: []interface{}{"task", "t1", "retries", 2},
+			expected:      `task failed task="t1" retries="2"`,
+		},
+		"with keys and values - +ve test case - key without value": {
+			keysAndValues: []interface{}{"task"},
+			expected:      `task failed task="(MISSING)"`,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := withKeysAndValues("task failed", mock.keysAndValues)
+			if actual != mock.expected {
+				t.Fatalf("failed to test with keys and values: expected '%s': actual '%s'", mock.expected, actual)
+			}
+		})
+	}
+}
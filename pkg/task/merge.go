@@ -0,0 +1,147 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+)
+
+// MergeResults merges the task results found at the provided keys of the
+// last run into a single json document. A key is the dot separated path of
+// a result w.r.t .TaskResult e.g. "listpods.items" refers to
+// .TaskResult.listpods.items. A result can be a json document in bytes or
+// string or can be a list or a map.
+//
+// Lists are merged by appending their items. Maps are merged by their keys
+// where the values of a common key are merged recursively. Values of a
+// common key that can not be merged e.g. two different strings are
+// reported as a conflict.
+//
+// Example:
+//  Merging ["pod-1"] with ["pod-2"] results in ["pod-1","pod-2"]
+//
+// NOTE:
+//  This is invoked after Run. An error is returned if this runner was not
+// run or if a result is not found.
+func (m *TaskGroupRunner) MergeResults(keys ...string) ([]byte, error) {
+	m.mutex.Lock()
+	values := m.lastValues
+	m.mutex.Unlock()
+
+	if values == nil {
+		return nil, fmt.Errorf("failed to merge results: runner was not run")
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("failed to merge results: no keys provided")
+	}
+
+	var merged interface{}
+	for idx, key := range keys {
+		fields := append([]string{string(v1alpha1.TaskResultTLP)}, strings.Split(key, ".")...)
+		result, err := decodeResult(util.GetNestedField(values, fields...))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to merge results: invalid result at key '%s'", key)
+		}
+
+		if idx == 0 {
+			merged = result
+			continue
+		}
+		merged, err = mergeResult(merged, result, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to merge results: conflict at key '%s'", key)
+		}
+	}
+
+	return json.Marshal(merged)
+}
+
+// decodeResult decodes the provided task result into lists, maps & scalars
+// similar to the ones produced by unmarshalling a json document
+func decodeResult(result interface{}) (decoded interface{}, err error) {
+	var b []byte
+	switch r := result.(type) {
+	case nil:
+		return nil, fmt.Errorf("result not found")
+	case []byte:
+		b = r
+	case string:
+		b = []byte(r)
+	default:
+		// round trip ensures maps & lists of any type can be merged
+		b, err = json.Marshal(r)
+		if err != nil {
+			return
+		}
+	}
+
+	err = json.Unmarshal(b, &decoded)
+	return
+}
+
+// mergeResult merges the provided source into the provided destination.
+// Path is the path of these values w.r.t the merged document & is used to
+// report the conflicts.
+func mergeResult(dest, src interface{}, path string) (interface{}, error) {
+	switch d := dest.(type) {
+	case []interface{}:
+		s, ok := src.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("can not merge '%T' into a list at path '%s'", src, pathOrRoot(path))
+		}
+		return append(d, s...), nil
+	case map[string]interface{}:
+		s, ok := src.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("can not merge '%T' into a map at path '%s'", src, pathOrRoot(path))
+		}
+		for k, v := range s {
+			existing, found := d[k]
+			if !found {
+				d[k] = v
+				continue
+			}
+			mergedValue, err := mergeResult(existing, v, path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			d[k] = mergedValue
+		}
+		return d, nil
+	}
+
+	if !reflect.DeepEqual(dest, src) {
+		return nil, fmt.Errorf("values '%v' and '%v' differ at path '%s'", dest, src, pathOrRoot(path))
+	}
+	return dest, nil
+}
+
+// pathOrRoot returns the provided path or "." if it is the root path
+func pathOrRoot(path string) string {
+	if len(path) == 0 {
+		return "."
+	}
+	return path
+}
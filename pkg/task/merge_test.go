@@ -0,0 +1,126 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+)
+
+func TestMergeResults(t *testing.T) {
+	tests := map[string]struct {
+		results        map[string]interface{}
+		keys           []string
+		expectedOutput string
+		expectedErr    string
+	}{
+		"merge results - +ve test case - lists": {
+			results: map[string]interface{}{
+				"t1": map[string]interface{}{"items": `["pod-1"]`},
+				"t2": map[string]interface{}{"items": []byte(`["pod-2","pod-3"]`)},
+			},
+			keys:           []string{"t1.items", "t2.items"},
+			expectedOutput: `["pod-1","pod-2","pod-3"]`,
+		},
+		"merge results - +ve test case - lists of k8s objects": {
+			results: map[string]interface{}{
+				"pods": map[string]interface{}{"list": `{"kind":"List","items":[{"name":"pod-1"}]}`},
+				"svcs": map[string]interface{}{"list": map[string]interface{}{"kind": "List", "items": []interface{}{map[string]interface{}{"name": "svc-1"}}}},
+			},
+			keys:           []string{"pods.list", "svcs.list"},
+			expectedOutput: `{"items":[{"name":"pod-1"},{"name":"svc-1"}],"kind":"List"}`,
+		},
+		"merge results - +ve test case - maps": {
+			results: map[string]interface{}{
+				"t1": map[string]interface{}{"labels": `{"app":"jiva","tier":"storage"}`},
+				"t2": map[string]interface{}{"labels": `{"app":"jiva","pvc":"pvc-1"}`},
+			},
+			keys:           []string{"t1.labels", "t2.labels"},
+			expectedOutput: `{"app":"jiva","pvc":"pvc-1","tier":"storage"}`,
+		},
+		"merge results - -ve test case - conflicting values": {
+			results: map[string]interface{}{
+				"t1": map[string]interface{}{"labels": `{"app":"jiva"}`},
+				"t2": map[string]interface{}{"labels": `{"app":"cstor"}`},
+			},
+			keys:        []string{"t1.labels", "t2.labels"},
+			expectedErr: "conflict at key 't2.labels': values 'jiva' and 'cstor' differ at path '.app'",
+		},
+		"merge results - -ve test case - list and map": {
+			results: map[string]interface{}{
+				"t1": map[string]interface{}{"items": `["pod-1"]`},
+				"t2": map[string]interface{}{"items": `{"name":"pod-2"}`},
+			},
+			keys:        []string{"t1.items", "t2.items"},
+			expectedErr: "conflict at key 't2.items'",
+		},
+		"merge results - -ve test case - result not found": {
+			results: map[string]interface{}{
+				"t1": map[string]interface{}{"items": `["pod-1"]`},
+			},
+			keys:        []string{"t1.items", "t2.items"},
+			expectedErr: "invalid result at key 't2.items': result not found",
+		},
+		"merge results - -ve test case - invalid json": {
+			results: map[string]interface{}{
+				"t1": map[string]interface{}{"items": `["pod-1"`},
+			},
+			keys:        []string{"t1.items"},
+			expectedErr: "invalid result at key 't1.items'",
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.AddRunTask(fakeCommandRunTask("noop", ""))
+			values := fakeTemplateValues()
+			_, err := r.Run(context.Background(), values)
+			if err != nil {
+				t.Fatalf("failed to test merge results: expected 'no error' on run: actual '%s'", err)
+			}
+			for id, result := range mock.results {
+				util.SetNestedField(values, result, string(v1alpha1.TaskResultTLP), id)
+			}
+
+			output, err := r.MergeResults(mock.keys...)
+			if len(mock.expectedErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), mock.expectedErr) {
+					t.Fatalf("failed to test merge results: expected error '%s': actual '%v'", mock.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to test merge results: expected 'no error': actual '%s'", err)
+			}
+			if string(output) != mock.expectedOutput {
+				t.Fatalf("failed to test merge results: expected '%s': actual '%s'", mock.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestMergeResultsWithoutRun(t *testing.T) {
+	_, err := NewTaskGroupRunner().MergeResults("t1.items")
+	if err == nil {
+		t.Fatalf("failed to test merge results without run: expected error: actual 'no error'")
+	}
+}
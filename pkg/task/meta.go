@@ -17,14 +17,15 @@ limitations under the License.
 package task
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ghodss/yaml"
-	"github.com/golang/glog"
 	"github.com/openebs/maya/pkg/template"
+	"github.com/openebs/maya/pkg/util"
 
 	m_k8s_client "github.com/openebs/maya/pkg/client/k8s"
 	mach_apis_meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -65,10 +66,37 @@ const (
 	// PatchTA flags a action as patch. Typically used to
 	// patch an object.
 	PatchTA MetaTaskAction = "patch"
+	// PatchStrategicTA flags a action as strategic merge patch. The task
+	// specifications are the patch document itself.
+	PatchStrategicTA MetaTaskAction = "patch-strategic"
+	// PatchMergeTA flags a action as JSON merge patch (RFC 7386). The task
+	// specifications are the patch document itself.
+	PatchMergeTA MetaTaskAction = "patch-merge"
+	// PatchJSONTA flags a action as JSON patch (RFC 6902). The task
+	// specifications are the list of patch operations.
+	PatchJSONTA MetaTaskAction = "patch-json"
+	// PatchCASTA flags a action as compare and swap patch. Typically used to
+	// patch an object only if it was not modified after it was fetched. The
+	// patch is re-attempted against the latest object on conflict.
+	PatchCASTA MetaTaskAction = "patch-cas"
 	// OutputTA flags the task action as output. Typically used to
 	// provide a schema (i.e. a custom defined) based output after
 	// running one or more tasks.
 	OutputTA MetaTaskAction = "output"
+	// WatchTA flags a action as watch. Typically used to wait till an object
+	// reaches a desired state.
+	WatchTA MetaTaskAction = "watch"
+)
+
+// OutputFormat represents the format the output of an output task is
+// rendered in
+type OutputFormat string
+
+const (
+	// YAMLOF renders the output as yaml
+	YAMLOF OutputFormat = "yaml"
+	// JSONOF renders the output as json
+	JSONOF OutputFormat = "json"
 )
 
 // MetaTaskProps provides properties representing the task's meta
@@ -94,6 +122,132 @@ type MetaTaskProps struct {
 	// # max of 10 attempts in 20 seconds interval
 	// retry: "10,20s"
 	Retry string `json:"retry"`
+	// RetryOnError specifies the no. of times this particular task can be
+	// re-tried if its execution resulted in a transient error. The interval
+	// between attempts is doubled after each attempt.
+	//
+	// A sample retry on error option:
+	//
+	// # max of 5 attempts starting with 2 seconds interval
+	// retryOnError: "5,2s"
+	//
+	// NOTE:
+	//  This is different from retry which is meant for task result
+	// verification errors. A put task is never re-tried.
+	RetryOnError string `json:"retryOnError"`
+	// Timeout specifies the maximum duration this task is allowed to execute.
+	// This overrides the timeout set in the run task's specifications.
+	//
+	// A sample timeout option:
+	//
+	// # task fails if its execution takes more than 30 seconds
+	// timeout: "30s"
+	Timeout string `json:"timeout"`
+	// RunIf is a predicate that determines if this task should be executed.
+	// This is typically set as a go template expression that gets evaluated
+	// against the template values. Task is skipped if this evaluates to
+	// "false" or "0".
+	//
+	// A sample run if option:
+	//
+	// # run this task only if the snapshot was created
+	// runIf: {{ .TaskResult.snapcreate.isCreated }}
+	RunIf MetaTaskPredicate `json:"runIf"`
+	// Disable is a predicate that determines if this task should be skipped.
+	// This is typically set as a go template expression that gets evaluated
+	// against the template values. Task is skipped if this evaluates to a
+	// truthy value e.g. "true", "yes", "1".
+	//
+	// A sample disable option:
+	//
+	// # skip this task if the volume is not a clone
+	// disable: {{ ne .Volume.isCloneEnable "true" }}
+	Disable MetaTaskPredicate `json:"disable"`
+	// RollbackPriority determines the order in which the rollback of this
+	// task is executed. Rollbacks with higher priority are executed first.
+	// Rollbacks with same priority are executed in the reverse order of their
+	// task's execution.
+	//
+	// A sample rollback priority option:
+	//
+	// # rollback this task before the tasks with default priority i.e. 0
+	// rollbackPriority: 10
+	RollbackPriority int `json:"rollbackPriority"`
+	// Cacheable flags if the result of this task can be cached & reused by
+	// the executions of this task having same rendered specifications. This
+	// is applicable to get & list actions only.
+	//
+	// A sample cacheable option:
+	//
+	// # reuse the storage pool fetched previously
+	// cacheable: true
+	Cacheable bool `json:"cacheable"`
+	// IfNotExists makes a put task idempotent. The object set in objectName
+	// is fetched before the task is executed. The task is skipped if the
+	// object already exists & the existing object is set at .JsonResult.
+	//
+	// A sample ifNotExists option:
+	//
+	// # do not re-create the service if a previous run had created it
+	// objectName: {{ .Volume.owner }}-svc
+	// ifNotExists: true
+	IfNotExists bool `json:"ifNotExists"`
+	// GroupLabel labels the group this task belongs to. Tasks of a group are
+	// skipped if the group is skipped by the task group runner.
+	//
+	// A sample group label option:
+	//
+	// # task applies to cstor volumes only
+	// groupLabel: cstor
+	GroupLabel string `json:"groupLabel"`
+	// Command is the command along with its arguments that is executed by a
+	// shellexec plugin task. The command is executed only if it is allowed by
+	// the registered shellexec plugin.
+	//
+	// A sample command option:
+	//
+	// command: /sbin/iscsiadm -m session
+	Command string `json:"command"`
+	// CASRetries is the no. of times a patch-cas task is re-attempted if its
+	// object was modified after it was fetched. Defaults to 3 if not set.
+	//
+	// A sample cas retries option:
+	//
+	// # re-attempt the patch at most 5 times on conflict
+	// casRetries: 5
+	CASRetries int `json:"casRetries"`
+	// IgnoreErrors are the classes of errors that do not fail this task. A
+	// task whose execution results in an error of any of these classes is
+	// considered successful & the error is stored at
+	// .TaskResult.<TaskIdentity>.ignoredErr. Supported classes are
+	// VersionMismatch, NotFound, Verify & Unknown.
+	//
+	// A sample ignore errors option:
+	//
+	// # absence of the snapshot is not a failure
+	// ignoreErrors:
+	// - NotFound
+	IgnoreErrors []string `json:"ignoreErrors"`
+}
+
+// MetaTaskPredicate is an evaluated predicate of a task e.g. run if. It can
+// be unmarshalled from any yaml scalar i.e. string, boolean or number.
+type MetaTaskPredicate string
+
+// UnmarshalJSON unmarshalls the provided scalar into the predicate
+func (p *MetaTaskPredicate) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		*p = ""
+		return nil
+	}
+	*p = MetaTaskPredicate(fmt.Sprintf("%v", v))
+	return nil
 }
 
 // toString returns a string representation of MetaTaskProps structure. In this
@@ -103,12 +257,23 @@ type MetaTaskProps struct {
 // Example:
 //  runNamespace=default::objectName=MySvc::retry=3,20s
 func (m MetaTaskProps) toString() string {
-	return fmt.Sprintf("runNamespace=%s::owner=%s::objectName=%s::options=%s::retry=%s",
+	return fmt.Sprintf("runNamespace=%s::owner=%s::objectName=%s::options=%s::retry=%s::retryOnError=%s::timeout=%s::runIf=%s::disable=%s::rollbackPriority=%d::cacheable=%t::ifNotExists=%t::groupLabel=%s::command=%s::casRetries=%d::ignoreErrors=%s",
 		m.RunNamespace,
 		m.Owner,
 		m.ObjectName,
 		m.Options,
-		m.Retry)
+		m.Retry,
+		m.RetryOnError,
+		m.Timeout,
+		m.RunIf,
+		m.Disable,
+		m.RollbackPriority,
+		m.Cacheable,
+		m.IfNotExists,
+		m.GroupLabel,
+		m.Command,
+		m.CASRetries,
+		strings.Join(m.IgnoreErrors, ","))
 }
 
 // selectOverride will override the current meta task properties from the given
@@ -134,6 +299,45 @@ func (m MetaTaskProps) selectOverride(given MetaTaskProps) MetaTaskProps {
 	if len(retry) != 0 {
 		m.Retry = retry
 	}
+	retryOnError := strings.TrimSpace(given.RetryOnError)
+	if len(retryOnError) != 0 {
+		m.RetryOnError = retryOnError
+	}
+	timeout := strings.TrimSpace(given.Timeout)
+	if len(timeout) != 0 {
+		m.Timeout = timeout
+	}
+	runIf := strings.TrimSpace(string(given.RunIf))
+	if len(runIf) != 0 {
+		m.RunIf = MetaTaskPredicate(runIf)
+	}
+	disable := strings.TrimSpace(string(given.Disable))
+	if len(disable) != 0 {
+		m.Disable = MetaTaskPredicate(disable)
+	}
+	if given.RollbackPriority != 0 {
+		m.RollbackPriority = given.RollbackPriority
+	}
+	if given.Cacheable {
+		m.Cacheable = given.Cacheable
+	}
+	if given.IfNotExists {
+		m.IfNotExists = given.IfNotExists
+	}
+	groupLabel := strings.TrimSpace(given.GroupLabel)
+	if len(groupLabel) != 0 {
+		m.GroupLabel = groupLabel
+	}
+	command := strings.TrimSpace(given.Command)
+	if len(command) != 0 {
+		m.Command = command
+	}
+	if given.CASRetries != 0 {
+		m.CASRetries = given.CASRetries
+	}
+	if len(given.IgnoreErrors) != 0 {
+		m.IgnoreErrors = given.IgnoreErrors
+	}
 
 	return m
 }
@@ -152,6 +356,12 @@ type MetaTaskSpec struct {
 	// In other words a task template is executed multiple times based on each
 	// of the item present here.
 	RepeatWith RepeatWithResource `json:"repeatWith"`
+	// OutputFormat is the format i.e. yaml or json the output of an output
+	// task is rendered in. The output is rendered as is if this is not set.
+	OutputFormat OutputFormat `json:"outputFormat"`
+	// Scope is the local variables of this task. These are available to this
+	// task's templates only & are discarded once this task is executed.
+	Scope map[string]interface{} `json:"scope"`
 }
 
 type metaTaskExecutor struct {
@@ -164,13 +374,26 @@ type metaTaskExecutor struct {
 	repeater repeatExecutor
 	// k8sClient will be used to make K8s API calls
 	k8sClient *m_k8s_client.K8sClient
+
+	// rendered is the meta task yaml after templating
+	rendered string
 }
 
 // getMetaInstances is a utility function that provides required objects
 // to instantiate meta task executor
-func getMetaInstances(metaTaskYml string, values map[string]interface{}) (m MetaTaskSpec, i taskIdentifier, r repeatExecutor, err error) {
+func getMetaInstances(metaTaskYml string, values map[string]interface{}, funcs template.FuncMap) (m MetaTaskSpec, i taskIdentifier, r repeatExecutor, err error) {
 	// transform the yaml with provided values
-	b, err := template.AsTemplatedBytes("MetaTaskSpec", metaTaskYml, values)
+	b, err := template.AsTemplatedBytesWithFuncs("MetaTaskSpec", metaTaskYml, values, funcs)
+	if err != nil {
+		return
+	}
+	return asMetaInstances(b)
+}
+
+// asMetaInstances provides the objects required to instantiate meta task
+// executor from the templated meta task yaml
+func asMetaInstances(b []byte) (m MetaTaskSpec, i taskIdentifier, r repeatExecutor, err error) {
+	err = validateMeta(b)
 	if err != nil {
 		return
 	}
@@ -196,9 +419,15 @@ func getMetaInstances(metaTaskYml string, values map[string]interface{}) (m Meta
 }
 
 // newMetaTaskExecutor provides a new instance of metaTaskExecutor
-func newMetaTaskExecutor(metaTaskYml string, values map[string]interface{}) (*metaTaskExecutor, error) {
+func newMetaTaskExecutor(metaTaskYml string, values map[string]interface{}, funcs template.FuncMap) (*metaTaskExecutor, error) {
 
-	m, i, r, err := getMetaInstances(metaTaskYml, values)
+	// transform the yaml with provided values
+	b, err := template.AsTemplatedBytesWithFuncs("MetaTaskSpec", metaTaskYml, values, funcs)
+	if err != nil {
+		return nil, err
+	}
+
+	m, i, r, err := asMetaInstances(b)
 	if err != nil {
 		return nil, err
 	}
@@ -213,6 +442,7 @@ func newMetaTaskExecutor(metaTaskYml string, values map[string]interface{}) (*me
 		identifier: i,
 		repeater:   r,
 		k8sClient:  k,
+		rendered:   string(b),
 	}, nil
 }
 
@@ -232,6 +462,18 @@ func (m *metaTaskExecutor) getTaskIdentity() MetaTaskIdentity {
 	return m.metaTask.MetaTaskIdentity
 }
 
+func (m *metaTaskExecutor) isCacheable() bool {
+	return m.metaTask.Cacheable
+}
+
+func (m *metaTaskExecutor) isIfNotExists() bool {
+	return m.metaTask.IfNotExists && m.isPut()
+}
+
+func (m *metaTaskExecutor) getRollbackPriority() int {
+	return m.metaTask.RollbackPriority
+}
+
 func (m *metaTaskExecutor) getObjectName() string {
 	return m.metaTask.ObjectName
 }
@@ -245,7 +487,89 @@ func (m *metaTaskExecutor) getK8sClient() *m_k8s_client.K8sClient {
 }
 
 func (m *metaTaskExecutor) getRetry() (attempts int, interval time.Duration) {
-	retry := m.metaTask.Retry
+	return parseRetry(m.metaTask.Retry)
+}
+
+func (m *metaTaskExecutor) getRetryOnError() (attempts int, interval time.Duration) {
+	return parseRetry(m.metaTask.RetryOnError)
+}
+
+// getCASRetries returns the no. of times a patch-cas task is re-attempted on
+// conflict
+func (m *metaTaskExecutor) getCASRetries() int {
+	if m.metaTask.CASRetries <= 0 {
+		return defaultCASRetries
+	}
+	return m.metaTask.CASRetries
+}
+
+// getOutputFormat returns the output format set in the meta specifications
+func (m *metaTaskExecutor) getOutputFormat() OutputFormat {
+	return m.metaTask.OutputFormat
+}
+
+// isIgnoredError flags if the class of the provided error is one of the
+// classes of errors ignored by this task
+func (m *metaTaskExecutor) isIgnoredError(err error) bool {
+	if err == nil {
+		return false
+	}
+	class := template.ErrorClass(err)
+	for _, ignored := range m.metaTask.IgnoreErrors {
+		if strings.EqualFold(strings.TrimSpace(ignored), class) {
+			return true
+		}
+	}
+	return false
+}
+
+// getScope returns the local variables set in the meta specifications
+func (m *metaTaskExecutor) getScope() map[string]interface{} {
+	return m.metaTask.Scope
+}
+
+// getTimeout returns the timeout set in the meta specifications; a zero
+// duration is returned if timeout is not set or is invalid
+func (m *metaTaskExecutor) getTimeout() time.Duration {
+	timeout, _ := time.ParseDuration(strings.TrimSpace(m.metaTask.Timeout))
+	return timeout
+}
+
+// isSkippedGroup flags if this task belongs to any of the provided skipped
+// groups
+func (m *metaTaskExecutor) isSkippedGroup(skippedGroups []string) bool {
+	groupLabel := strings.TrimSpace(m.metaTask.GroupLabel)
+	return len(groupLabel) != 0 && util.ContainsString(skippedGroups, groupLabel)
+}
+
+// isSkip flags if this task should not be executed based on the task's
+// predicates
+func (m *metaTaskExecutor) isSkip() bool {
+	return len(m.skipReason()) != 0
+}
+
+// skipReason returns the reason to skip this task; an empty reason implies
+// the task should be executed
+//
+// NOTE:
+//  Predicates are already evaluated since meta specifications are templated
+// before they are unmarshalled. Only "false" & "0" run if predicates result
+// in a skip. Any truthy disable predicate results in a skip.
+func (m *metaTaskExecutor) skipReason() string {
+	if util.CheckTruthy(strings.TrimSpace(string(m.metaTask.Disable))) {
+		return "disable condition met"
+	}
+
+	runIf := strings.ToLower(strings.TrimSpace(string(m.metaTask.RunIf)))
+	if runIf == "false" || runIf == "0" {
+		return fmt.Sprintf("run if predicate evaluated to '%s'", runIf)
+	}
+
+	return ""
+}
+
+// parseRetry parses the retry option which is in "attempts,interval" format
+func parseRetry(retry string) (attempts int, interval time.Duration) {
 	// "attempts,interval" format
 	defRetry := "0,0s"
 
@@ -257,13 +581,13 @@ func (m *metaTaskExecutor) getRetry() (attempts int, interval time.Duration) {
 	}
 
 	// determine the attempts
-	attempts, _ = strconv.Atoi(retryArr[0])
+	attempts, _ = strconv.Atoi(strings.TrimSpace(retryArr[0]))
 	if attempts < 0 {
 		// no retries for negative attempt value
 		attempts = 0
 	}
 	// determine the interval
-	interval, _ = time.ParseDuration(retryArr[1])
+	interval, _ = time.ParseDuration(strings.TrimSpace(retryArr[1]))
 
 	return
 }
@@ -290,12 +614,37 @@ func (m *metaTaskExecutor) isPut() bool {
 	return m.metaTask.Action == PutTA
 }
 
+// isIdempotent flags if executing this meta task more than once has the
+// same effect as executing it once
+//
+// NOTE:
+//  A put is not idempotent since the attempt that failed may have created
+// its object(s) anyway
+func (m *metaTaskExecutor) isIdempotent() bool {
+	return !m.isPut()
+}
+
 func (m *metaTaskExecutor) isDelete() bool {
 	return m.metaTask.Action == DeleteTA
 }
 
 func (m *metaTaskExecutor) isPatch() bool {
-	return m.metaTask.Action == PatchTA
+	return m.metaTask.Action == PatchTA || len(m.getPatchType()) != 0
+}
+
+// getPatchType returns the type of patch flagged by the action e.g.
+// patch-json. An empty type is returned for patch action since its type is
+// set in its task specifications.
+func (m *metaTaskExecutor) getPatchType() TaskPatchType {
+	return patchActionTypes[m.metaTask.Action]
+}
+
+func (m *metaTaskExecutor) isPatchCAS() bool {
+	return m.metaTask.Action == PatchCASTA
+}
+
+func (m *metaTaskExecutor) isWatch() bool {
+	return m.metaTask.Action == WatchTA
 }
 
 func (m *metaTaskExecutor) isPutExtnV1B1Deploy() bool {
@@ -318,6 +667,14 @@ func (m *metaTaskExecutor) isPatchAppsV1B1Deploy() bool {
 	return m.identifier.isAppsV1B1Deploy() && m.isPatch()
 }
 
+func (m *metaTaskExecutor) isPatchCASExtnV1B1Deploy() bool {
+	return m.identifier.isExtnV1B1Deploy() && m.isPatchCAS()
+}
+
+func (m *metaTaskExecutor) isPatchCASOEV1alpha1SPC() bool {
+	return m.identifier.isStoragePoolClaim() && m.isPatchCAS()
+}
+
 func (m *metaTaskExecutor) isPutCoreV1Service() bool {
 	return m.identifier.isCoreV1Service() && m.isPut()
 }
@@ -405,6 +762,21 @@ func (m *metaTaskExecutor) isDeleteOEV1alpha1CSV() bool {
 	return m.identifier.isOEV1alpha1CV() && m.isDelete()
 }
 
+// isSupportedDelete flags if this meta task's delete action can be executed
+//
+// NOTE:
+//  A command does not make API calls & hence supports delete action as well
+func (m *metaTaskExecutor) isSupportedDelete() bool {
+	return m.isCommand() ||
+		m.isDeleteExtnV1B1Deploy() ||
+		m.isDeleteAppsV1B1Deploy() ||
+		m.isDeleteCoreV1Service() ||
+		m.isDeleteOEV1alpha1SP() ||
+		m.isDeleteOEV1alpha1CSP() ||
+		m.isDeleteOEV1alpha1CSV() ||
+		m.isDeleteOEV1alpha1CVR()
+}
+
 func (m *metaTaskExecutor) isDeleteOEV1alpha1CVR() bool {
 	return m.identifier.isOEV1alpha1CVR() && m.isDelete()
 }
@@ -436,9 +808,10 @@ func getRollbackMetaInstances(given MetaTaskSpec, objectName string) (m MetaTask
 		// rollback currently understands only Delete action
 		Action: DeleteTA,
 		MetaTaskProps: MetaTaskProps{
-			ObjectName:   objectName,
-			RunNamespace: given.RunNamespace,
-			Owner:        given.Owner,
+			ObjectName:       objectName,
+			RunNamespace:     given.RunNamespace,
+			Owner:            given.Owner,
+			RollbackPriority: given.RollbackPriority,
 		},
 		MetaTaskIdentity: given.MetaTaskIdentity,
 	}
@@ -466,7 +839,6 @@ func (m *metaTaskExecutor) asRollbackInstance(objectName string) (*metaTaskExecu
 
 	if len(objectName) == 0 {
 		errMsg := fmt.Sprintf("failed to build rollback instance for task '%s': object name is missing", m.getIdentity())
-		glog.Errorf(fmt.Sprintf("%s: meta task '%+v'", errMsg, m.getMetaInfo()))
 		return nil, true, fmt.Errorf(errMsg)
 	}
 
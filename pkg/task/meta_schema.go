@@ -0,0 +1,203 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// metaTaskSchema is the JSON schema of a run task's meta specifications
+//
+// NOTE:
+//  Only the subset of JSON schema i.e. type, required, properties &
+// minLength keywords are understood by the validator. Hence action, which is
+// required by all kinds other than Command, is verified by validateMeta.
+const metaTaskSchema = `{
+  "type": "object",
+  "required": ["id", "kind"],
+  "properties": {
+    "id": {
+      "type": "string",
+      "minLength": 1
+    },
+    "kind": {
+      "type": "string",
+      "minLength": 1
+    },
+    "action": {
+      "type": "string",
+      "minLength": 1
+    },
+    "apiVersion": {
+      "type": "string"
+    },
+    "runNamespace": {
+      "type": "string"
+    },
+    "objectName": {
+      "type": "string"
+    },
+    "outputFormat": {
+      "type": "string"
+    },
+    "scope": {
+      "type": "object"
+    }
+  }
+}`
+
+// jsonSchema represents the JSON schema keywords that are understood by the
+// meta specifications validator
+type jsonSchema struct {
+	Type       string                `json:"type"`
+	Required   []string              `json:"required"`
+	Properties map[string]jsonSchema `json:"properties"`
+	MinLength  int                   `json:"minLength"`
+}
+
+// metaSchema is the parsed instance of metaTaskSchema
+var metaSchema = mustParseSchema(metaTaskSchema)
+
+// mustParseSchema parses the provided JSON schema; it panics if the schema is
+// invalid
+func mustParseSchema(schema string) (s jsonSchema) {
+	err := json.Unmarshal([]byte(schema), &s)
+	if err != nil {
+		panic(fmt.Sprintf("invalid json schema: %s", err))
+	}
+	return
+}
+
+// MetaValidationError represents the violations of meta task schema found
+// in a run task's meta specifications
+type MetaValidationError struct {
+	// Violations are the schema violations
+	Violations []string
+}
+
+func (e *MetaValidationError) Error() string {
+	return fmt.Sprintf("invalid meta task specifications: %s", strings.Join(e.Violations, ", "))
+}
+
+// validateMeta validates the provided meta specifications against the meta
+// task schema
+//
+// NOTE:
+//  Meta specifications are validated after templating since the raw meta
+// specifications are not valid YAML till the template directives are
+// executed. This is invoked by asMetaInstances only which is common to a
+// run, a dry run & an explain.
+func validateMeta(meta []byte) error {
+	var doc interface{}
+	err := yaml.Unmarshal(meta, &doc)
+	if err != nil {
+		return &MetaValidationError{Violations: []string{fmt.Sprintf("malformed yaml: %s", err)}}
+	}
+
+	violations := metaSchema.validate("", doc)
+	violations = append(violations, validateAction(doc)...)
+	if len(violations) != 0 {
+		return &MetaValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// validateAction returns a violation if the provided meta specifications do
+// not set an action. Action is not required by a Command kind since the
+// command is set in the task's post specifications.
+func validateAction(doc interface{}) []string {
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if kind, _ := obj["kind"].(string); kind == string(CommandKind) {
+		return nil
+	}
+	if _, found := obj["action"]; !found {
+		return []string{"'action' is required"}
+	}
+	return nil
+}
+
+// validate returns the violations found in the provided value as per this
+// schema
+func (s jsonSchema) validate(path string, value interface{}) (violations []string) {
+	field := path
+	if len(field) == 0 {
+		field = "meta"
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("'%s' must be an object", field)}
+		}
+		for _, req := range s.Required {
+			if _, found := obj[req]; !found {
+				violations = append(violations, fmt.Sprintf("'%s' is required", join(path, req)))
+			}
+		}
+		// sort the properties to report violations in a deterministic order
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			v, found := obj[name]
+			if !found || (v == nil && !s.isRequired(name)) {
+				// an optional property that renders empty is same as unset
+				continue
+			}
+			violations = append(violations, s.Properties[name].validate(join(path, name), v)...)
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("'%s' must be a string", field)}
+		}
+		if len(strings.TrimSpace(str)) < s.MinLength {
+			violations = append(violations, fmt.Sprintf("'%s' must not be empty", field))
+		}
+	}
+	return
+}
+
+// isRequired returns true if the provided property is required as per this
+// schema
+func (s jsonSchema) isRequired(name string) bool {
+	for _, req := range s.Required {
+		if req == name {
+			return true
+		}
+	}
+	return false
+}
+
+// join returns the dotted path of the provided field
+func join(path, field string) string {
+	if len(path) == 0 {
+		return field
+	}
+	return path + "." + field
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	install "github.com/openebs/maya/pkg/install/v1alpha1"
+	"github.com/openebs/maya/pkg/template"
+)
+
+func TestValidateMeta(t *testing.T) {
+	tests := map[string]struct {
+		meta       string
+		violations []string
+		isErr      bool
+	}{
+		"validate meta - +ve test case - valid meta": {
+			meta: "id: cvolcreate\nkind: Service\napiVersion: v1\naction: put",
+		},
+		"validate meta - +ve test case - command without action": {
+			meta: "id: createcstorsnap\nkind: Command",
+		},
+		"validate meta - +ve test case - empty optional property": {
+			meta: "id: deletecsp\nkind: CStorPool\napiVersion: openebs.io/v1alpha1\naction: delete\nobjectName:",
+		},
+		"validate meta - -ve test case - missing action": {
+			meta:       "id: cvolcreate\nkind: Service\napiVersion: v1",
+			violations: []string{"'action' is required"},
+			isErr:      true,
+		},
+		"validate meta - -ve test case - empty id & kind": {
+			meta:       "id: \"\"\nkind: \" \"\naction: get",
+			violations: []string{"'id' must not be empty", "'kind' must not be empty"},
+			isErr:      true,
+		},
+		"validate meta - -ve test case - non string id": {
+			meta:       "id: 123\nkind: Command\naction: get",
+			violations: []string{"'id' must be a string"},
+			isErr:      true,
+		},
+		"validate meta - -ve test case - empty meta": {
+			meta:       "",
+			violations: []string{"'meta' must be an object"},
+			isErr:      true,
+		},
+		"validate meta - -ve test case - malformed meta": {
+			meta:  "id: [cvolcreate",
+			isErr: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateMeta([]byte(mock.meta))
+			if !mock.isErr {
+				if err != nil {
+					t.Fatalf("failed to test validate meta: expected 'no error': actual '%s'", err)
+				}
+				return
+			}
+
+			verr, ok := err.(*MetaValidationError)
+			if !ok {
+				t.Fatalf("failed to test validate meta: expected 'meta validation error': actual '%#v'", err)
+			}
+			if mock.violations != nil && !reflect.DeepEqual(verr.Violations, mock.violations) {
+				t.Fatalf("failed to test validate meta: expected violations '%v': actual '%v'", mock.violations, verr.Violations)
+			}
+		})
+	}
+}
+
+func TestValidateMetaOfInstalledRunTasks(t *testing.T) {
+	values := map[string]interface{}{
+		string(v1alpha1.TaskResultTLP): map[string]interface{}{},
+		string(v1alpha1.ListItemsTLP):  map[string]interface{}{},
+	}
+
+	count := 0
+	for _, artifact := range install.RegisteredArtifactsFor070().Items {
+		if install.IsNotRunTask(artifact) {
+			continue
+		}
+		var runtask v1alpha1.RunTask
+		err := yaml.Unmarshal([]byte(artifact.Doc), &runtask)
+		if err != nil {
+			t.Fatalf("failed to test installed runtasks: expected 'no error': actual '%s'", err)
+		}
+		if runtask.Kind != "RunTask" {
+			// e.g. the RunTask CRD
+			continue
+		}
+		count++
+
+		t.Run(runtask.Name, func(t *testing.T) {
+			meta, err := template.AsTemplatedBytes("MetaTaskSpec", runtask.Spec.Meta, values)
+			if err != nil {
+				t.Fatalf("failed to test installed runtask '%s': expected 'no error': actual '%s'", runtask.Name, err)
+			}
+			err = validateMeta(meta)
+			if err != nil {
+				t.Fatalf("failed to test installed runtask '%s': expected 'no error': actual '%s'", runtask.Name, err)
+			}
+		})
+	}
+	if count == 0 {
+		t.Fatalf("failed to test installed runtasks: expected 'runtasks': actual 'none'")
+	}
+}
@@ -138,7 +138,7 @@ func TestNewMetaTaskExecutor(t *testing.T) {
 
 	for name, mock := range tests {
 		t.Run(name, func(t *testing.T) {
-			mte, err := newMetaTaskExecutor(mock.yaml, mock.values)
+			mte, err := newMetaTaskExecutor(mock.yaml, mock.values, nil)
 
 			if err != nil && !mock.isErr {
 				t.Fatalf("failed to test new meta executor: expected 'no error': actual '%s'", err.Error())
@@ -267,6 +267,55 @@ retry: "5,2z"
 	}
 }
 
+func TestGetTimeout(t *testing.T) {
+	tests := map[string]struct {
+		yaml            string
+		expectedTimeout time.Duration
+	}{
+		"get timeout - +ve test case - valid meta task yaml with valid timeout": {
+			yaml: `
+id: okid
+apiVersion: v1
+kind: Service
+timeout: "30s"
+`,
+			expectedTimeout: 30 * time.Second,
+		},
+		"get timeout - +ve test case - valid meta task yaml without timeout": {
+			yaml: `
+id: okid
+apiVersion: v1
+kind: Service
+`,
+			expectedTimeout: 0,
+		},
+		"get timeout - -ve test case - valid meta task yaml with invalid timeout": {
+			yaml: `
+id: okid
+apiVersion: v1
+kind: Service
+timeout: "30z"
+`,
+			expectedTimeout: 0,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			var m MetaTaskSpec
+			yaml.Unmarshal([]byte(mock.yaml), &m)
+
+			mte := &metaTaskExecutor{
+				metaTask: m,
+			}
+
+			if mte.getTimeout() != mock.expectedTimeout {
+				t.Fatalf("failed to test get timeout: expected timeout '%s': actual timeout '%s'", mock.expectedTimeout, mte.getTimeout())
+			}
+		})
+	}
+}
+
 func TestGetObjectName(t *testing.T) {
 	tests := map[string]struct {
 		yaml       string
@@ -1526,3 +1575,38 @@ action: {{ .action }}
 		})
 	}
 }
+
+func TestIsSkip(t *testing.T) {
+	tests := map[string]struct {
+		runIf    string
+		disable  string
+		expected bool
+	}{
+		"is skip - +ve test case - false":          {runIf: "false", expected: true},
+		"is skip - +ve test case - capital false":  {runIf: "False", expected: true},
+		"is skip - +ve test case - zero":           {runIf: "0", expected: true},
+		"is skip - -ve test case - empty":          {runIf: "", expected: false},
+		"is skip - -ve test case - true":           {runIf: "true", expected: false},
+		"is skip - -ve test case - non boolean":    {runIf: "snapshot-1", expected: false},
+		"is skip - +ve test case - spaced false":   {runIf: "  false ", expected: true},
+		"is skip - -ve test case - no value":       {runIf: "<no value>", expected: false},
+		"is skip - +ve test case - disable true":   {disable: "true", expected: true},
+		"is skip - +ve test case - disable yes":    {disable: "Yes", expected: true},
+		"is skip - +ve test case - disable wins":   {runIf: "true", disable: "1", expected: true},
+		"is skip - -ve test case - disable false":  {disable: "false", expected: false},
+		"is skip - -ve test case - disable empty":  {disable: "", expected: false},
+		"is skip - -ve test case - disable random": {disable: "clone", expected: false},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			mte := &metaTaskExecutor{
+				metaTask: MetaTaskSpec{MetaTaskProps: MetaTaskProps{RunIf: MetaTaskPredicate(mock.runIf), Disable: MetaTaskPredicate(mock.disable)}},
+			}
+
+			if mte.isSkip() != mock.expected {
+				t.Fatalf("failed to test is skip: expected '%t': actual '%t'", mock.expected, mte.isSkip())
+			}
+		})
+	}
+}
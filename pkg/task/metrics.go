@@ -0,0 +1,292 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricLabels are the labels of the task metrics
+var metricLabels = []string{"action", "kind"}
+
+// runnerMetrics holds the prometheus metrics of task group runners
+type runnerMetrics struct {
+	// started counts the tasks whose execution started
+	started *prometheus.CounterVec
+	// succeeded counts the tasks that were executed successfully
+	succeeded *prometheus.CounterVec
+	// failed counts the tasks whose execution failed
+	failed *prometheus.CounterVec
+	// rolledBack counts the rollback tasks that were executed
+	rolledBack *prometheus.CounterVec
+	// duration observes the duration taken to execute the tasks
+	duration *prometheus.HistogramVec
+}
+
+// newRunnerMetrics returns a new instance of runnerMetrics whose metrics are
+// registered against the provided registerer
+//
+// NOTE:
+//  Metrics that were registered previously e.g. by another instrumented
+// runner are reused
+func newRunnerMetrics(reg prometheus.Registerer) *runnerMetrics {
+	return &runnerMetrics{
+		started: registerCounterVec(reg, prometheus.CounterOpts{
+			Namespace: "openebs",
+			Name:      "tasks_started_total",
+			Help:      "Number of runtasks whose execution started",
+		}, metricLabels...),
+		succeeded: registerCounterVec(reg, prometheus.CounterOpts{
+			Namespace: "openebs",
+			Name:      "tasks_succeeded_total",
+			Help:      "Number of runtasks that were executed successfully",
+		}, metricLabels...),
+		failed: registerCounterVec(reg, prometheus.CounterOpts{
+			Namespace: "openebs",
+			Name:      "tasks_failed_total",
+			Help:      "Number of runtasks whose execution failed",
+		}, metricLabels...),
+		rolledBack: registerCounterVec(reg, prometheus.CounterOpts{
+			Namespace: "openebs",
+			Name:      "tasks_rolledback_total",
+			Help:      "Number of rollback runtasks that were executed",
+		}, metricLabels...),
+		duration: registerHistogramVec(reg, prometheus.HistogramOpts{
+			Namespace: "openebs",
+			Name:      "task_duration_seconds",
+			Help:      "Duration taken to execute a runtask",
+			Buckets:   prometheus.DefBuckets,
+		}, metricLabels...),
+	}
+}
+
+// registerCounterVec registers a counter vector built from the provided
+// options & labels. An already registered counter vector is returned as is.
+func registerCounterVec(reg prometheus.Registerer, opts prometheus.CounterOpts, labels ...string) *prometheus.CounterVec {
+	c := prometheus.NewCounterVec(opts, labels)
+	err := reg.Register(c)
+	if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+		if existing, ok := are.ExistingCollector.(*prometheus.CounterVec); ok {
+			return existing
+		}
+	}
+	if err != nil {
+		packageLogger().Error(err, "failed to register metric", "metric", opts.Name)
+	}
+	return c
+}
+
+// registerHistogramVec registers a histogram vector built from the provided
+// options & labels. An already registered histogram vector is returned as is.
+func registerHistogramVec(reg prometheus.Registerer, opts prometheus.HistogramOpts, labels ...string) *prometheus.HistogramVec {
+	h := prometheus.NewHistogramVec(opts, labels)
+	err := reg.Register(h)
+	if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+		if existing, ok := are.ExistingCollector.(*prometheus.HistogramVec); ok {
+			return existing
+		}
+	}
+	if err != nil {
+		packageLogger().Error(err, "failed to register metric", "metric", opts.Name)
+	}
+	return h
+}
+
+// NewInstrumentedTaskGroupRunner returns a new task group runner that
+// records prometheus metrics of its tasks against the provided registerer
+func NewInstrumentedTaskGroupRunner(reg prometheus.Registerer, opts ...TaskGroupRunnerOption) *TaskGroupRunner {
+	m := NewTaskGroupRunner(opts...)
+	m.metrics = newRunnerMetrics(reg)
+	return m
+}
+
+// taskMetricLabels returns the metric label values of the provided task
+func taskMetricLabels(te *taskExecutor) []string {
+	meta := te.metaTaskExec.getMetaInfo()
+	return []string{string(meta.Action), meta.Kind}
+}
+
+// taskStarted records the start of the provided task's execution
+//
+// NOTE:
+//  Nothing is recorded if the runner is not instrumented i.e. the metrics
+// are nil. This holds true for all the recording methods.
+func (rm *runnerMetrics) taskStarted(te *taskExecutor) {
+	if rm == nil {
+		return
+	}
+	rm.started.WithLabelValues(taskMetricLabels(te)...).Inc()
+}
+
+// taskDone records the outcome & the duration of the provided task's
+// execution
+//
+// NOTE:
+//  This is meant to be deferred. A panic while executing the task is
+// recorded as a failure & is then propagated.
+func (rm *runnerMetrics) taskDone(te *taskExecutor, started time.Time, err *error) {
+	if rm == nil {
+		return
+	}
+
+	labels := taskMetricLabels(te)
+	rm.duration.WithLabelValues(labels...).Observe(time.Since(started).Seconds())
+
+	if r := recover(); r != nil {
+		rm.failed.WithLabelValues(labels...).Inc()
+		panic(r)
+	}
+
+	if *err != nil {
+		rm.failed.WithLabelValues(labels...).Inc()
+		return
+	}
+	rm.succeeded.WithLabelValues(labels...).Inc()
+}
+
+// rollbackTaskDone records the execution of the provided rollback task
+//
+// NOTE:
+//  This is meant to be deferred so that a rollback task that panics is
+// recorded as well
+func (rm *runnerMetrics) rollbackTaskDone(rte *taskExecutor) {
+	if rm == nil {
+		return
+	}
+	rm.rolledBack.WithLabelValues(taskMetricLabels(rte)...).Inc()
+}
+
+// MetricsRecorder records the metrics of a task group runner
+//
+// NOTE:
+//  Tasks are identified by their identity & not by the names of their
+// objects to keep the cardinality of the metrics bounded
+type MetricsRecorder interface {
+	// ObserveTask records the outcome & the duration of the execution of the
+	// task with the provided identity
+	ObserveTask(taskID string, err error, duration time.Duration)
+	// RollbackTriggered records a rollback of the executed tasks
+	RollbackTriggered()
+	// FallbackTriggered records a fallback to the fallback templates
+	FallbackTriggered()
+}
+
+// PrometheusMetricsRecorder is a metrics recorder that records the metrics of
+// a task group runner as prometheus metrics
+type PrometheusMetricsRecorder struct {
+	// executions counts the executed tasks by their identity & result
+	executions *prometheus.CounterVec
+	// duration observes the duration taken to execute the tasks by their
+	// identity
+	duration *prometheus.HistogramVec
+	// rollbacks counts the rollbacks that were triggered
+	rollbacks *prometheus.CounterVec
+	// fallbacks counts the fallbacks that were triggered
+	fallbacks *prometheus.CounterVec
+}
+
+// NewPrometheusMetricsRecorder returns a new instance of
+// PrometheusMetricsRecorder whose metrics are registered against the provided
+// registerer
+//
+// NOTE:
+//  Metrics that were registered previously e.g. by another recorder are
+// reused
+func NewPrometheusMetricsRecorder(reg prometheus.Registerer) *PrometheusMetricsRecorder {
+	return &PrometheusMetricsRecorder{
+		executions: registerCounterVec(reg, prometheus.CounterOpts{
+			Namespace: "maya",
+			Name:      "runtask_executions_total",
+			Help:      "Number of runtask executions by task & result",
+		}, "task", "result"),
+		duration: registerHistogramVec(reg, prometheus.HistogramOpts{
+			Namespace: "maya",
+			Name:      "runtask_duration_seconds",
+			Help:      "Duration taken to execute a runtask",
+			Buckets:   prometheus.DefBuckets,
+		}, "task"),
+		rollbacks: registerCounterVec(reg, prometheus.CounterOpts{
+			Namespace: "maya",
+			Name:      "runtask_rollbacks_total",
+			Help:      "Number of rollbacks triggered by task group runners",
+		}),
+		fallbacks: registerCounterVec(reg, prometheus.CounterOpts{
+			Namespace: "maya",
+			Name:      "runtask_fallbacks_total",
+			Help:      "Number of fallbacks triggered by task group runners",
+		}),
+	}
+}
+
+// ObserveTask records the outcome & the duration of a task's execution
+func (r *PrometheusMetricsRecorder) ObserveTask(taskID string, err error, duration time.Duration) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	r.executions.WithLabelValues(taskID, result).Inc()
+	r.duration.WithLabelValues(taskID).Observe(duration.Seconds())
+}
+
+// RollbackTriggered records a rollback of the executed tasks
+func (r *PrometheusMetricsRecorder) RollbackTriggered() {
+	r.rollbacks.WithLabelValues().Inc()
+}
+
+// FallbackTriggered records a fallback to the fallback templates
+func (r *PrometheusMetricsRecorder) FallbackTriggered() {
+	r.fallbacks.WithLabelValues().Inc()
+}
+
+// observeTask records the outcome & the duration of the provided task's
+// execution against this runner's metrics recorder if any
+//
+// NOTE:
+//  This is meant to be deferred. A panic while executing the task is
+// recorded as a failure & is then propagated.
+func (m *TaskGroupRunner) observeTask(te *taskExecutor, started time.Time, err *error) {
+	if m.metricsRecorder == nil {
+		return
+	}
+
+	if r := recover(); r != nil {
+		m.metricsRecorder.ObserveTask(te.getTaskIdentity(), fmt.Errorf("panic: %v", r), time.Since(started))
+		panic(r)
+	}
+	m.metricsRecorder.ObserveTask(te.getTaskIdentity(), *err, time.Since(started))
+}
+
+// recordRollbackTriggered records a rollback of the executed tasks against
+// this runner's metrics recorder if any
+func (m *TaskGroupRunner) recordRollbackTriggered() {
+	if m.metricsRecorder == nil {
+		return
+	}
+	m.metricsRecorder.RollbackTriggered()
+}
+
+// recordFallbackTriggered records a fallback to the fallback templates
+// against this runner's metrics recorder if any
+func (m *TaskGroupRunner) recordFallbackTriggered() {
+	if m.metricsRecorder == nil {
+		return
+	}
+	m.metricsRecorder.FallbackTriggered()
+}
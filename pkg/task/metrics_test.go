@@ -0,0 +1,237 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gatherCounts returns the sum of the samples of each gathered metric
+func gatherCounts(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %s", err)
+	}
+
+	counts := map[string]float64{}
+	for _, f := range families {
+		for _, metric := range f.GetMetric() {
+			if metric.GetCounter() != nil {
+				counts[f.GetName()] += metric.GetCounter().GetValue()
+			}
+			if metric.GetHistogram() != nil {
+				counts[f.GetName()] += float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return counts
+}
+
+func TestInstrumentedTaskGroupRunner(t *testing.T) {
+	tests := map[string]struct {
+		posts    []string
+		expected map[string]float64
+	}{
+		"instrumented runner - +ve test case - all tasks succeed": {
+			posts: []string{
+				`{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+				`{{- "t2-obj" | saveAs "t2.objectName" .TaskResult | noop -}}`,
+			},
+			expected: map[string]float64{
+				"openebs_tasks_started_total":   2,
+				"openebs_tasks_succeeded_total": 2,
+				"openebs_task_duration_seconds": 2,
+			},
+		},
+		"instrumented runner - -ve test case - second task fails": {
+			posts: []string{
+				`{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+				`{{- fail "t2 failed" -}}`,
+			},
+			expected: map[string]float64{
+				"openebs_tasks_started_total":    2,
+				"openebs_tasks_succeeded_total":  1,
+				"openebs_tasks_failed_total":     1,
+				"openebs_tasks_rolledback_total": 1,
+				"openebs_task_duration_seconds":  2,
+			},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			reg := prometheus.NewRegistry()
+			r := NewInstrumentedTaskGroupRunner(reg)
+			for idx, post := range mock.posts {
+				r.AddRunTask(fakeCommandRunTask(fmt.Sprintf("t%d", idx+1), post))
+			}
+
+			r.Run(context.Background(), fakeTemplateValues())
+			actual := gatherCounts(t, reg)
+			for metric, count := range mock.expected {
+				if actual[metric] != count {
+					t.Fatalf("failed to test instrumented runner: expected '%s' to be '%v': actual '%v'", metric, count, actual[metric])
+				}
+			}
+		})
+	}
+}
+
+func TestRecordTaskMetricsOnPanic(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	r := NewInstrumentedTaskGroupRunner(reg)
+	te := &taskExecutor{
+		metaTaskExec: &metaTaskExecutor{metaTask: MetaTaskSpec{Action: PutTA}},
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("failed to test record task metrics on panic: expected 'panic': actual 'no panic'")
+			}
+		}()
+
+		var err error
+		defer r.metrics.taskDone(te, time.Now(), &err)
+		panic("task panicked")
+	}()
+
+	if actual := gatherCounts(t, reg)["openebs_tasks_failed_total"]; actual != 1 {
+		t.Fatalf("failed to test record task metrics on panic: expected '1' failure: actual '%v'", actual)
+	}
+}
+
+func TestNewRunnerMetricsReuse(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	first := newRunnerMetrics(reg)
+	second := newRunnerMetrics(reg)
+	if first.started != second.started {
+		t.Fatalf("failed to test runner metrics reuse: expected registered metrics to be reused")
+	}
+}
+
+// fakeMetricsRecorder records the observed task identities & counts the
+// triggered rollbacks & fallbacks
+type fakeMetricsRecorder struct {
+	succeeded []string
+	failed    []string
+	rollbacks int
+	fallbacks int
+}
+
+func (r *fakeMetricsRecorder) ObserveTask(taskID string, err error, duration time.Duration) {
+	if err != nil {
+		r.failed = append(r.failed, taskID)
+		return
+	}
+	r.succeeded = append(r.succeeded, taskID)
+}
+
+func (r *fakeMetricsRecorder) RollbackTriggered() { r.rollbacks++ }
+
+func (r *fakeMetricsRecorder) FallbackTriggered() { r.fallbacks++ }
+
+func TestMetricsRecorder(t *testing.T) {
+	tests := map[string]struct {
+		posts             []string
+		fallbacks         []string
+		expectedSucceeded int
+		expectedFailed    int
+		expectedRollbacks int
+		expectedFallbacks int
+	}{
+		"metrics recorder - +ve test case - all tasks succeed": {
+			posts: []string{
+				`{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+				`{{- "t2-obj" | saveAs "t2.objectName" .TaskResult | noop -}}`,
+			},
+			expectedSucceeded: 2,
+		},
+		"metrics recorder - -ve test case - rollback": {
+			posts: []string{
+				`{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+				`{{- fail "t2 failed" -}}`,
+			},
+			expectedSucceeded: 1,
+			expectedFailed:    1,
+			expectedRollbacks: 1,
+		},
+		"metrics recorder - -ve test case - fallback": {
+			posts: []string{
+				`{{- true | versionMismatchErr "not supported" | saveIf "t1.versionMismatchErr" .TaskResult | noop -}}`,
+			},
+			fallbacks:         []string{"cast-legacy"},
+			expectedFailed:    1,
+			expectedFallbacks: 1,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := &fakeMetricsRecorder{}
+			r := NewTaskGroupRunner()
+			r.SetMetricsRecorder(recorder)
+			r.SetFallback(mock.fallbacks)
+			r.fallbackFn = func(ctx context.Context, castemplate string, values map[string]interface{}) ([]byte, error) {
+				return []byte("fallback"), nil
+			}
+			for idx, post := range mock.posts {
+				r.AddRunTask(fakeCommandRunTask(fmt.Sprintf("t%d", idx+1), post))
+			}
+
+			r.Run(context.Background(), fakeTemplateValues())
+			if len(recorder.succeeded) != mock.expectedSucceeded || len(recorder.failed) != mock.expectedFailed {
+				t.Fatalf("failed to test metrics recorder: expected '%d' succeeded & '%d' failed: actual '%v' & '%v'", mock.expectedSucceeded, mock.expectedFailed, recorder.succeeded, recorder.failed)
+			}
+			if recorder.rollbacks != mock.expectedRollbacks || recorder.fallbacks != mock.expectedFallbacks {
+				t.Fatalf("failed to test metrics recorder: expected '%d' rollbacks & '%d' fallbacks: actual '%d' & '%d'", mock.expectedRollbacks, mock.expectedFallbacks, recorder.rollbacks, recorder.fallbacks)
+			}
+		})
+	}
+}
+
+func TestPrometheusMetricsRecorder(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	r := NewPrometheusMetricsRecorder(reg)
+	r.ObserveTask("t1", nil, time.Second)
+	r.ObserveTask("t1", fmt.Errorf("t1 failed"), time.Second)
+	r.RollbackTriggered()
+	r.FallbackTriggered()
+
+	expected := map[string]float64{
+		"maya_runtask_executions_total": 2,
+		"maya_runtask_duration_seconds": 2,
+		"maya_runtask_rollbacks_total":  1,
+		"maya_runtask_fallbacks_total":  1,
+	}
+	actual := gatherCounts(t, reg)
+	for metric, count := range expected {
+		if actual[metric] != count {
+			t.Fatalf("failed to test prometheus metrics recorder: expected '%s' to be '%v': actual '%v'", metric, count, actual[metric])
+		}
+	}
+
+	// recorder that reuses the registered metrics
+	if NewPrometheusMetricsRecorder(reg).executions != r.executions {
+		t.Fatalf("failed to test prometheus metrics recorder: expected registered metrics to be reused")
+	}
+}
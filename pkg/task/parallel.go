@@ -0,0 +1,178 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+)
+
+// mergeTaskValues merges the template values changed by a task from the
+// task's isolated template values into the group's template values. Base
+// is the copy of template values the task's isolated values were made from.
+//
+// NOTE:
+//  Values that are set, changed or removed by the task e.g.
+// .TaskResult.<taskID> or the items saved at .ListItems are merged. Maps
+// are merged by their keys. Hence values set at different keys of a map by
+// different tasks are all retained.
+func (m *TaskGroupRunner) mergeTaskValues(values, base, isolated map[string]interface{}) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	mergeChangedValues(values, base, isolated)
+}
+
+// mergeChangedValues sets the values of changed that differ from the ones
+// in base into dest & removes the keys of base that are not found in changed
+// from dest
+func mergeChangedValues(dest, base, changed map[string]interface{}) {
+	for k, cv := range changed {
+		bv, found := base[k]
+		if found && reflect.DeepEqual(bv, cv) {
+			continue
+		}
+		bm, bok := bv.(map[string]interface{})
+		if !found {
+			// a map set by this task is merged with the one that may have
+			// been set by another task
+			bm, bok = map[string]interface{}{}, true
+		}
+		cm, cok := cv.(map[string]interface{})
+		dm, dok := dest[k].(map[string]interface{})
+		if bok && cok && dok {
+			mergeChangedValues(dm, bm, cm)
+			continue
+		}
+		dest[k] = cv
+	}
+	for k := range base {
+		if _, found := changed[k]; !found {
+			delete(dest, k)
+		}
+	}
+}
+
+// runTasksInParallel executes the provided tasks concurrently. The number of
+// tasks that get executed at the same time is bounded by this runner's
+// concurrency. All the tasks are executed at the same time if concurrency is
+// not set.
+//
+// NOTE:
+//  Each task is executed against its own copy of template values. The values
+// changed by each task are merged back into the provided template values once
+// the task is executed.
+//
+// NOTE:
+//  All the task executors are built & the task identities are verified for
+// uniqueness before any of these tasks get executed
+//
+// NOTE:
+//  Failure of any task cancels the execution of the tasks that are yet to be
+// executed. Rollback of the tasks that were executed is planned irrespective
+// of their failure.
+//
+// NOTE:
+//  All the tasks are executed if the runner continues on error. The errors of
+// all the failed tasks are returned in this case.
+func (m *TaskGroupRunner) runTasksInParallel(ctx context.Context, runtasks []*v1alpha1.RunTask, values map[string]interface{}) (err error) {
+	// base is the copy of template values each task starts with
+	base := util.DeepCopyMap(values)
+	executors := make([]*taskExecutor, 0, len(runtasks))
+	for _, runtask := range runtasks {
+		te, err := m.prepareATask(runtask, util.DeepCopyMap(values))
+		if err != nil {
+			m.postTaskRun(values, "", err)
+			return newTaskExecutionError(runtask, nil, err)
+		}
+		executors = append(executors, te)
+	}
+
+	workers := m.concurrency
+	if workers < 1 || workers > len(executors) {
+		workers = len(executors)
+	}
+
+	// scheduling is cancelled on failure of any task; tasks that are already
+	// being executed are allowed to complete
+	schedule, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(executors))
+		// sem bounds the number of tasks being executed at the same time
+		sem = make(chan struct{}, workers)
+	)
+
+	for idx, te := range executors {
+		select {
+		case sem <- struct{}{}:
+		case <-schedule.Done():
+		}
+
+		if schedule.Err() != nil {
+			// stop scheduling the remaining tasks since one of the executed
+			// tasks has failed or the runner has timed out
+			break
+		}
+
+		wg.Add(1)
+		go func(idx int, te *taskExecutor) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if schedule.Err() != nil {
+				return
+			}
+
+			errs[idx] = newTaskExecutionError(te.runtask, te, m.resumeOrExecute(ctx, te))
+			m.mergeTaskValues(values, base, te.templateValues)
+
+			if errs[idx] != nil && m.isAbort(errs[idx]) {
+				cancel()
+			}
+		}(idx, te)
+	}
+
+	wg.Wait()
+
+	if m.isContinueOnError() {
+		var merr *multierror.Error
+		for _, e := range errs {
+			if e != nil {
+				merr = multierror.Append(merr, e)
+			}
+		}
+		return merr.ErrorOrNil()
+	}
+
+	// return the error of the earliest task (as per the task order) that
+	// failed
+	for _, e := range errs {
+		if e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
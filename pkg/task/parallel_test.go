@@ -0,0 +1,177 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+)
+
+func TestRunTasksInParallel(t *testing.T) {
+	tests := map[string]struct {
+		ids         []string
+		concurrency int
+		isErr       bool
+	}{
+		"parallel run - +ve test case - unique ids": {
+			ids:         []string{"t1", "t2", "t3", "t4"},
+			concurrency: 2,
+		},
+		"parallel run - +ve test case - workers more than tasks": {
+			ids:         []string{"t1", "t2"},
+			concurrency: 5,
+		},
+		"parallel run - -ve test case - duplicate ids": {
+			ids:         []string{"t1", "t2", "t1"},
+			concurrency: 3,
+			isErr:       true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.SetConcurrency(mock.concurrency)
+			for _, id := range mock.ids {
+				post := `{{- "` + id + `-obj" | saveAs "` + id + `.objectName" .TaskResult | noop -}}`
+				r.AddRunTask(fakeCommandRunTask(id, post))
+			}
+
+			values := fakeTemplateValues()
+			err := r.runAllTasks(context.Background(), values)
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("failed to test parallel run: expected 'error': actual 'no error'")
+				}
+				if len(r.rollbacks.planned) != 0 {
+					t.Fatalf("failed to test parallel run: expected no task execution: actual rollbacks '%d'", len(r.rollbacks.planned))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to test parallel run: expected 'no error': actual '%s'", err)
+			}
+
+			for _, id := range mock.ids {
+				actual := util.GetNestedString(values, string(v1alpha1.TaskResultTLP), id, string(v1alpha1.ObjectNameTRTP))
+				if actual != id+"-obj" {
+					t.Fatalf("failed to test parallel run: expected result '%s-obj': actual '%s'", id, actual)
+				}
+			}
+
+			if len(r.rollbacks.planned) != len(mock.ids) {
+				t.Fatalf("failed to test parallel run: expected rollbacks '%d': actual '%d'", len(mock.ids), len(r.rollbacks.planned))
+			}
+		})
+	}
+}
+
+func TestAddParallelRunTasks(t *testing.T) {
+	tests := map[string]struct {
+		before   []string
+		parallel []string
+		after    []string
+		stages   int
+		isErr    bool
+	}{
+		"add parallel tasks - +ve test case - mixed with sequential tasks": {
+			before:   []string{"t1"},
+			parallel: []string{"t2", "t3"},
+			after:    []string{"t4"},
+			stages:   3,
+		},
+		"add parallel tasks - +ve test case - parallel tasks only": {
+			parallel: []string{"t1", "t2", "t3"},
+			stages:   1,
+		},
+		"add parallel tasks - -ve test case - nil meta": {
+			parallel: []string{"t1", ""},
+			isErr:    true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			for _, id := range mock.before {
+				r.AddRunTask(fakeCommandRunTask(id, ""))
+			}
+
+			var group []*v1alpha1.RunTask
+			for _, id := range mock.parallel {
+				rt := fakeCommandRunTask(id, "")
+				if id == "" {
+					rt.Spec.Meta = ""
+				}
+				group = append(group, rt)
+			}
+			err := r.AddParallelRunTasks(group...)
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("failed to test add parallel tasks: expected 'error': actual 'no error'")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to test add parallel tasks: expected 'no error': actual '%s'", err)
+			}
+
+			for _, id := range mock.after {
+				r.AddRunTask(fakeCommandRunTask(id, ""))
+			}
+
+			if len(r.stages()) != mock.stages {
+				t.Fatalf("failed to test add parallel tasks: expected stages '%d': actual '%d'", mock.stages, len(r.stages()))
+			}
+		})
+	}
+}
+
+func TestRunParallelRunTasksFailure(t *testing.T) {
+	failing := func(id string) *v1alpha1.RunTask {
+		post := `{{- "` + id + `-obj" | saveAs "` + id + `.objectName" .TaskResult | noop -}}` +
+			`{{- fail "` + id + ` failed" -}}`
+		return fakeCommandRunTask(id, post)
+	}
+
+	r := NewTaskGroupRunner()
+	r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+	r.AddParallelRunTasks(failing("t2"), failing("t3"))
+	r.AddRunTask(fakeCommandRunTask("t4", `{{- "t4-obj" | saveAs "t4.objectName" .TaskResult | noop -}}`))
+
+	values := fakeTemplateValues()
+	err := r.runAllTasks(context.Background(), values)
+	if err == nil {
+		t.Fatalf("failed to test parallel tasks failure: expected 'error': actual 'no error'")
+	}
+
+	// t1 and the parallel tasks that got executed are planned for rollback;
+	// one of the failed parallel tasks may be cancelled before its execution
+	if len(r.rollbacks.planned) < 2 || len(r.rollbacks.planned) > 3 {
+		t.Fatalf("failed to test parallel tasks failure: expected rollbacks '2' or '3': actual '%d'", len(r.rollbacks.planned))
+	}
+
+	if util.GetNestedString(values, string(v1alpha1.TaskResultTLP), "t4", string(v1alpha1.ObjectNameTRTP)) != "" {
+		t.Fatalf("failed to test parallel tasks failure: expected task 't4' not to be executed")
+	}
+
+	// rollback of all the planned tasks should go through
+	r.rollback(context.Background())
+}
@@ -21,7 +21,6 @@ import (
 	"fmt"
 
 	"github.com/ghodss/yaml"
-	"github.com/openebs/maya/pkg/template"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -45,6 +44,13 @@ var taskPatchTypes = map[TaskPatchType]types.PatchType{
 	StrategicTPT: types.StrategicMergePatchType,
 }
 
+// patchActionTypes maps the patch action variants to their patch types
+var patchActionTypes = map[MetaTaskAction]TaskPatchType{
+	PatchStrategicTA: StrategicTPT,
+	PatchMergeTA:     MergeTPT,
+	PatchJSONTA:      JsonTPT,
+}
+
 // TaskPatches will consist of patches that gets applied
 // against the task object
 type TaskPatch struct {
@@ -71,15 +77,13 @@ type TaskPatch struct {
 	Specs string `json:"pspec"`
 }
 
-// asTaskPatch runs go template against the yaml document & converts it
-// to a TaskPatch type
-func asTaskPatch(context, yml string, values map[string]interface{}) (patch TaskPatch, err error) {
-	b, err := template.AsTemplatedBytes(context, yml, values)
-	if err != nil {
-		return
+// toTaskPatch converts the templated yaml or json document to a TaskPatch.
+// The document is set as the specifications of a TaskPatch of the provided
+// type if any or else is unmarshalled into a TaskPatch.
+func toTaskPatch(b []byte, patchType TaskPatchType) (patch TaskPatch, err error) {
+	if len(patchType) != 0 {
+		return TaskPatch{Type: patchType, Specs: string(b)}, nil
 	}
-
-	// unmarshall into TaskPatch
 	err = yaml.Unmarshal(b, &patch)
 	return
 }
@@ -104,7 +108,26 @@ func newTaskPatchExecutor(patch TaskPatch) (*taskPatchExecutor, error) {
 
 // toJson converts the patch in yaml document format to corresponding
 // json document
+//
+// NOTE:
+//  A json patch is a list of operations while other patches are objects. A
+// json patch having a single operation can be set as an object.
 func (p *taskPatchExecutor) toJson() ([]byte, error) {
+	if p.patch.Type == JsonTPT {
+		var doc interface{}
+		err := yaml.Unmarshal([]byte(p.patch.Specs), &doc)
+		if err != nil {
+			return nil, err
+		}
+		switch ops := doc.(type) {
+		case []interface{}:
+			return json.Marshal(ops)
+		case map[string]interface{}:
+			return json.Marshal([]interface{}{ops})
+		}
+		return nil, fmt.Errorf("failed to convert json patch: expected a list of patch operations: actual '%T'", doc)
+	}
+
 	m := map[string]interface{}{}
 	err := yaml.Unmarshal([]byte(p.patch.Specs), &m)
 	if err != nil {
@@ -0,0 +1,166 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// defaultCASRetries is the no. of times a patch-cas task is re-attempted on
+// conflict if its meta specifications do not set casRetries
+const defaultCASRetries = 3
+
+// casPatcher fetches & patches the object of a patch-cas task
+type casPatcher struct {
+	// get fetches the object with the provided name
+	get func(name string) ([]byte, error)
+	// patch patches the object with the provided name
+	patch func(name string, patchType types.PatchType, patches []byte) ([]byte, error)
+}
+
+// patchCAS patches the object of this task only if the object was not
+// modified after it was fetched. The resource version of the fetched object
+// is set in the patch so that K8s rejects the patch with a conflict if the
+// object was modified. The patch is re-attempted against the latest object
+// on conflict.
+//
+// NOTE:
+//  The patched object is set at .JsonResult & its resource version is set at
+// .TaskResult.<TaskIdentity>.resourceVersion
+func (m *taskExecutor) patchCAS(context string, p casPatcher) (err error) {
+	patch, err := m.asPatch(context)
+	if err != nil {
+		return
+	}
+	if patch.Type == JsonTPT {
+		return fmt.Errorf("failed to patch-cas '%s': patch type '%s' is not supported", m.getTaskObjectName(), patch.Type)
+	}
+
+	pe, err := newTaskPatchExecutor(patch)
+	if err != nil {
+		return
+	}
+
+	raw, err := pe.toJson()
+	if err != nil {
+		return
+	}
+
+	name := m.getTaskObjectName()
+	retries := m.metaTaskExec.getCASRetries()
+	for attempt := 0; ; attempt++ {
+		var patched []byte
+		patched, err = m.patchCASOnce(p, name, pe.patchType(), raw)
+		if err == nil {
+			return m.setCASResult(patched)
+		}
+		if !k8serrors.IsConflict(errors.Cause(err)) || attempt >= retries {
+			return errors.Wrapf(err, "failed to patch-cas '%s': attempts '%d'", name, attempt+1)
+		}
+		m.logger().Info("re-attempting patch-cas since object was modified", "task", m.getTaskIdentity(), "objectName", name, "attempt", attempt+1)
+	}
+}
+
+// patchCASOnce fetches the latest object & patches it with the provided
+// patches along with the resource version of the fetched object
+func (m *taskExecutor) patchCASOnce(p casPatcher, name string, patchType types.PatchType, raw []byte) ([]byte, error) {
+	current, err := p.get(name)
+	if err != nil {
+		return nil, err
+	}
+	resourceVersion, err := resourceVersionOf(current)
+	if err != nil {
+		return nil, err
+	}
+
+	// patches are decoded every time since the resource version is set in
+	// them
+	patches := map[string]interface{}{}
+	err = json.Unmarshal(raw, &patches)
+	if err != nil {
+		return nil, err
+	}
+	util.SetNestedField(patches, resourceVersion, "metadata", "resourceVersion")
+	b, err := json.Marshal(patches)
+	if err != nil {
+		return nil, err
+	}
+	return p.patch(name, patchType, b)
+}
+
+// setCASResult sets the patched object & its resource version against the
+// template values
+func (m *taskExecutor) setCASResult(patched []byte) error {
+	resourceVersion, err := resourceVersionOf(patched)
+	if err != nil {
+		return err
+	}
+
+	util.SetNestedField(m.templateValues, patched, string(v1alpha1.CurrentJSONResultTLP))
+	util.SetNestedField(m.templateValues, resourceVersion, string(v1alpha1.TaskResultTLP), m.getTaskIdentity(), string(v1alpha1.ResourceVersionTRTP))
+	return nil
+}
+
+// resourceVersionOf returns the resource version of the provided object
+func resourceVersionOf(raw []byte) (string, error) {
+	var obj struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+	}
+	err := json.Unmarshal(raw, &obj)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get resource version")
+	}
+	if len(obj.Metadata.ResourceVersion) == 0 {
+		return "", fmt.Errorf("failed to get resource version: missing metadata.resourceVersion")
+	}
+	return obj.Metadata.ResourceVersion, nil
+}
+
+// patchCASExtnV1B1Deploy patches the K8s Deployment of this task only if the
+// deployment was not modified after it was fetched
+func (m *taskExecutor) patchCASExtnV1B1Deploy() error {
+	kc := m.getK8sClient()
+	return m.patchCAS("ExtnV1B1DeployPatchCAS", casPatcher{
+		get:   kc.GetExtnV1B1DeploymentAsRaw,
+		patch: kc.PatchExtnV1B1DeploymentAsRaw,
+	})
+}
+
+// patchCASOEV1alpha1SPC patches the SPC of this task only if the SPC was not
+// modified after it was fetched
+func (m *taskExecutor) patchCASOEV1alpha1SPC() error {
+	kc := m.getK8sClient()
+	return m.patchCAS("patchCASSPC", casPatcher{
+		get: kc.GetOEV1alpha1SPCAsRaw,
+		patch: func(name string, patchType types.PatchType, patches []byte) ([]byte, error) {
+			spc, err := kc.PatchOEV1alpha1SPCAsRaw(name, patchType, patches)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(spc)
+		},
+	})
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+)
+
+// fakeDeployAPIServer returns a K8s API server that serves a single
+// deployment. The deployment is modified by someone else before each of the
+// first conflicts patches i.e. these patches result in a conflict.
+func fakeDeployAPIServer(conflicts int) (*httptest.Server, *int) {
+	var (
+		mutex           sync.Mutex
+		resourceVersion = 1
+		patches         int
+	)
+	deploy := func() []byte {
+		return []byte(fmt.Sprintf(`{"apiVersion":"extensions/v1beta1","kind":"Deployment","metadata":{"name":"d1","namespace":"default","resourceVersion":"%d"}}`, resourceVersion))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/apis/extensions/v1beta1/namespaces/default/deployments/d1" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"apiVersion":"v1","kind":"Status","status":"Failure","reason":"NotFound","code":404}`))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			w.Write(deploy())
+		case http.MethodPatch:
+			patches++
+			if patches <= conflicts {
+				// someone else modified the deployment after it was fetched
+				resourceVersion++
+			}
+
+			body, _ := ioutil.ReadAll(r.Body)
+			var patch struct {
+				Metadata struct {
+					ResourceVersion string `json:"resourceVersion"`
+				} `json:"metadata"`
+			}
+			json.Unmarshal(body, &patch)
+			if patch.Metadata.ResourceVersion != strconv.Itoa(resourceVersion) {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"apiVersion":"v1","kind":"Status","status":"Failure","reason":"Conflict","code":409}`))
+				return
+			}
+			resourceVersion++
+			w.Write(deploy())
+		}
+	}))
+	return server, &patches
+}
+
+func TestPatchCAS(t *testing.T) {
+	tests := map[string]struct {
+		conflicts               int
+		casRetries              string
+		patchType               string
+		expectedResourceVersion string
+		expectedPatches         int
+		isErr                   bool
+	}{
+		"patch cas - +ve test case - no conflict": {
+			patchType:               "merge",
+			expectedResourceVersion: "2",
+			expectedPatches:         1,
+		},
+		"patch cas - +ve test case - re-attempted on conflict": {
+			conflicts:               2,
+			patchType:               "strategic",
+			expectedResourceVersion: "4",
+			expectedPatches:         3,
+		},
+		"patch cas - -ve test case - conflicts exceed cas retries": {
+			conflicts:       5,
+			casRetries:      "\ncasRetries: 1",
+			patchType:       "merge",
+			expectedPatches: 2,
+			isErr:           true,
+		},
+		"patch cas - -ve test case - json patch is not supported": {
+			patchType: "json",
+			isErr:     true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			server, patches := fakeDeployAPIServer(mock.conflicts)
+			defer server.Close()
+
+			defer setK8sMaster(server.URL)()
+
+			runtask := &v1alpha1.RunTask{}
+			runtask.Name = "patchdeploy"
+			runtask.Spec.Meta = "id: patchdeploy\napiVersion: extensions/v1beta1\nkind: Deployment\naction: patch-cas\nrunNamespace: default\nobjectName: d1" + mock.casRetries
+			runtask.Spec.Task = "type: " + mock.patchType + "\npspec: |\n  spec:\n    replicas: 2"
+
+			r := NewTaskGroupRunner()
+			r.AddRunTask(runtask)
+			values := fakeTemplateValues()
+			_, err := r.Run(context.Background(), values)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test patch cas: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			if *patches != mock.expectedPatches {
+				t.Fatalf("failed to test patch cas: expected patches '%d': actual '%d'", mock.expectedPatches, *patches)
+			}
+			if mock.isErr {
+				return
+			}
+			actual := util.GetNestedString(values, string(v1alpha1.TaskResultTLP), "patchdeploy", string(v1alpha1.ResourceVersionTRTP))
+			if actual != mock.expectedResourceVersion {
+				t.Fatalf("failed to test patch cas: expected resource version '%s': actual '%s'", mock.expectedResourceVersion, actual)
+			}
+		})
+	}
+}
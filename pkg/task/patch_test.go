@@ -17,8 +17,14 @@ limitations under the License.
 package task
 
 import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -124,3 +130,105 @@ func TestPatch(t *testing.T) {
 		}) // end of run
 	}
 }
+
+// fakePatchAPIServer returns a K8s API server that serves a single
+// deployment & records the content type & body of the patch requests
+func fakePatchAPIServer() (*httptest.Server, *string, *string) {
+	var contentType, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodPatch || r.URL.Path != "/apis/extensions/v1beta1/namespaces/default/deployments/d1" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"apiVersion":"v1","kind":"Status","status":"Failure","reason":"NotFound","code":404}`))
+			return
+		}
+
+		b, _ := ioutil.ReadAll(r.Body)
+		contentType, body = r.Header.Get("Content-Type"), string(b)
+		w.Write([]byte(`{"apiVersion":"extensions/v1beta1","kind":"Deployment","metadata":{"name":"d1","namespace":"default"},"spec":{"replicas":2}}`))
+	}))
+	return server, &contentType, &body
+}
+
+func TestPatchActions(t *testing.T) {
+	tests := map[string]struct {
+		action              string
+		task                string
+		expectedContentType string
+		expectedBody        string
+		isErr               bool
+	}{
+		"patch actions - +ve test case - patch with type in task": {
+			action:              "patch",
+			task:                "type: merge\npspec: |\n  spec:\n    replicas: 2",
+			expectedContentType: string(types.MergePatchType),
+			expectedBody:        `{"spec":{"replicas":2}}`,
+		},
+		"patch actions - +ve test case - patch-strategic": {
+			action:              "patch-strategic",
+			task:                "spec:\n  replicas: {{ .Volume.replicas }}",
+			expectedContentType: string(types.StrategicMergePatchType),
+			expectedBody:        `{"spec":{"replicas":2}}`,
+		},
+		"patch actions - +ve test case - patch-merge": {
+			action:              "patch-merge",
+			task:                `{"spec":{"replicas":{{ .Volume.replicas }}}}`,
+			expectedContentType: string(types.MergePatchType),
+			expectedBody:        `{"spec":{"replicas":2}}`,
+		},
+		"patch actions - +ve test case - patch-json": {
+			action:              "patch-json",
+			task:                `[{"op":"replace","path":"/spec/replicas","value":{{ .Volume.replicas }}}]`,
+			expectedContentType: string(types.JSONPatchType),
+			expectedBody:        `[{"op":"replace","path":"/spec/replicas","value":2}]`,
+		},
+		"patch actions - +ve test case - patch-json as yaml": {
+			action:              "patch-json",
+			task:                "- op: replace\n  path: /spec/replicas\n  value: {{ .Volume.replicas }}",
+			expectedContentType: string(types.JSONPatchType),
+			expectedBody:        `[{"op":"replace","path":"/spec/replicas","value":2}]`,
+		},
+		"patch actions - -ve test case - patch-json with invalid operations": {
+			action: "patch-json",
+			task:   `"replace"`,
+			isErr:  true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			server, contentType, body := fakePatchAPIServer()
+			defer server.Close()
+
+			defer setK8sMaster(server.URL)()
+
+			runtask := &v1alpha1.RunTask{}
+			runtask.Name = "patchdeploy"
+			runtask.Spec.Meta = "id: patchdeploy\napiVersion: extensions/v1beta1\nkind: Deployment\naction: " + mock.action + "\nrunNamespace: default\nobjectName: d1"
+			runtask.Spec.Task = mock.task
+			runtask.Spec.PostRun = `{{- jsonpath .JsonResult "{.spec.replicas}" | trim | saveAs "patchdeploy.replicas" .TaskResult | noop -}}`
+
+			r := NewTaskGroupRunner()
+			r.AddRunTask(runtask)
+			values := fakeTemplateValues()
+			values["Volume"] = map[string]interface{}{"replicas": 2}
+			_, err := r.Run(context.Background(), values)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test patch actions: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			if mock.isErr {
+				return
+			}
+			if *contentType != mock.expectedContentType {
+				t.Fatalf("failed to test patch actions: expected content type '%s': actual '%s'", mock.expectedContentType, *contentType)
+			}
+			if *body != mock.expectedBody {
+				t.Fatalf("failed to test patch actions: expected patch '%s': actual '%s'", mock.expectedBody, *body)
+			}
+			actual := util.GetNestedString(values, string(v1alpha1.TaskResultTLP), "patchdeploy", "replicas")
+			if actual != "2" {
+				t.Fatalf("failed to test patch actions: expected patched replicas '2' in task result: actual '%s'", actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+)
+
+// pluginActionPrefix is the prefix of a task action that is executed by a
+// task plugin
+//
+// e.g. action: plugin/httpget
+const pluginActionPrefix = "plugin/"
+
+// TaskPlugin executes a task whose action is not a kubernetes API call e.g.
+// calling an external service
+type TaskPlugin interface {
+	// Execute executes the task based on the task's rendered meta & task
+	// specifications. The returned result is made available to the task's
+	// post run templates as .JsonResult. Execution is expected to stop once
+	// the provided context is done.
+	Execute(ctx context.Context, meta MetaTaskSpec, spec string, values map[string]interface{}) ([]byte, error)
+}
+
+var (
+	// pluginsMutex guards the registered plugins
+	pluginsMutex sync.RWMutex
+	// plugins are the registered task plugins mapped by their names
+	plugins = map[string]TaskPlugin{}
+)
+
+// RegisterTaskPlugin registers the provided plugin against the provided name.
+// Tasks having the action as plugin/<name> are executed by this plugin.
+//
+// NOTE:
+//  No plugin is registered by default since a plugin lets the templates
+// reach beyond the kubernetes API. A binary opts in to the plugins it trusts
+// e.g.
+//
+//  task.RegisterTaskPlugin(task.HTTPGetPluginName, &task.HTTPGetPlugin{})
+//
+// NOTE:
+//  A plugin registered earlier with the same name is replaced
+func RegisterTaskPlugin(name string, plugin TaskPlugin) {
+	pluginsMutex.Lock()
+	defer pluginsMutex.Unlock()
+
+	plugins[name] = plugin
+}
+
+// getTaskPlugin returns the plugin registered against the provided name
+func getTaskPlugin(name string) (plugin TaskPlugin, found bool) {
+	pluginsMutex.RLock()
+	defer pluginsMutex.RUnlock()
+
+	plugin, found = plugins[name]
+	return
+}
+
+func (m *metaTaskExecutor) isPlugin() bool {
+	return strings.HasPrefix(string(m.metaTask.Action), pluginActionPrefix)
+}
+
+// getPluginName returns the name of the plugin that executes this task
+func (m *metaTaskExecutor) getPluginName() string {
+	return strings.TrimPrefix(string(m.metaTask.Action), pluginActionPrefix)
+}
+
+// executePlugin executes this task via its plugin & sets the plugin's result
+// at .JsonResult
+func (m *taskExecutor) executePlugin() (err error) {
+	name := m.metaTaskExec.getPluginName()
+	plugin, found := getTaskPlugin(name)
+	if !found {
+		return fmt.Errorf("failed to execute task '%s': plugin '%s' is not registered", m.getTaskIdentity(), name)
+	}
+
+	var spec []byte
+	if m.runtask != nil && len(m.runtask.Spec.Task) != 0 {
+		spec, err = m.renderTask("RunTask")
+		if err != nil {
+			return
+		}
+	}
+
+	result, err := plugin.Execute(m.context(), m.metaTaskExec.getMetaInfo(), string(spec), m.templateValues)
+	if err != nil {
+		return fmt.Errorf("failed to execute task '%s' via plugin '%s': %s", m.getTaskIdentity(), name, err)
+	}
+
+	util.SetNestedField(m.templateValues, result, string(v1alpha1.CurrentJSONResultTLP))
+	return
+}
+
+// HTTPGetPluginName is the name that HTTPGetPlugin is typically registered
+// with
+const HTTPGetPluginName = "httpget"
+
+// defaultHTTPGetTimeout is the timeout of a http get request if the task
+// does not specify its own timeout
+const defaultHTTPGetTimeout = 30 * time.Second
+
+// httpGetSpec represents the task specifications of a httpget plugin task
+//
+// A sample httpget task:
+//
+// meta: |
+//   id: getlicense
+//   kind: License
+//   apiVersion: v1
+//   action: plugin/httpget
+// task: |
+//   url: http://license-server/licenses/{{ .Volume.owner }}
+//   headers:
+//     Accept: application/json
+type httpGetSpec struct {
+	// URL to fetch
+	URL string `json:"url"`
+	// Headers are set against the request
+	Headers map[string]string `json:"headers"`
+}
+
+// HTTPGetPlugin fetches the url set in the task specifications. The
+// response body is the result of the task.
+type HTTPGetPlugin struct {
+	// client is used to send the request; http.DefaultClient is used if not
+	// set
+	client *http.Client
+}
+
+// Execute sends a http get request to the url set in the task specifications.
+// The request is cancelled if it does not complete within the task's timeout
+// or if the provided context is done.
+func (p *HTTPGetPlugin) Execute(ctx context.Context, meta MetaTaskSpec, spec string, values map[string]interface{}) (result []byte, err error) {
+	var s httpGetSpec
+	err = yaml.Unmarshal([]byte(spec), &s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid httpget specifications: %s", err)
+	}
+	if len(strings.TrimSpace(s.URL)) == 0 {
+		return nil, fmt.Errorf("invalid httpget specifications: url is missing")
+	}
+
+	timeout, _ := time.ParseDuration(strings.TrimSpace(meta.Timeout))
+	if timeout <= 0 {
+		timeout = defaultHTTPGetTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, s.URL, nil)
+	if err != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := p.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	result, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("failed to get '%s': unexpected status '%s'", s.URL, resp.Status)
+	}
+	return
+}
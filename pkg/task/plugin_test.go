@@ -0,0 +1,169 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+)
+
+// fakeEchoPlugin returns the rendered task specifications as its result
+type fakeEchoPlugin struct{}
+
+func (p fakeEchoPlugin) Execute(ctx context.Context, meta MetaTaskSpec, spec string, values map[string]interface{}) ([]byte, error) {
+	if spec == "fail" {
+		return nil, fmt.Errorf("echo failed")
+	}
+	return []byte(meta.Identity + ":" + spec), nil
+}
+
+func TestExecutePlugin(t *testing.T) {
+	RegisterTaskPlugin("echo", fakeEchoPlugin{})
+
+	tests := map[string]struct {
+		action   MetaTaskAction
+		task     string
+		expected string
+		isErr    bool
+	}{
+		"execute plugin - +ve test case - registered plugin": {
+			action:   "plugin/echo",
+			task:     "owner: {{ .owner }}",
+			expected: "echoit:owner: pvc-1",
+		},
+		"execute plugin - -ve test case - unregistered plugin": {
+			action: "plugin/unknown",
+			isErr:  true,
+		},
+		"execute plugin - -ve test case - plugin error": {
+			action: "plugin/echo",
+			task:   "fail",
+			isErr:  true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			runtask := &v1alpha1.RunTask{}
+			runtask.Spec.Task = mock.task
+			values := fakeTemplateValues()
+			values["owner"] = "pvc-1"
+			te := &taskExecutor{
+				templateValues: values,
+				runtask:        runtask,
+				metaTaskExec: &metaTaskExecutor{
+					metaTask: MetaTaskSpec{MetaTaskIdentity: MetaTaskIdentity{Identity: "echoit"}, Action: mock.action},
+				},
+			}
+
+			// no k8s client is required to execute a plugin
+			err := te.ExecuteIt()
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("failed to test execute plugin: expected 'error': actual 'no error'")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to test execute plugin: expected 'no error': actual '%s'", err)
+			}
+
+			result, _ := util.GetNestedField(values, string(v1alpha1.CurrentJSONResultTLP)).([]byte)
+			if string(result) != mock.expected {
+				t.Fatalf("failed to test execute plugin: expected '%s': actual '%s'", mock.expected, result)
+			}
+		})
+	}
+}
+
+func TestHTTPGetPlugin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(time.Second)
+		}
+		if r.URL.Path != "/license" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"valid":"%s"}`, r.Header.Get("X-Owner"))
+	}))
+	defer server.Close()
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := map[string]struct {
+		ctx      context.Context
+		timeout  string
+		spec     string
+		expected string
+		isErr    bool
+	}{
+		"httpget plugin - +ve test case - valid url": {
+			spec:     "url: " + server.URL + "/license\nheaders:\n  X-Owner: pvc-1",
+			expected: `{"valid":"pvc-1"}`,
+		},
+		"httpget plugin - -ve test case - missing url": {
+			spec:  "headers:\n  X-Owner: pvc-1",
+			isErr: true,
+		},
+		"httpget plugin - -ve test case - not found": {
+			spec:  "url: " + server.URL + "/unknown",
+			isErr: true,
+		},
+		"httpget plugin - -ve test case - meta timeout": {
+			timeout: "100ms",
+			spec:    "url: " + server.URL + "/slow",
+			isErr:   true,
+		},
+		"httpget plugin - -ve test case - cancelled context": {
+			ctx:   cancelled,
+			spec:  "url: " + server.URL + "/license",
+			isErr: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := mock.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			p := &HTTPGetPlugin{}
+			result, err := p.Execute(ctx, MetaTaskSpec{Timeout: mock.timeout}, mock.spec, nil)
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("failed to test httpget plugin: expected 'error': actual 'no error'")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to test httpget plugin: expected 'no error': actual '%s'", err)
+			}
+			if string(result) != mock.expected {
+				t.Fatalf("failed to test httpget plugin: expected '%s': actual '%s'", mock.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/template"
+	"github.com/openebs/maya/pkg/util"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// ContentType is the media type an output is rendered as
+type ContentType string
+
+const (
+	// JSONContentType renders the output as json
+	JSONContentType ContentType = "application/json"
+	// YAMLContentType renders the output as yaml
+	YAMLContentType ContentType = "application/yaml"
+	// ProtobufContentType renders the output as a protobuf message. This is
+	// supported for the outputs that are K8s objects having proto
+	// definitions e.g. a Service.
+	ProtobufContentType ContentType = "application/vnd.google.protobuf"
+)
+
+// OutputRenderer renders the output of a task as a specific media type
+type OutputRenderer func(output []byte) ([]byte, error)
+
+// outputRenderers are the supported output renderers mapped by their media
+// types
+var outputRenderers = map[ContentType]OutputRenderer{
+	JSONContentType:     renderJSON,
+	YAMLContentType:     renderYAML,
+	ProtobufContentType: renderProtobuf,
+}
+
+// renderJSON renders the provided yaml or json output as json
+func renderJSON(output []byte) ([]byte, error) {
+	return yaml.YAMLToJSON(output)
+}
+
+// renderYAML renders the provided yaml or json output as yaml
+func renderYAML(output []byte) ([]byte, error) {
+	return yaml.JSONToYAML(output)
+}
+
+// protoMarshaler is implemented by the types having proto definitions
+type protoMarshaler interface {
+	Marshal() ([]byte, error)
+}
+
+// renderProtobuf renders the provided yaml or json output as a protobuf
+// message. The output is decoded into a K8s object based on its apiVersion
+// & kind.
+func renderProtobuf(output []byte) ([]byte, error) {
+	j, err := yaml.YAMLToJSON(output)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, gvk, err := scheme.Codecs.UniversalDeserializer().Decode(j, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render output as protobuf: %s", err)
+	}
+
+	m, ok := obj.(protoMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("failed to render output as protobuf: kind '%s' has no proto definition", gvk.Kind)
+	}
+	return m.Marshal()
+}
+
+// negotiateContentType returns the first of the provided comma separated
+// media types that has a renderer. Parameters of a media type e.g. q are
+// ignored. An empty media type is returned if none of the media types is
+// supported.
+func negotiateContentType(accept string) ContentType {
+	for _, mediaType := range strings.Split(accept, ",") {
+		contentType := ContentType(strings.TrimSpace(strings.Split(mediaType, ";")[0]))
+		if _, ok := outputRenderers[contentType]; ok {
+			return contentType
+		}
+	}
+	return ""
+}
+
+// renderOutput renders the provided output of the provided output task. The
+// media type set in the task's specifications is preferred over the one
+// negotiated from the media types set at .Accept. The output is returned as
+// is if there is no media type to render as.
+func renderOutput(outputTask *v1alpha1.RunTask, output []byte, values map[string]interface{}) ([]byte, error) {
+	contentType := ContentType(strings.TrimSpace(outputTask.Spec.ContentType))
+	if len(contentType) == 0 {
+		contentType = negotiateContentType(util.GetNestedString(values, string(v1alpha1.AcceptTLP)))
+	}
+	if len(contentType) == 0 {
+		return output, nil
+	}
+
+	render, ok := outputRenderers[contentType]
+	if !ok {
+		return nil, fmt.Errorf("failed to render output task '%s': unsupported content type '%s'", outputTask.Name, contentType)
+	}
+	rendered, err := render(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render output task '%s' as '%s': %s", outputTask.Name, contentType, err)
+	}
+	return rendered, nil
+}
+
+// formatOutput converts the provided yaml or json output to the provided
+// format. The output is returned as is if no format is provided.
+func formatOutput(output []byte, format OutputFormat) ([]byte, error) {
+	if len(format) == 0 {
+		return output, nil
+	}
+
+	var doc interface{}
+	err := yaml.Unmarshal(output, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format output as '%s': output is neither yaml nor json: %s", format, err)
+	}
+
+	switch format {
+	case JSONOF:
+		j := template.ToJSON(doc)
+		if len(j) == 0 {
+			return nil, fmt.Errorf("failed to format output as '%s': output can not be converted to json", format)
+		}
+		return []byte(j), nil
+	case YAMLOF:
+		y := template.ToYaml(doc)
+		if strings.HasPrefix(y, "error: ") {
+			return nil, fmt.Errorf("failed to format output as '%s': %s", format, strings.TrimPrefix(y, "error: "))
+		}
+		return []byte(y), nil
+	}
+	return nil, fmt.Errorf("failed to format output: unsupported output format '%s': supported formats are '%s' and '%s'", format, YAMLOF, JSONOF)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	api_core_v1 "k8s.io/api/core/v1"
+)
+
+func TestRenderOutput(t *testing.T) {
+	svc := "apiVersion: v1\nkind: Service\nmetadata:\n  name: svc-1"
+
+	tests := map[string]struct {
+		contentType string
+		accept      string
+		output      string
+		expected    string
+		isErr       bool
+	}{
+		"render output - +ve test case - as is": {
+			output:   "name: vol-1",
+			expected: "name: vol-1",
+		},
+		"render output - +ve test case - json content type": {
+			contentType: "application/json",
+			output:      "name: vol-1",
+			expected:    `{"name":"vol-1"}`,
+		},
+		"render output - +ve test case - yaml content type": {
+			contentType: "application/yaml",
+			output:      `{"name":"vol-1"}`,
+			expected:    "name: vol-1\n",
+		},
+		"render output - +ve test case - negotiated from accept": {
+			accept:   "text/html, application/json;q=0.9",
+			output:   "name: vol-1",
+			expected: `{"name":"vol-1"}`,
+		},
+		"render output - +ve test case - content type is preferred over accept": {
+			contentType: "application/yaml",
+			accept:      "application/json",
+			output:      `{"name":"vol-1"}`,
+			expected:    "name: vol-1\n",
+		},
+		"render output - +ve test case - unsupported accept": {
+			accept:   "text/html",
+			output:   "name: vol-1",
+			expected: "name: vol-1",
+		},
+		"render output - -ve test case - unsupported content type": {
+			contentType: "text/html",
+			output:      "name: vol-1",
+			isErr:       true,
+		},
+		"render output - -ve test case - invalid yaml": {
+			contentType: "application/json",
+			output:      "name: [vol-1",
+			isErr:       true,
+		},
+		"render output - -ve test case - protobuf without proto definition": {
+			contentType: "application/vnd.google.protobuf",
+			output:      "apiVersion: openebs.io/v1alpha1\nkind: CASVolume\nmetadata:\n  name: vol-1",
+			isErr:       true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			runtask := &v1alpha1.RunTask{}
+			runtask.Name = "output"
+			runtask.Spec.ContentType = mock.contentType
+			values := map[string]interface{}{}
+			if len(mock.accept) != 0 {
+				values[string(v1alpha1.AcceptTLP)] = mock.accept
+			}
+
+			actual, err := renderOutput(runtask, []byte(mock.output), values)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test render output: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			if !mock.isErr && string(actual) != mock.expected {
+				t.Fatalf("failed to test render output: expected '%s': actual '%s'", mock.expected, actual)
+			}
+		})
+	}
+
+	runtask := &v1alpha1.RunTask{}
+	runtask.Spec.ContentType = string(ProtobufContentType)
+	b, err := renderOutput(runtask, []byte(svc), nil)
+	if err != nil {
+		t.Fatalf("failed to test render output: expected no protobuf error: actual '%s'", err)
+	}
+	decoded := &api_core_v1.Service{}
+	if err := decoded.Unmarshal(b); err != nil || decoded.Name != "svc-1" {
+		t.Fatalf("failed to test render output: expected protobuf of service 'svc-1': actual '%s': error '%v'", decoded.Name, err)
+	}
+}
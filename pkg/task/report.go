@@ -0,0 +1,227 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+)
+
+// TaskStatus represents the status of a task after the task group runner
+// has run
+type TaskStatus string
+
+const (
+	// TaskSucceeded flags a task whose execution was successful
+	TaskSucceeded TaskStatus = "Succeeded"
+	// TaskFailed flags a task whose execution resulted in an error
+	TaskFailed TaskStatus = "Failed"
+	// TaskSkipped flags a task that was not executed since its run if
+	// predicate evaluated to false or its disable predicate evaluated to true
+	TaskSkipped TaskStatus = "Skipped"
+)
+
+// TaskResult represents the outcome of executing a task
+type TaskResult struct {
+	// Name of the run task
+	//
+	// NOTE:
+	//  This is not set for rollback tasks
+	Name string
+	// Identity of the task
+	Identity string
+	// Status of the task
+	Status TaskStatus
+	// Started is the time when the task's execution started
+	Started time.Time
+	// Duration taken to execute the task
+	Duration time.Duration
+	// Retries is the number of times the task was re-executed due to
+	// retryable errors
+	Retries int
+	// ObjectNames are the names of the objects operated by the task
+	ObjectNames []string
+	// RenderedMeta is the task's meta yaml after templating
+	RenderedMeta string
+	// RenderedTask is the task's yaml after templating; is empty if the task
+	// yaml was not templated e.g. for a get action
+	//
+	// NOTE:
+	//  This may hold the values of the secrets referred to by the task
+	RenderedTask string
+	// Err is the error if any that resulted from executing the task
+	Err error
+	// IgnoredErr is the error that resulted from executing the task & was
+	// ignored as per the task's ignoreErrors
+	IgnoredErr error
+}
+
+// RunResult represents the outcome of running a task group runner
+type RunResult struct {
+	// Output of the runner as defined by the runner's output task
+	Output []byte
+	// ExecutedTasks are the tasks that were executed or skipped in their
+	// order of completion
+	ExecutedTasks []TaskResult
+	// RolledBackTasks are the rollback tasks that were executed in their
+	// order of execution
+	RolledBackTasks []TaskResult
+	// LeakedObjects are the names of the objects whose rollback failed; these
+	// need to be cleaned up manually
+	LeakedObjects []string
+	// Duration taken by the runner
+	Duration time.Duration
+	// Err is the error if any that resulted from running the runner
+	Err error
+	// FellBack flags if the runner fell back to the fallback template
+	FellBack bool
+}
+
+// GroupRunReportEntry represents the outcome of a task in a group run report
+type GroupRunReportEntry struct {
+	// Identity of the task
+	Identity string
+	// Status of the task
+	Status TaskStatus
+	// Started is the time when the task's execution started
+	Started time.Time
+	// Duration taken to execute the task
+	Duration time.Duration
+	// Error if any that resulted from executing the task
+	Error string
+	// ObjectNames are the names of the objects operated by the task
+	ObjectNames []string
+	// RenderedMeta is the task's meta yaml after templating
+	RenderedMeta string
+	// RenderedTask is the task's yaml after templating
+	RenderedTask string
+}
+
+// GroupRunReport represents the outcome of running a task group runner
+type GroupRunReport struct {
+	// Tasks are the entries of the tasks that were executed or skipped in
+	// their order of completion
+	Tasks []GroupRunReportEntry
+	// Output of the runner as defined by the runner's output task
+	Output []byte
+	// RolledBack flags if the executed tasks were rolled back
+	RolledBack bool
+	// LeakedObjects are the names of the objects whose rollback failed; these
+	// need to be cleaned up manually
+	LeakedObjects []string
+	// FellBack flags if the runner fell back to the fallback template
+	FellBack bool
+}
+
+// asGroupRunReport transforms the run result into a group run report
+func (r RunResult) asGroupRunReport() *GroupRunReport {
+	report := &GroupRunReport{
+		Output:        r.Output,
+		RolledBack:    len(r.RolledBackTasks) != 0,
+		LeakedObjects: r.LeakedObjects,
+		FellBack:      r.FellBack,
+	}
+
+	for _, t := range r.ExecutedTasks {
+		entry := GroupRunReportEntry{
+			Identity:     t.Identity,
+			Status:       t.Status,
+			Started:      t.Started,
+			Duration:     t.Duration,
+			ObjectNames:  t.ObjectNames,
+			RenderedMeta: t.RenderedMeta,
+			RenderedTask: t.RenderedTask,
+		}
+		if t.Err != nil {
+			entry.Error = t.Err.Error()
+		}
+		report.Tasks = append(report.Tasks, entry)
+	}
+
+	return report
+}
+
+// splitObjectNames splits the comma separated object names
+func splitObjectNames(objectName string) (names []string) {
+	for _, name := range strings.Split(objectName, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) != 0 {
+			names = append(names, name)
+		}
+	}
+	return
+}
+
+// taskObjectNames returns the names of the objects operated by the task
+// with the provided identity. The list set at .TaskResult.<taskID>.objectNames
+// is preferred over the comma separated names set at
+// .TaskResult.<taskID>.objectName
+func taskObjectNames(values map[string]interface{}, identity string) (names []string) {
+	switch list := util.GetNestedField(values, string(v1alpha1.TaskResultTLP), identity, string(v1alpha1.ObjectNamesTRTP)).(type) {
+	case []string:
+		for _, name := range list {
+			if name = strings.TrimSpace(name); len(name) != 0 {
+				names = append(names, name)
+			}
+		}
+		return
+	case []interface{}:
+		for _, item := range list {
+			if item == nil {
+				continue
+			}
+			if name := strings.TrimSpace(fmt.Sprint(item)); len(name) != 0 {
+				names = append(names, name)
+			}
+		}
+		return
+	}
+	return splitObjectNames(util.GetNestedString(values, string(v1alpha1.TaskResultTLP), identity, string(v1alpha1.ObjectNameTRTP)))
+}
+
+// recordExecuted records the outcome of an executed task. Status of the task
+// defaults to succeeded & is set to failed if there was an error.
+func (m *TaskGroupRunner) recordExecuted(result TaskResult) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(result.Status) == 0 {
+		result.Status = TaskSucceeded
+	}
+	if result.Err != nil {
+		result.Status = TaskFailed
+	}
+	m.executed = append(m.executed, result)
+}
+
+// recordSkipped records a task that was skipped
+func (m *TaskGroupRunner) recordSkipped(name, identity string) {
+	m.mutex.Lock()
+	m.executed = append(m.executed, TaskResult{
+		Name:     name,
+		Identity: identity,
+		Status:   TaskSkipped,
+		Started:  time.Now(),
+	})
+	m.mutex.Unlock()
+
+	m.sendUpdate(identity, TaskSkipped, nil, nil)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"net"
+	"time"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/template"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8swait "k8s.io/apimachinery/pkg/util/wait"
+)
+
+// isRetryableErr flags if the provided error is a transient error. Execution
+// of a task that resulted in a transient error may succeed if re-tried.
+//
+// NOTE:
+//  Errors that are handled via templating e.g. verification errors are never
+// considered as retryable
+func isRetryableErr(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	err = errors.Cause(err)
+	switch err.(type) {
+	case *template.VerifyError, *template.NotFoundError, *template.VersionMismatchError:
+		return false
+	}
+
+	if k8serrors.IsConflict(err) ||
+		k8serrors.IsServerTimeout(err) ||
+		k8serrors.IsTimeout(err) ||
+		k8serrors.IsTooManyRequests(err) ||
+		k8serrors.IsServiceUnavailable(err) ||
+		k8serrors.IsInternalError(err) {
+		return true
+	}
+
+	if netErr, ok := err.(net.Error); ok {
+		return netErr.Timeout() || netErr.Temporary()
+	}
+
+	return false
+}
+
+// retryJitterFactor is the maximum factor by which the delay between retries
+// is randomly increased. This avoids tasks of concurrent runners from
+// retrying at the same time.
+const retryJitterFactor = 0.1
+
+// getRetryPolicy returns the policy to retry this task on retryable errors
+//
+// NOTE:
+//  retryOnError set in meta specifications overrides the retry policy set in
+// task specifications which in turn overrides the runner's retry policy
+func (m *taskExecutor) getRetryPolicy() (policy v1alpha1.RetryPolicy) {
+	retries, interval := m.metaTaskExec.getRetryOnError()
+	if retries > 0 {
+		return v1alpha1.RetryPolicy{
+			MaxAttempts:    retries + 1,
+			InitialDelayMS: int(interval / time.Millisecond),
+			BackoffFactor:  2,
+		}
+	}
+
+	if m.runtask != nil {
+		policy = m.runtask.Spec.RetryPolicy
+	}
+	if policy == (v1alpha1.RetryPolicy{}) {
+		policy = m.defaultRetryPolicy
+	}
+	return
+}
+
+// retryOnError executes the task & retries its execution if the execution
+// resulted in a retryable error. The number of attempts, the initial delay &
+// the factor by which the delay increases after each attempt is determined
+// by the task's retry policy.
+//
+// NOTE:
+//  A task that is not idempotent e.g. put is never retried. Otherwise the
+// object created by a failed attempt is found to exist by the next attempt
+// & is neither reported nor rolled back.
+func (m *taskExecutor) retryOnError() (err error) {
+	policy := m.getRetryPolicy()
+
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	factor := policy.BackoffFactor
+	if factor <= 0 {
+		factor = 2
+	} else if factor < 1 {
+		// delay should never decrease
+		factor = 1
+	}
+	delay := time.Duration(policy.InitialDelayMS) * time.Millisecond
+
+	// i == 0 implies original task execute invocation
+	// i > 0 implies a retry operation
+	for i := 0; i < attempts; i++ {
+		m.retries = i
+		err = m.Execute()
+		if !isRetryableErr(err) {
+			// either a success or an error that can not be retried
+			return
+		}
+		if !m.metaTaskExec.isIdempotent() {
+			if attempts > 1 {
+				m.logger().Error(err, "will not retry runtask: its action is not idempotent", "task", m.getTaskIdentity(), "action", m.metaTaskExec.getMetaInfo().Action)
+			}
+			return
+		}
+
+		if i != attempts-1 {
+			wait := k8swait.Jitter(delay, retryJitterFactor)
+			m.logger().Error(err, "retryable error was found while executing runtask: will retry", "task", m.getTaskIdentity(), "attempt", i+1, "after", wait)
+
+			if waitErr := m.wait(wait); waitErr != nil {
+				return waitErr
+			}
+			delay = time.Duration(float64(delay) * factor)
+		}
+	}
+
+	// return after exhausting the original invocation and all retries;
+	// error of the final attempt will be returned here
+	return
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/template"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestIsRetryableErr(t *testing.T) {
+	gr := schema.GroupResource{Resource: "services"}
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"retryable err - +ve test case - conflict":             {err: k8serrors.NewConflict(gr, "svc", fmt.Errorf("conflict")), expected: true},
+		"retryable err - +ve test case - server timeout":       {err: k8serrors.NewServerTimeout(gr, "create", 1), expected: true},
+		"retryable err - +ve test case - wrapped conflict":     {err: errors.Wrap(k8serrors.NewConflict(gr, "svc", fmt.Errorf("conflict")), "failed"), expected: true},
+		"retryable err - -ve test case - nil error":            {err: nil, expected: false},
+		"retryable err - -ve test case - not found":            {err: k8serrors.NewNotFound(gr, "svc"), expected: false},
+		"retryable err - -ve test case - verify error":         {err: &template.VerifyError{}, expected: false},
+		"retryable err - -ve test case - plain template error": {err: fmt.Errorf("template: invalid"), expected: false},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			if isRetryableErr(mock.err) != mock.expected {
+				t.Fatalf("failed to test retryable err: expected '%t': actual '%t'", mock.expected, !mock.expected)
+			}
+		})
+	}
+}
+
+func TestParseRetry(t *testing.T) {
+	tests := map[string]struct {
+		retry            string
+		expectedAttempts int
+		expectedInterval time.Duration
+	}{
+		"parse retry - +ve test case - valid retry":      {retry: "5,2s", expectedAttempts: 5, expectedInterval: 2 * time.Second},
+		"parse retry - +ve test case - spaced retry":     {retry: "3, 1s", expectedAttempts: 3, expectedInterval: time.Second},
+		"parse retry - -ve test case - empty retry":      {retry: "", expectedAttempts: 0, expectedInterval: 0},
+		"parse retry - -ve test case - negative attempt": {retry: "-1,1s", expectedAttempts: 0, expectedInterval: time.Second},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, i := parseRetry(mock.retry)
+			if a != mock.expectedAttempts || i != mock.expectedInterval {
+				t.Fatalf("failed to test parse retry: expected attempts '%d' interval '%s': actual attempts '%d' interval '%s'", mock.expectedAttempts, mock.expectedInterval, a, i)
+			}
+		})
+	}
+}
+
+func TestGetRetryPolicy(t *testing.T) {
+	tests := map[string]struct {
+		retryOnError string
+		policy       v1alpha1.RetryPolicy
+		runnerPolicy v1alpha1.RetryPolicy
+		expected     v1alpha1.RetryPolicy
+	}{
+		"get retry policy - +ve test case - policy from task specs": {
+			policy:   v1alpha1.RetryPolicy{MaxAttempts: 3, InitialDelayMS: 100, BackoffFactor: 1.5},
+			expected: v1alpha1.RetryPolicy{MaxAttempts: 3, InitialDelayMS: 100, BackoffFactor: 1.5},
+		},
+		"get retry policy - +ve test case - meta overrides task specs": {
+			retryOnError: "4,2s",
+			policy:       v1alpha1.RetryPolicy{MaxAttempts: 3, InitialDelayMS: 100, BackoffFactor: 1.5},
+			expected:     v1alpha1.RetryPolicy{MaxAttempts: 5, InitialDelayMS: 2000, BackoffFactor: 2},
+		},
+		"get retry policy - +ve test case - task specs override runner": {
+			policy:       v1alpha1.RetryPolicy{MaxAttempts: 3, InitialDelayMS: 100, BackoffFactor: 1.5},
+			runnerPolicy: v1alpha1.RetryPolicy{MaxAttempts: 2},
+			expected:     v1alpha1.RetryPolicy{MaxAttempts: 3, InitialDelayMS: 100, BackoffFactor: 1.5},
+		},
+		"get retry policy - +ve test case - policy from runner": {
+			runnerPolicy: v1alpha1.RetryPolicy{MaxAttempts: 2, InitialDelayMS: 10},
+			expected:     v1alpha1.RetryPolicy{MaxAttempts: 2, InitialDelayMS: 10},
+		},
+		"get retry policy - +ve test case - no policy": {
+			expected: v1alpha1.RetryPolicy{},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			rt := &v1alpha1.RunTask{}
+			rt.Spec.RetryPolicy = mock.policy
+			te := &taskExecutor{
+				runtask:            rt,
+				defaultRetryPolicy: mock.runnerPolicy,
+				metaTaskExec: &metaTaskExecutor{
+					metaTask: MetaTaskSpec{MetaTaskProps: MetaTaskProps{RetryOnError: mock.retryOnError}},
+				},
+			}
+
+			if te.getRetryPolicy() != mock.expected {
+				t.Fatalf("failed to test get retry policy: expected '%+v': actual '%+v'", mock.expected, te.getRetryPolicy())
+			}
+		})
+	}
+}
+
+func TestRetryOnErrorIdempotent(t *testing.T) {
+	tests := map[string]struct {
+		meta             string
+		task             string
+		expectedRequests int32
+	}{
+		"retry on error - +ve test case - get is retried": {
+			meta:             "id: pvc\napiVersion: v1\nkind: PersistentVolumeClaim\naction: get\nrunNamespace: default\nobjectName: pvc-1",
+			expectedRequests: 3,
+		},
+		"retry on error - -ve test case - put is not retried": {
+			meta:             "id: svc\napiVersion: v1\nkind: Service\naction: put\nrunNamespace: default",
+			task:             "apiVersion: v1\nkind: Service\nmetadata:\n  name: svc-1",
+			expectedRequests: 1,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			// api server that fails with a retryable error
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"apiVersion":"v1","kind":"Status","status":"Failure","reason":"InternalError","code":500}`))
+			}))
+			defer server.Close()
+
+			defer setK8sMaster(server.URL)()
+
+			runtask := &v1alpha1.RunTask{}
+			runtask.Spec.Meta = mock.meta
+			runtask.Spec.Task = mock.task
+			runtask.Spec.RetryPolicy = v1alpha1.RetryPolicy{MaxAttempts: 3, InitialDelayMS: 1}
+			te, err := newTaskExecutor(runtask, fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test retry on error: expected 'no error': actual '%s'", err)
+			}
+
+			err = te.retryOnError()
+			if !k8serrors.IsInternalError(errors.Cause(err)) {
+				t.Fatalf("failed to test retry on error: expected 'internal error': actual '%v'", err)
+			}
+			if atomic.LoadInt32(&requests) != mock.expectedRequests {
+				t.Fatalf("failed to test retry on error: expected '%d' requests: actual '%d'", mock.expectedRequests, requests)
+			}
+		})
+	}
+}
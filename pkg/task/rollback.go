@@ -0,0 +1,611 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/openebs/maya/pkg/template"
+	"github.com/openebs/maya/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// runnerRollbacks plans the rollbacks of the tasks executed by a task group
+// runner & holds the outcome of the executed rollbacks. Rollback tasks are
+// executed by the runner.
+type runnerRollbacks struct {
+	// planned is an array of rollback tasks that need to be run in sequence
+	// in the event of any error
+	planned []*taskExecutor
+	// rolledBack holds the outcome of the rollback tasks that were executed
+	rolledBack []TaskResult
+	// leaked holds the names of the objects whose rollback failed even after
+	// retries
+	leaked []string
+	// retries is the number of times a failed rollback task is retried
+	retries int
+	// retryInterval is the duration to wait before retrying a failed
+	// rollback task
+	retryInterval time.Duration
+	// deadline is the maximum duration the caller waits for the rollback to
+	// complete; there is no deadline if this is not set
+	deadline time.Duration
+	// stopOnFailure flags if the rollback stops at the first rollback task
+	// that fails; rollback continues with the remaining rollback tasks if
+	// this is not set
+	stopOnFailure bool
+	// resets counts the resets of the runner; outcome of a background
+	// rollback is discarded if the runner was reset since the rollback started
+	resets int
+	// background tracks the rollbacks that continue in the background after
+	// the deadline
+	background sync.WaitGroup
+	// mutex guards the planned rollbacks & their outcome since tasks may be
+	// executed in parallel & rollbacks may continue in the background
+	mutex sync.Mutex
+}
+
+// plan adds the provided rollback task to the planned rollbacks
+func (rb *runnerRollbacks) plan(rte *taskExecutor) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	rb.planned = append(rb.planned, rte)
+}
+
+// leak adds the provided object name to the objects whose rollback failed
+func (rb *runnerRollbacks) leak(objectName string) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	rb.leaked = append(rb.leaked, objectName)
+}
+
+// objectNames returns the names of the objects planned for rollback by the
+// identities of the tasks that created them
+func (rb *runnerRollbacks) objectNames() map[string][]string {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	if len(rb.planned) == 0 {
+		return nil
+	}
+	names := map[string][]string{}
+	for _, rte := range rb.planned {
+		names[rte.rollbackOf] = append(names[rte.rollbackOf], rte.getTaskObjectName())
+	}
+	return names
+}
+
+// ordered returns the planned rollback tasks in their order of execution
+// along with the current count of resets
+func (rb *runnerRollbacks) ordered() ([]*taskExecutor, int) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	return orderRollbacks(rb.planned), rb.resets
+}
+
+// take removes the rollback tasks of the tasks with the provided identities
+// from the planned rollbacks. These are returned in their order of execution
+// along with the current count of resets.
+func (rb *runnerRollbacks) take(ids []string) ([]*taskExecutor, int) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	var selected, remaining []*taskExecutor
+	for _, rte := range rb.planned {
+		if util.ContainsString(ids, rte.rollbackOf) {
+			selected = append(selected, rte)
+			continue
+		}
+		remaining = append(remaining, rte)
+	}
+	rb.planned = remaining
+	return orderRollbacks(selected), rb.resets
+}
+
+// replace sets the provided rollback tasks as the planned rollbacks & returns
+// the rollback tasks that were planned before
+func (rb *runnerRollbacks) replace(planned []*taskExecutor) []*taskExecutor {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	previous := rb.planned
+	rb.planned = planned
+	return previous
+}
+
+// report returns the outcome of the executed rollbacks
+func (rb *runnerRollbacks) report() (rolledBack []TaskResult, leaked []string) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	rolledBack = append(rolledBack, rb.rolledBack...)
+	leaked = append(leaked, rb.leaked...)
+	return
+}
+
+// publish adds the provided outcome of a rollback to the rollback report.
+// Outcome is discarded if the runner was reset after the rollback started
+// i.e. if resets is not the current count of resets.
+func (rb *runnerRollbacks) publish(o *rollbackOutcome, resets int) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	if resets != rb.resets {
+		return
+	}
+	rb.rolledBack = append(rb.rolledBack, o.rolledBack...)
+	rb.leaked = append(rb.leaked, o.leaked...)
+}
+
+// reset clears the planned rollbacks & their outcome. Outcome of a rollback
+// that continues in the background is discarded.
+func (rb *runnerRollbacks) reset() {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	rb.planned = nil
+	rb.rolledBack = nil
+	rb.leaked = nil
+	rb.resets++
+}
+
+// SetRollbackDeadline sets the maximum duration the caller waits for the
+// rollback of the executed tasks. The run returns a RollbackError that wraps
+// a RollbackTimeoutError once this deadline passes, while the rollback
+// continues in the background. The caller waits till the rollback completes
+// if this is not set.
+//
+// NOTE:
+//  Report of a run whose rollback did not complete within the deadline does
+// not include the rollback tasks that complete in the background. These are
+// discarded if the runner is reset before the background rollback completes.
+func (m *TaskGroupRunner) SetRollbackDeadline(d time.Duration) {
+	m.rollbacks.deadline = d
+}
+
+// SetStopOnRollbackFailure flags if the rollback stops at the first rollback
+// task that fails. The rollback tasks that follow the failed one are not
+// executed & RollbackHaltedError is returned. This is meant for the flows
+// where rolling back an object after a failed rollback can cause further
+// damage e.g. deleting a parent object before its child object.
+func (m *TaskGroupRunner) SetStopOnRollbackFailure(stop bool) {
+	m.rollbacks.stopOnFailure = stop
+}
+
+// SetRollbackRetries sets the number of times a failed rollback task is
+// retried & the interval between these retries. Objects whose rollback failed
+// even after these retries are reported as leaked.
+func (m *TaskGroupRunner) SetRollbackRetries(n int, interval time.Duration) {
+	m.rollbacks.retries = n
+	m.rollbacks.retryInterval = interval
+}
+
+// planForRollback plans for rollback in case of future errors while executing
+// the tasks. This will add to the list of rollback tasks
+//
+// NOTE:
+//  This is just the planning for rollback & not actual rollback.
+// In the events of issues this planning will be useful.
+//
+// NOTE:
+//  There are cases where multiple objects may be created due to a single
+// RunTask. A rollback is planned for each of the provided object names.
+//
+// NOTE:
+//  Rollback of the task is verified before the task is executed. An object
+// whose rollback can not be planned even so is reported as leaked.
+func (m *TaskGroupRunner) planForRollback(te *taskExecutor, objectNames []string) error {
+	var errs *multierror.Error
+	// plan the rollback for all the objects that got created
+	for _, name := range objectNames {
+		// entire rollback plan is encapsulated in the task itself
+		rte, err := te.asRollbackInstance(name)
+		if err == nil && rte == nil {
+			// this task does not need a rollback
+			continue
+		}
+		if err == nil {
+			err = rte.Validate()
+		}
+		if err != nil {
+			m.logger().Error(err, "failed to plan for rollback: object needs to be cleaned up manually", "task", te.getTaskIdentity(), "objectName", name)
+			m.rollbacks.leak(name)
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		m.rollbacks.plan(rte)
+
+		m.mutex.Lock()
+		m.objectsCreated++
+		created := m.objectsCreated
+		m.mutex.Unlock()
+
+		// the object is rolled back along with the objects created previously
+		if m.maxObjectsCreated > 0 && created > m.maxObjectsCreated {
+			return &MaxObjectsCreatedError{Limit: m.maxObjectsCreated, TaskID: te.getTaskIdentity()}
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// MaxObjectsCreatedError represents an error due to the tasks of a runner
+// creating more objects than the runner's limit
+type MaxObjectsCreatedError struct {
+	// Limit is the maximum number of objects the tasks are allowed to create
+	Limit int
+	// TaskID is the identity of the task that exceeded the limit
+	TaskID string
+}
+
+func (e *MaxObjectsCreatedError) Error() string {
+	return fmt.Sprintf("aborted runtasks: task '%s' exceeded the limit of '%d' created objects", e.TaskID, e.Limit)
+}
+
+// IsMaxObjectsCreated flags if the provided error or any error wrapped or
+// aggregated by it is due to exceeding the limit of created objects
+func IsMaxObjectsCreated(err error) bool {
+	for err != nil {
+		switch e := err.(type) {
+		case *MaxObjectsCreatedError:
+			return true
+		case *multierror.Error:
+			for _, nested := range e.Errors {
+				if IsMaxObjectsCreated(nested) {
+					return true
+				}
+			}
+			return false
+		}
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			return false
+		}
+		err = cause.Cause()
+	}
+	return false
+}
+
+// RollbackError represents an error due to failure in executing the tasks
+// where the rollback of the executed tasks failed as well
+type RollbackError struct {
+	// Err is the error that resulted from executing the tasks
+	Err error
+	// RollbackErr is the aggregated error of the failed rollback tasks
+	RollbackErr error
+}
+
+func (e *RollbackError) Error() string {
+	return fmt.Sprintf("operation failed and rollback partially failed: %s: %s", e.RollbackErr, e.Err)
+}
+
+// Cause returns the error that resulted from executing the tasks
+//
+// NOTE:
+//  This lets errors.Cause to return the original error
+func (e *RollbackError) Cause() error {
+	return e.Err
+}
+
+// rollback will rollback the previously run operation(s). It returns the
+// aggregated error of the rollback tasks that failed.
+//
+// NOTE:
+//  Rollback is not cancelled along with the provided context since this
+// context is typically done when the run times out or when the caller goes
+// away. Failed rollback tasks are retried till the rollback deadline if any
+// elapses.
+//
+// NOTE:
+//  RollbackTimeoutError is returned if the rollback does not complete within
+// the rollback deadline. The rollback continues in the background in this
+// case.
+//
+// NOTE:
+//  Outcome of a rollback that continues in the background is published once
+// it completes unless this runner is reset by then. Nothing else of this
+// runner is mutated by the background rollback.
+func (m *TaskGroupRunner) rollback(ctx context.Context) error {
+	rollbacks, resets := m.rollbacks.ordered()
+	ctx, cancel := m.rollbackContext(ctx)
+	if m.rollbacks.deadline <= 0 {
+		defer cancel()
+		outcome := &rollbackOutcome{}
+		err := m.executeRollbacks(ctx, rollbacks, outcome)
+		m.rollbacks.publish(outcome, resets)
+		return err
+	}
+
+	done := make(chan error, 1)
+	m.rollbacks.background.Add(1)
+	go func() {
+		defer m.rollbacks.background.Done()
+		defer cancel()
+		outcome := &rollbackOutcome{}
+		err := m.executeRollbacks(ctx, rollbacks, outcome)
+		m.rollbacks.publish(outcome, resets)
+		done <- err
+	}()
+
+	deadline := time.NewTimer(m.rollbacks.deadline)
+	defer deadline.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-deadline.C:
+		m.logger().Info("rollback did not complete within deadline: will continue in the background", "deadline", m.rollbacks.deadline)
+		return &RollbackTimeoutError{Deadline: m.rollbacks.deadline}
+	}
+}
+
+// rollbackContext returns the context used to rollback. This context holds
+// the values e.g. tracer of the provided context but is not cancelled along
+// with it. It is done once the rollback deadline if any elapses.
+func (m *TaskGroupRunner) rollbackContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	rctx := context.Context(detachedContext{ctx})
+	if m.rollbacks.deadline <= 0 {
+		return context.WithCancel(rctx)
+	}
+	return context.WithTimeout(rctx, m.rollbacks.deadline)
+}
+
+// detachedContext is a context that holds the values of its parent context
+// but is never done
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+// RollbackTimeoutError represents an error due to the rollback of the
+// executed tasks not completing within the rollback deadline
+type RollbackTimeoutError struct {
+	// Deadline is the duration the rollback was allowed to take
+	Deadline time.Duration
+}
+
+func (e *RollbackTimeoutError) Error() string {
+	return fmt.Sprintf("rollback did not complete within '%s': rollback continues in the background", e.Deadline)
+}
+
+// IsRollbackTimeout flags if the provided error or the rollback error of the
+// provided RollbackError is a RollbackTimeoutError
+func IsRollbackTimeout(err error) bool {
+	if rerr, ok := err.(*RollbackError); ok {
+		err = rerr.RollbackErr
+	}
+	_, ok := errors.Cause(err).(*RollbackTimeoutError)
+	return ok
+}
+
+// RollbackHaltedError represents the rollback that was stopped at the first
+// rollback task that failed
+type RollbackHaltedError struct {
+	// FailedTask is the identity of the rollback task that failed
+	FailedTask string
+	// RolledBack are the identities of the rollback tasks that completed
+	// before the failure
+	RolledBack []string
+	// Pending are the identities of the rollback tasks that were not
+	// executed due to the failure
+	Pending []string
+	// Err is the error of the failed rollback task
+	Err error
+}
+
+func (e *RollbackHaltedError) Error() string {
+	return fmt.Sprintf("rollback halted at runtask '%s': rolled back '%s': pending '%s': %s", e.FailedTask, strings.Join(e.RolledBack, ", "), strings.Join(e.Pending, ", "), e.Err)
+}
+
+// Cause returns the error of the failed rollback task
+func (e *RollbackHaltedError) Cause() error {
+	return e.Err
+}
+
+// AsRollbackHaltedError returns the RollbackHaltedError if the provided
+// error or the rollback error of the provided RollbackError is one
+func AsRollbackHaltedError(err error) (*RollbackHaltedError, bool) {
+	if rerr, ok := err.(*RollbackError); ok {
+		err = rerr.RollbackErr
+	}
+	herr, ok := err.(*RollbackHaltedError)
+	return herr, ok
+}
+
+// RollbackOnly rolls back the objects created by the tasks with the provided
+// identities. The rollbacks of other tasks are left as is. Rolled back tasks
+// are no longer planned for rollback.
+//
+// NOTE:
+//  This is meant to be invoked after inspecting the outcome of a run whose
+// executed tasks were not rolled back e.g. a run with ContinueOnError policy
+func (m *TaskGroupRunner) RollbackOnly(ids ...string) error {
+	selected, resets := m.rollbacks.take(ids)
+
+	outcome := &rollbackOutcome{}
+	err := m.executeRollbacks(context.Background(), selected, outcome)
+	m.rollbacks.publish(outcome, resets)
+	return err
+}
+
+// executeRollbacks executes the provided rollback tasks in their order. It
+// records the outcome of these tasks in the provided outcome & returns the
+// aggregated error of the rollback tasks that failed.
+func (m *TaskGroupRunner) executeRollbacks(ctx context.Context, rollbacks []*taskExecutor, outcome *rollbackOutcome) (err error) {
+	count := len(rollbacks)
+	if count == 0 {
+		m.logger().Info("nothing to rollback: no rollback tasks were found")
+		return nil
+	}
+
+	ctx, span := startSpan(ctx, "rollback")
+	defer func() { endSpan(span, err) }()
+
+	m.logger().Info("will rollback previously executed runtask(s)")
+	m.recordRollbackTriggered()
+
+	rollbackStarted := time.Now()
+	m.emit(TaskEvent{Type: RollbackStartedEvent})
+
+	var (
+		errs       *multierror.Error
+		rolledBack []string
+		halted     *RollbackHaltedError
+	)
+	for idx, rte := range rollbacks {
+		started := time.Now()
+		retries, err := m.rollbackATask(ctx, rte)
+		outcome.recordRolledBack(rte.getTaskIdentity(), started, retries, err)
+		m.recordRollback(rte.getTaskIdentity(), err)
+		if err == nil {
+			rolledBack = append(rolledBack, rte.getTaskIdentity())
+			continue
+		}
+
+		m.logger().Error(err, "failed to rollback runtask", "task", rte.getTaskIdentity(), "objectName", rte.getTaskObjectName())
+		outcome.recordLeaked(rte.getTaskObjectName())
+		if !m.rollbacks.stopOnFailure {
+			// continue with the next rollbacks
+			errs = multierror.Append(errs, errors.Wrapf(err, "failed to rollback runtask '%s'", rte.getTaskIdentity()))
+			continue
+		}
+
+		halted = &RollbackHaltedError{FailedTask: rte.getTaskIdentity(), RolledBack: rolledBack, Err: err}
+		for _, pending := range rollbacks[idx+1:] {
+			halted.Pending = append(halted.Pending, pending.getTaskIdentity())
+			// objects of the pending rollbacks are left as is
+			outcome.recordLeaked(pending.getTaskObjectName())
+		}
+		m.logger().Error(halted, "operator needs to intervene")
+		break
+	}
+
+	if len(outcome.leaked) != 0 {
+		m.logger().Error(nil, "failed to rollback objects: these need to be cleaned up manually", "objects", strings.Join(outcome.leaked, ", "))
+	}
+
+	if halted != nil {
+		m.emit(TaskEvent{Type: RollbackCompletedEvent, Duration: time.Since(rollbackStarted), Err: halted})
+		return halted
+	}
+	m.emit(TaskEvent{Type: RollbackCompletedEvent, Duration: time.Since(rollbackStarted), Err: errs.ErrorOrNil()})
+	return errs.ErrorOrNil()
+}
+
+// orderRollbacks returns the provided rollback tasks in their order of
+// execution i.e. in the **reverse order** of planning. Rollback tasks with
+// higher rollback priority are ordered first.
+func orderRollbacks(rollbacks []*taskExecutor) []*taskExecutor {
+	count := len(rollbacks)
+	ordered := make([]*taskExecutor, 0, count)
+	for i := count - 1; i >= 0; i-- {
+		ordered = append(ordered, rollbacks[i])
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].metaTaskExec.getRollbackPriority() > ordered[j].metaTaskExec.getRollbackPriority()
+	})
+	return ordered
+}
+
+// rollbackATask executes the provided rollback task. Execution is retried as
+// per this runner's rollback retries if it fails & if the provided context is
+// not done.
+func (m *TaskGroupRunner) rollbackATask(ctx context.Context, rte *taskExecutor) (retries int, err error) {
+	defer m.metrics.rollbackTaskDone(rte)
+
+	for ; ; retries++ {
+		err = rte.ExecuteIt()
+		if template.IsNotFound(err) {
+			// nothing to rollback since the object is already gone
+			m.logger().Info("skipping rollback of runtask: object was not found", "task", rte.getTaskIdentity(), "objectName", rte.getTaskObjectName())
+			return retries, nil
+		}
+		if err == nil || retries >= m.rollbacks.retries {
+			return
+		}
+
+		m.logger().Error(err, "failed to rollback runtask: will retry", "task", rte.getTaskIdentity(), "attempt", retries+1, "after", m.rollbacks.retryInterval)
+		select {
+		case <-time.After(m.rollbacks.retryInterval):
+		case <-ctx.Done():
+			m.logger().Error(ctx.Err(), "will not retry rollback of runtask", "task", rte.getTaskIdentity())
+			return
+		}
+	}
+}
+
+// rollbackOutcome holds the outcome of the rollback tasks executed by a
+// single rollback. It is local to the rollback & is published to the runner
+// once the rollback completes.
+type rollbackOutcome struct {
+	// rolledBack holds the outcome of the executed rollback tasks
+	rolledBack []TaskResult
+
+	// leaked holds the names of the objects whose rollback failed
+	leaked []string
+}
+
+// recordRolledBack records the outcome of an executed rollback task
+func (o *rollbackOutcome) recordRolledBack(identity string, started time.Time, retries int, err error) {
+	status := TaskSucceeded
+	if err != nil {
+		status = TaskFailed
+	}
+
+	o.rolledBack = append(o.rolledBack, TaskResult{
+		Identity: identity,
+		Status:   status,
+		Started:  started,
+		Duration: time.Since(started),
+		Retries:  retries,
+		Err:      err,
+	})
+}
+
+// recordLeaked records the name of the object whose rollback failed
+func (o *rollbackOutcome) recordLeaked(objectName string) {
+	o.leaked = append(o.leaked, strings.TrimSpace(objectName))
+}
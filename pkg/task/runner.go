@@ -17,17 +17,23 @@ limitations under the License.
 package task
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/golang/glog"
+	"github.com/hashicorp/go-multierror"
 	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
 	"github.com/openebs/maya/pkg/template"
 	"github.com/openebs/maya/pkg/util"
+	"github.com/pkg/errors"
 )
 
 // redactJsonResult will update the provided map by removing the original json
-// result doc i.e. bytes and replace it with "--redacted--"
+// result doc i.e. bytes and replace it with "--redacted--". The value of the
+// secret referred to by the task, if any, is redacted as well.
 //
 // NOTE:
 //  This should be done once the task group runner has finished executing all
@@ -35,77 +41,1015 @@ import (
 // the error.
 func redactJsonResult(templateValues map[string]interface{}) {
 	templateValues[string(v1alpha1.CurrentJSONResultTLP)] = "--redacted--"
+	if _, ok := templateValues[string(v1alpha1.SecretTLP)]; ok {
+		templateValues[string(v1alpha1.SecretTLP)] = "--redacted--"
+	}
+}
+
+// redactTemplateValues returns a copy of the provided template values where
+// the values of the matching keys are replaced with "--redacted--". A key
+// matches if it is the dot separated path of a nested value or is a suffix
+// of this path e.g. "chapSecret" as well as "TaskResult.t1.chapSecret"
+// match the path "TaskResult.t1.chapSecret".
+//
+// NOTE:
+//  The provided template values are not mutated since these are used by
+// the tasks that follow
+func redactTemplateValues(values map[string]interface{}, keys []string) map[string]interface{} {
+	if len(keys) == 0 {
+		return values
+	}
+	return redactNestedValues(values, "", keys)
+}
+
+// redactNestedValues redacts the values of the provided map whose paths
+// match any of the provided keys; path is the path of this map
+func redactNestedValues(values map[string]interface{}, path string, keys []string) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+
+	redacted := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		fieldPath := k
+		if len(path) != 0 {
+			fieldPath = path + "." + k
+		}
+
+		if isRedactedPath(fieldPath, keys) {
+			redacted[k] = "--redacted--"
+			continue
+		}
+		redacted[k] = redactNestedValue(v, fieldPath, keys)
+	}
+	return redacted
+}
+
+// redactNestedValue redacts the nested values of the provided value whose
+// paths match any of the provided keys; path is the path of this value
+//
+// NOTE:
+//  Items of a list have the path of the list e.g. "password" of each item
+// of the list "TaskResult.t1.users" has the path
+// "TaskResult.t1.users.password"
+func redactNestedValue(value interface{}, path string, keys []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return redactNestedValues(v, path, keys)
+	case map[string]string:
+		if v == nil {
+			return v
+		}
+		redacted := make(map[string]string, len(v))
+		for k, s := range v {
+			redacted[k] = s
+			if isRedactedPath(path+"."+k, keys) {
+				redacted[k] = "--redacted--"
+			}
+		}
+		return redacted
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactNestedValue(item, path, keys)
+		}
+		return redacted
+	}
+	return value
+}
+
+// isRedactedPath flags if the provided path matches any of the provided keys
+// either exactly or as a suffix
+func isRedactedPath(path string, keys []string) bool {
+	for _, key := range keys {
+		if path == key || strings.HasSuffix(path, "."+key) {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupRunner abstracts running a group of tasks against the provided
+// template values
+//
+// NOTE:
+//  This is implemented by TaskGroupRunner. It enables the callers to use a
+// fake runner e.g. FakeTaskGroupRunner of pkg/task/testing during UT.
+type GroupRunner interface {
+	Run(ctx context.Context, values map[string]interface{}) (output []byte, err error)
 }
 
+// TaskGroupRunner implements GroupRunner
+var _ GroupRunner = &TaskGroupRunner{}
+
 // PostTaskRunFn is a closure definition that provides option
 // to act on an individual task's result
+//
+// NOTE:
+//  The task's result i.e. a copy of .TaskResult.<taskID> is provided. If the
+// task failed, the error is set at executeErr property of this result. Result
+// is nil if the task failed even before its identity could be determined.
 type PostTaskRunFn func(taskResult map[string]interface{})
 
+// PreTaskRunFn is a closure definition that provides option to augment the
+// template values of a task right before the task is executed
+//
+// NOTE:
+//  The task's template values are provided & can be mutated e.g. to set the
+// values known only before this task executes. The task is not executed &
+// the run fails if an error is returned.
+type PreTaskRunFn func(taskID string, values map[string]interface{}) error
+
+// ResultTransformerFn is a closure definition that provides option to
+// normalize or validate the result of a task right after the task is
+// executed & before the following tasks make use of this result
+//
+// NOTE:
+//  The task's result i.e. .TaskResult.<taskID> is provided & can be mutated
+// e.g. to coerce the types of its values. The task fails & is rolled back if
+// an error is returned.
+type ResultTransformerFn func(taskID string, result map[string]interface{}) error
+
 // TaskGroupRunner helps in running a set of Tasks in sequence
 type TaskGroupRunner struct {
 	// allTaskIDs will hold the identity of the run tasks managed by this
 	// group runner
 	allTaskIDs []string
+	// seededResults are the task results set against the template values
+	// before the tasks are executed; these are mapped by task identities
+	seededResults map[string]map[string]interface{}
 	// allTasks is an array of run tasks
 	allTasks []*v1alpha1.RunTask
-	// outputTask holds the specs to return this group runner's
-	// output in the format (i.e. specs) defined in this output run task
-	outputTask *v1alpha1.RunTask
-	// fallbackTemplate is the CAS Template to fallback to; is optional
-	fallbackTemplate string
-	// rollbacks is an array of task executor that need to be run in
-	// sequence in the event of any error
-	rollbacks []*taskExecutor
+	// taskIndex maps the identities of the run tasks to the run tasks; is
+	// built on demand by RunTask & is reset when the run tasks change
+	taskIndex map[string]*v1alpha1.RunTask
+	// outputTasks hold the specs to return this group runner's
+	// output in the format (i.e. specs) defined in these output run tasks
+	outputTasks []*v1alpha1.RunTask
+	// outputSeparator joins the outputs of the output tasks; defaults to
+	// DefaultOutputSeparator
+	outputSeparator string
+	// dependencies maps a task identity to the identities of the tasks that
+	// must complete before this task is executed; is optional
+	dependencies map[string][]string
+	// skippedGroups are the group labels whose tasks are not executed
+	skippedGroups []string
+	// outputExpected flags if this runner needs an output task
+	outputExpected bool
+	// alwaysRunOutput flags if the output task is run even when the tasks
+	// of this runner failed
+	alwaysRunOutput bool
+	// fallbackTemplates are the CAS Templates to fallback to in their order
+	// of preference; is optional
+	fallbackTemplates []string
+	// fallbackFn runs a fallback template; defaults to runFallbackTemplate
+	fallbackFn func(ctx context.Context, castemplate string, values map[string]interface{}) ([]byte, error)
+	// resolveFallbackFn verifies if a fallback template is available;
+	// defaults to resolveFallbackTemplate
+	resolveFallbackFn func(castemplate string) error
+	// fallbackPredicate decides if a failed run falls back to the fallback
+	// templates; defaults to the check for version mismatch error
+	fallbackPredicate func(err error) bool
+	// circuitBreaker decides if a task can be executed; is optional
+	circuitBreaker CircuitBreaker
+	// runTaskCache caches the runtasks of the fallback templates; is optional
+	runTaskCache *RunTaskCache
+	// resultCache caches the results of read only tasks; is optional
+	resultCache ResultCache
+	// strictValidation flags if all the tasks are validated before executing
+	// any of them
+	strictValidation bool
+	// version is negotiated against the supported version range of this
+	// runner & its fallback templates; there is no negotiation if this is not
+	// set
+	version string
+	// minVersion is the minimum version supported by this runner; is optional
+	minVersion string
+	// maxVersion is the maximum version supported by this runner; is optional
+	maxVersion string
+	// rollbacks plans the rollbacks of the executed tasks & holds the
+	// outcome of the executed rollbacks
+	rollbacks runnerRollbacks
+	// concurrency is the maximum number of tasks that can be executed at the
+	// same time; tasks are executed in sequence if this is less than 2
+	concurrency int
+	// parallelGroups holds groups of indices (w.r.t allTasks) of the tasks
+	// that can be executed in parallel
+	parallelGroups [][]int
+	// timeout is the maximum duration all the tasks of this runner are
+	// allowed to execute; there is no timeout if this is not set
+	timeout time.Duration
+	// postTaskRunFns are invoked after the execution of each task; is optional
+	postTaskRunFns []PostTaskRunFn
+	// ran flags if this runner was run; a runner needs to be reset before it
+	// is run again
+	ran bool
+	// lastValues are the template values of the last run; is used to merge
+	// the results of the tasks after the run
+	lastValues map[string]interface{}
+	// preTaskRunFn is invoked before the execution of each task; is
+	// optional
+	preTaskRunFn PreTaskRunFn
+	// resultTransformer is invoked with the result of each task that is
+	// executed successfully; is optional
+	resultTransformer ResultTransformerFn
+	// valuesSnapshotHook is invoked with a copy of the template values after
+	// each task that succeeds; is optional
+	valuesSnapshotHook ValuesSnapshotHook
+	// executed holds the outcome of the tasks that were executed
+	executed []TaskResult
+	// retryPolicy is the policy to retry the tasks that do not set their own
+	// retry policy; is optional
+	retryPolicy v1alpha1.RetryPolicy
+	// templateFuncs are the template functions available to the templates of
+	// the tasks in addition to the ones supported by the template library
+	templateFuncs template.FuncMap
+	// secretFetcher fetches the secrets referred to by the tasks; defaults
+	// to K8sSecretFetcher
+	secretFetcher SecretFetcher
+	// secrets caches the secrets fetched during a run
+	secrets *secretCache
+	// checkpointer saves the progress of a run so that an interrupted run can
+	// be resumed; is optional
+	checkpointer Checkpointer
+	// runnerID identifies the checkpoint of this runner
+	runnerID string
+	// completed holds the identities of the tasks that completed in the
+	// current run including the ones restored from the checkpoint
+	completed []string
+	// resumed holds the identities of the tasks that were restored from the
+	// checkpoint
+	resumed []string
+	// resumedRollbacks holds the names of the objects planned for rollback by
+	// the identities of the tasks that were restored from the checkpoint
+	resumedRollbacks map[string][]string
+	// errorPolicy determines how this runner reacts to the failure of a task;
+	// defaults to FailFast
+	errorPolicy ErrorPolicy
+	// fellBack flags if this runner fell back to the fallback template
+	fellBack bool
+	// eventRecorder records the events that occur while executing the tasks;
+	// is optional
+	eventRecorder TaskEventRecorder
+	// updateCh receives the updates of a run that is being streamed
+	updateCh chan<- TaskUpdate
+	// eventCh receives the events that occur while running the tasks; is
+	// optional
+	eventCh chan<- TaskEvent
+	// metrics are the prometheus metrics recorded while executing the tasks;
+	// is optional
+	metrics *runnerMetrics
+	// metricsRecorder records the metrics of this runner; is optional
+	metricsRecorder MetricsRecorder
+	// maxObjectsCreated is the maximum number of objects the tasks of this
+	// runner are allowed to create in a run; there is no limit if this is not
+	// set
+	maxObjectsCreated int
+	// objectsCreated is the number of objects created by the tasks of this
+	// runner in the current run
+	objectsCreated int
+	// redactKeys are the keys whose template values are redacted before the
+	// template values are logged; is optional
+	redactKeys []string
+	// tracer traces the execution of this runner if the context provided to
+	// Run does not hold a tracer; is optional
+	tracer Tracer
+	// log logs the messages of this runner; defaults to the logger of this
+	// package
+	log Logger
+	// mutex guards the runner's state that gets updated while executing the
+	// tasks in parallel
+	mutex sync.Mutex
+}
+
+// ErrorPolicy determines how a task group runner reacts to the failure of a
+// task
+type ErrorPolicy string
+
+const (
+	// FailFast stops the execution of the remaining tasks on failure of a
+	// task & rolls back the executed tasks. This is the default policy.
+	FailFast ErrorPolicy = "failFast"
+	// ContinueOnError records the failure of a task & continues with the
+	// execution of the remaining tasks. Executed tasks are not rolled back.
+	//
+	// NOTE:
+	//  This is meant for best effort flows e.g. deletion of a volume's
+	// objects
+	ContinueOnError ErrorPolicy = "continueOnError"
+)
+
+// TaskGroupRunnerOption is a typed function that abstracts setting of an
+// option against a task group runner
+type TaskGroupRunnerOption func(*TaskGroupRunner)
+
+// WithTimeout sets the maximum duration all the tasks of the task group
+// runner are allowed to execute. Tasks that are yet to be executed are not
+// executed once this timeout fires & the executed tasks are rolled back.
+func WithTimeout(d time.Duration) TaskGroupRunnerOption {
+	return func(m *TaskGroupRunner) {
+		m.timeout = d
+	}
+}
+
+// WithRetryPolicy sets the policy to retry the tasks of the task group
+// runner on retryable errors. This policy is used only for the tasks that do
+// not set their own retry policy.
+func WithRetryPolicy(policy v1alpha1.RetryPolicy) TaskGroupRunnerOption {
+	return func(m *TaskGroupRunner) {
+		m.retryPolicy = policy
+	}
+}
+
+// WithPostTaskRunFn adds a function to be invoked after the execution of
+// each task of the task group runner
+func WithPostTaskRunFn(fn PostTaskRunFn) TaskGroupRunnerOption {
+	return func(m *TaskGroupRunner) {
+		m.AddPostTaskRunFn(fn)
+	}
+}
+
+func NewTaskGroupRunner(opts ...TaskGroupRunnerOption) *TaskGroupRunner {
+	m := &TaskGroupRunner{}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
+
+func (m *TaskGroupRunner) AddRunTask(runtask *v1alpha1.RunTask) (err error) {
+	err = validateRunTask(runtask)
+	if err != nil {
+		return
+	}
+
+	m.allTasks = append(m.allTasks, runtask)
+	m.taskIndex = nil
+	return
+}
+
+// validateRunTask verifies if the provided run task can be added to a runner
+func validateRunTask(runtask *v1alpha1.RunTask) error {
+	if runtask == nil {
+		return fmt.Errorf("nil runtask: failed to add run task")
+	}
+
+	if len(runtask.Spec.Meta) == 0 {
+		return fmt.Errorf("failed to add run task: nil meta task specs found: task name '%s'", runtask.Name)
+	}
+
+	return nil
+}
+
+// AddRunTasks adds the provided run tasks to this runner. All the tasks are
+// validated & errors if any are returned. None of the tasks are added if
+// there are errors.
+//
+// NOTE:
+//  Run tasks are identified by their names since their identities are known
+// only after templating their meta specifications. Hence tasks having names
+// that are duplicate within the batch or that are already present in this
+// runner result in errors.
+//
+// NOTE:
+//  A run task instance that is present more than once is added only once
+//
+// NOTE:
+//  Each error reports the index of the offending run task in the provided
+// run tasks. Tasks that are added are in the provided order.
+func (m *TaskGroupRunner) AddRunTasks(runtasks []*v1alpha1.RunTask) (errs []error) {
+	names := map[string]bool{}
+	added := map[*v1alpha1.RunTask]bool{}
+	for _, runtask := range m.allTasks {
+		names[runtask.Name] = true
+		added[runtask] = true
+	}
+
+	var batch []*v1alpha1.RunTask
+	for idx, runtask := range runtasks {
+		err := validateRunTask(runtask)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid runtask at index '%d'", idx))
+			continue
+		}
+
+		if added[runtask] {
+			m.logger().Info("runtask is added more than once: will be added only once", "runtask", runtask.Name)
+			continue
+		}
+		added[runtask] = true
+
+		if names[runtask.Name] {
+			errs = append(errs, fmt.Errorf("invalid runtask at index '%d': failed to add run task: duplicate task name '%s'", idx, runtask.Name))
+			continue
+		}
+		names[runtask.Name] = true
+
+		batch = append(batch, runtask)
+	}
+
+	if len(errs) != 0 {
+		return
+	}
+
+	m.allTasks = append(m.allTasks, batch...)
+	m.taskIndex = nil
+	return
+}
+
+// MustAddRunTasks adds the provided run tasks to this runner. It panics if
+// there are any errors.
+func (m *TaskGroupRunner) MustAddRunTasks(runtasks []*v1alpha1.RunTask) {
+	errs := m.AddRunTasks(runtasks)
+	if len(errs) != 0 {
+		panic(fmt.Sprintf("failed to add run tasks: %v", errs))
+	}
+}
+
+// AddParallelRunTasks adds the provided run tasks as a group. Tasks belonging
+// to a group are executed in parallel. The group as a whole is executed in
+// sequence w.r.t other tasks of this runner.
+//
+// NOTE:
+//  Tasks added as a group should not depend on each other's results
+//
+// NOTE:
+//  Groups can not be used along with the task dependencies set via
+// SetTaskDependencies
+func (m *TaskGroupRunner) AddParallelRunTasks(runtasks ...*v1alpha1.RunTask) (err error) {
+	for _, runtask := range runtasks {
+		if runtask == nil {
+			err = fmt.Errorf("nil runtask: failed to add parallel run tasks")
+			return
+		}
+
+		if len(runtask.Spec.Meta) == 0 {
+			err = fmt.Errorf("failed to add parallel run tasks: nil meta task specs found: task name '%s'", runtask.Name)
+			return
+		}
+	}
+
+	var group []int
+	for _, runtask := range runtasks {
+		group = append(group, len(m.allTasks))
+		m.allTasks = append(m.allTasks, runtask)
+	}
+	m.parallelGroups = append(m.parallelGroups, group)
+	m.taskIndex = nil
+	return
+}
+
+// indexOfRunTask returns the index of the run task having the provided name;
+// -1 is returned if there is no such run task
+func (m *TaskGroupRunner) indexOfRunTask(name string) int {
+	for idx, runtask := range m.allTasks {
+		if runtask.Name == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+// ReplaceRunTask replaces the run task having the provided name with the
+// provided run task. The provided run task takes the position of the replaced
+// run task including its parallel group if any.
+//
+// NOTE:
+//  This is safe only before this runner is run
+func (m *TaskGroupRunner) ReplaceRunTask(name string, runtask *v1alpha1.RunTask) error {
+	err := validateRunTask(runtask)
+	if err != nil {
+		return errors.Wrapf(err, "failed to replace run task '%s'", name)
+	}
+
+	idx := m.indexOfRunTask(name)
+	if idx < 0 {
+		return fmt.Errorf("failed to replace run task: run task '%s' is not found", name)
+	}
+	if runtask.Name != name && m.indexOfRunTask(runtask.Name) >= 0 {
+		return fmt.Errorf("failed to replace run task '%s': duplicate task name '%s'", name, runtask.Name)
+	}
+
+	m.allTasks[idx] = runtask
+	m.taskIndex = nil
+	return nil
+}
+
+// RemoveRunTask removes the run task having the provided name. The run task
+// is removed from its parallel group as well if any.
+//
+// NOTE:
+//  This is safe only before this runner is run
+func (m *TaskGroupRunner) RemoveRunTask(name string) error {
+	idx := m.indexOfRunTask(name)
+	if idx < 0 {
+		return fmt.Errorf("failed to remove run task: run task '%s' is not found", name)
+	}
+
+	m.allTasks = append(m.allTasks[:idx], m.allTasks[idx+1:]...)
+	m.taskIndex = nil
+
+	// parallel groups refer to the tasks by their indices; hence the indices
+	// that follow the removed task are shifted
+	var groups [][]int
+	for _, group := range m.parallelGroups {
+		var shifted []int
+		for _, tidx := range group {
+			switch {
+			case tidx < idx:
+				shifted = append(shifted, tidx)
+			case tidx > idx:
+				shifted = append(shifted, tidx-1)
+			}
+		}
+		if len(shifted) != 0 {
+			groups = append(groups, shifted)
+		}
+	}
+	m.parallelGroups = groups
+	return nil
+}
+
+// DefaultOutputSeparator is the default separator that joins the outputs of
+// multiple output tasks
+const DefaultOutputSeparator = "\n---\n"
+
+// validateOutputTask verifies if the provided run task can be used as an
+// output task
+func validateOutputTask(runtask *v1alpha1.RunTask) error {
+	if runtask == nil {
+		return fmt.Errorf("nil run task found")
+	}
+
+	if len(runtask.Spec.Meta) == 0 {
+		return fmt.Errorf("nil meta task specs found: task name '%s'", runtask.Name)
+	}
+
+	if len(runtask.Spec.Task) == 0 {
+		return fmt.Errorf("nil task specs found: task name '%s'", runtask.Name)
+	}
+	return nil
+}
+
+// SetOutputTask sets this runner with a run task that will be used
+// to return the output after successful execution of this runner.
+// Output tasks added previously are replaced.
+//
+// NOTE:
+//  This output format is specified in the provided run task.
+func (m *TaskGroupRunner) SetOutputTask(runtask *v1alpha1.RunTask) (err error) {
+	err = validateOutputTask(runtask)
+	if err != nil {
+		return errors.Wrap(err, "failed to set output task")
+	}
+
+	m.outputTasks = []*v1alpha1.RunTask{runtask}
+	return
+}
+
+// AddOutputTask adds a run task to the output tasks of this runner. Outputs
+// of all the output tasks are rendered in their order of addition & are
+// joined by this runner's output separator.
+//
+// NOTE:
+//  This is meant for list operations that aggregate the results of multiple
+// tasks
+func (m *TaskGroupRunner) AddOutputTask(runtask *v1alpha1.RunTask) (err error) {
+	err = validateOutputTask(runtask)
+	if err != nil {
+		return errors.Wrap(err, "failed to add output task")
+	}
+
+	m.outputTasks = append(m.outputTasks, runtask)
+	return
+}
+
+// SetOutputSeparator sets the separator that joins the outputs of multiple
+// output tasks
+func (m *TaskGroupRunner) SetOutputSeparator(separator string) {
+	m.outputSeparator = separator
+}
+
+// getOutputSeparator returns the separator that joins the outputs of
+// multiple output tasks
+func (m *TaskGroupRunner) getOutputSeparator() string {
+	if len(m.outputSeparator) == 0 {
+		return DefaultOutputSeparator
+	}
+	return m.outputSeparator
+}
+
+// SetFallback sets this runner with fallback options in case this runner gets
+// into some specific errors e.g. version mismatch error. Fallbacks are ordered
+// from the most preferred to the least preferred.
+//
+// NOTE:
+//  This replaces the fallbacks that were added previously
+func (m *TaskGroupRunner) SetFallback(castemplates []string) {
+	m.fallbackTemplates = nil
+	for _, castemplate := range castemplates {
+		m.AddFallback(castemplate)
+	}
+}
+
+// AddFallback appends the provided CAS Template to this runner's chain of
+// fallbacks. Fallbacks are attempted in the order they were added.
+func (m *TaskGroupRunner) AddFallback(castemplate string) {
+	castemplate = strings.TrimSpace(castemplate)
+	if len(castemplate) == 0 {
+		return
+	}
+	m.fallbackTemplates = append(m.fallbackTemplates, castemplate)
+}
+
+// SetFallbackPredicate sets the function that decides if a failed run falls
+// back to this runner's fallback templates. This replaces the default
+// behaviour of falling back only in case of version mismatch error.
+//
+// NOTE:
+//  The predicate is invoked with the error that failed the run. Setting a
+// nil predicate restores the default behaviour.
+func (m *TaskGroupRunner) SetFallbackPredicate(fn func(err error) bool) {
+	m.fallbackPredicate = fn
+}
+
+// isFallback returns true if the provided error of a failed run should
+// result in falling back to the fallback templates
+func (m *TaskGroupRunner) isFallback(err error) bool {
+	// fallback templates would hit the same failing endpoints
+	if len(m.fallbackTemplates) == 0 || IsCircuitBreakerOpen(err) {
+		return false
+	}
+	if m.fallbackPredicate == nil {
+		return template.IsVersionMismatch(err)
+	}
+	return m.fallbackPredicate(err)
+}
+
+// SetResultCache sets the cache that is used to reuse the results of read
+// only tasks that are marked as cacheable
+func (m *TaskGroupRunner) SetResultCache(c ResultCache) {
+	m.resultCache = c
+}
+
+// SetRunTaskCache sets the cache that is used to fetch the runtasks of the
+// fallback templates instead of fetching them from the cluster
+//
+// NOTE:
+//  The cache is used only for the fallback templates whose task namespace is
+// the namespace of the cache
+func (m *TaskGroupRunner) SetRunTaskCache(c *RunTaskCache) {
+	m.runTaskCache = c
+}
+
+// SetAlwaysRunOutput sets this runner to run its output task even when its
+// tasks failed. The output is returned along with the error so that callers
+// get the partial status of the run.
+//
+// NOTE:
+//  The output task should make use of the safe navigation template functions
+// since the results of the tasks that were not executed are missing
+func (m *TaskGroupRunner) SetAlwaysRunOutput(always bool) {
+	m.alwaysRunOutput = always
+}
+
+// SetOutputExpected flags if the caller of this runner expects an output.
+// A run fails validation if output is expected & there is no output task.
+func (m *TaskGroupRunner) SetOutputExpected(expected bool) {
+	m.outputExpected = expected
+}
+
+// SkipGroups sets this runner to skip the tasks whose group label matches
+// any of the provided labels. Skipped tasks are neither executed nor rolled
+// back. Remaining tasks are executed in their order.
+func (m *TaskGroupRunner) SkipGroups(labels ...string) {
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if len(label) != 0 {
+			m.skippedGroups = append(m.skippedGroups, label)
+		}
+	}
 }
 
-func NewTaskGroupRunner() *TaskGroupRunner {
-	return &TaskGroupRunner{}
+// SetRedactKeys sets the keys whose template values are redacted before the
+// template values are logged. A key is either the dot separated path of a
+// template value or the suffix of this path.
+func (m *TaskGroupRunner) SetRedactKeys(keys ...string) {
+	m.redactKeys = keys
+}
+
+// SetMetricsRecorder sets the recorder of the metrics of this runner's task
+// executions, rollbacks & fallbacks. Metrics are not recorded if this is not
+// set.
+func (m *TaskGroupRunner) SetMetricsRecorder(r MetricsRecorder) {
+	m.metricsRecorder = r
+}
+
+// SetStrictValidation sets this runner to validate all its tasks before
+// executing any of them
+func (m *TaskGroupRunner) SetStrictValidation(strict bool) {
+	m.strictValidation = strict
+}
+
+// SetErrorPolicy sets the policy that determines how this runner reacts to
+// the failure of a task
+func (m *TaskGroupRunner) SetErrorPolicy(policy ErrorPolicy) {
+	m.errorPolicy = policy
+}
+
+// isContinueOnError flags if this runner continues with the remaining tasks
+// on failure of a task
+func (m *TaskGroupRunner) isContinueOnError() bool {
+	return m.errorPolicy == ContinueOnError
+}
+
+// isAbort flags if the provided error of executing the tasks stops the
+// execution of the remaining tasks
+//
+// NOTE:
+//  Exceeding the limit of created objects or an open circuit breaker stops
+// the execution even if this runner continues on error
+func (m *TaskGroupRunner) isAbort(err error) bool {
+	return !m.isContinueOnError() || IsMaxObjectsCreated(err) || IsCircuitBreakerOpen(err)
+}
+
+// SetMaxObjectsCreated sets the maximum number of objects the tasks of this
+// runner are allowed to create in a run. The run is aborted & the created
+// objects are rolled back once this limit is exceeded.
+//
+// NOTE:
+//  Objects are counted as & when their rollback is planned. This is a
+// guardrail against templates that create objects in a runaway loop.
+func (m *TaskGroupRunner) SetMaxObjectsCreated(n int) {
+	m.maxObjectsCreated = n
+}
+
+// SetTemplateFuncs sets the template functions that can be invoked from the
+// templates of this runner's tasks in addition to the ones supported by the
+// template library. An error is returned if any of these functions collides
+// with a function supported by the template library.
+func (m *TaskGroupRunner) SetTemplateFuncs(fns template.FuncMap) error {
+	err := template.ValidateTemplateFuncs(fns)
+	if err != nil {
+		return errors.Wrap(err, "failed to set template functions")
+	}
+
+	funcs := make(template.FuncMap, len(fns))
+	for name, fn := range fns {
+		funcs[name] = fn
+	}
+	m.templateFuncs = funcs
+	return nil
+}
+
+// SetSecretFetcher sets the fetcher of the secrets referred to by the tasks
+// of this runner
+func (m *TaskGroupRunner) SetSecretFetcher(fetcher SecretFetcher) {
+	m.secretFetcher = fetcher
+}
+
+// injectSecret sets the value of the secret referred to by the provided
+// runtask against the template values
+func (m *TaskGroupRunner) injectSecret(runtask *v1alpha1.RunTask, values map[string]interface{}) error {
+	ref := runtask.Spec.SecretRef
+	if ref == nil {
+		return nil
+	}
+
+	secrets := m.secrets
+	if secrets == nil {
+		secrets = newSecretCache(m.getSecretFetcher())
+	}
+	value, err := secrets.value(ref)
+	if err != nil {
+		return errors.Wrapf(err, "failed to inject secret into runtask '%s'", runtask.Name)
+	}
+	values[string(v1alpha1.SecretTLP)] = value
+	return nil
+}
+
+// getSecretFetcher returns the fetcher of the secrets referred to by the
+// tasks of this runner
+func (m *TaskGroupRunner) getSecretFetcher() SecretFetcher {
+	if m.secretFetcher == nil {
+		return K8sSecretFetcher{}
+	}
+	return m.secretFetcher
+}
+
+// SetVersion sets the version that is negotiated against the version range
+// supported by this runner & its fallback templates
+func (m *TaskGroupRunner) SetVersion(version string) {
+	m.version = strings.TrimSpace(version)
+}
+
+// SetVersionRange sets the minimum & maximum versions supported by this
+// runner. Either of these can be empty.
+func (m *TaskGroupRunner) SetVersionRange(minVersion, maxVersion string) {
+	m.minVersion = strings.TrimSpace(minVersion)
+	m.maxVersion = strings.TrimSpace(maxVersion)
+}
+
+// negotiateVersion returns VersionMismatchError if this runner's version does
+// not lie within the version range supported by this runner
+func (m *TaskGroupRunner) negotiateVersion() error {
+	if len(m.version) == 0 {
+		return nil
+	}
+	return template.VerifyVersionRange(m.version, m.minVersion, m.maxVersion)
+}
+
+// SetConcurrency sets the maximum number of tasks that this runner can execute
+// at the same time. All the tasks are executed in parallel if this is set to a
+// value greater than 1.
+//
+// NOTE:
+//  Tasks executed in parallel should not depend on each other's results
+func (m *TaskGroupRunner) SetConcurrency(n int) {
+	m.concurrency = n
+}
+
+// SetPostTaskRunFn sets the function to be invoked after the execution of
+// each task. This replaces the functions that were added previously.
+func (m *TaskGroupRunner) SetPostTaskRunFn(fn PostTaskRunFn) {
+	m.SetPostTaskRunFns(fn)
+}
+
+// SetPostTaskRunFns sets the functions to be invoked after the execution of
+// each task. This replaces the functions that were added previously.
+func (m *TaskGroupRunner) SetPostTaskRunFns(fns ...PostTaskRunFn) {
+	m.postTaskRunFns = nil
+	for _, fn := range fns {
+		m.AddPostTaskRunFn(fn)
+	}
+}
+
+// AddPostTaskRunFn adds a function to be invoked after the execution of each
+// task. Functions are invoked in the order they were added.
+//
+// NOTE:
+//  These functions are invoked concurrently if tasks are executed in parallel
+func (m *TaskGroupRunner) AddPostTaskRunFn(fn PostTaskRunFn) {
+	if fn == nil {
+		return
+	}
+	m.postTaskRunFns = append(m.postTaskRunFns, fn)
+}
+
+// SetPreTaskRunFn sets the function to be invoked before the execution of
+// each task. The function is invoked after the task's meta specifications
+// are rendered & before its task specifications are rendered.
+//
+// NOTE:
+//  This function is invoked concurrently if tasks are executed in parallel.
+// Each of these tasks provides its own copy of template values.
+func (m *TaskGroupRunner) SetPreTaskRunFn(fn PreTaskRunFn) {
+	m.preTaskRunFn = fn
+}
+
+// SetResultTransformer sets the function to be invoked with the result of
+// each task that is executed successfully. The result is transformed before
+// it is made available to the following tasks.
+//
+// NOTE:
+//  This function is invoked concurrently if tasks are executed in parallel
+func (m *TaskGroupRunner) SetResultTransformer(fn ResultTransformerFn) {
+	m.resultTransformer = fn
+}
+
+// transformResult invokes the result transformer if any with the result of
+// the provided task. The result is set at the template values if it was not
+// set by the task. A panic in the transformer is returned as an error.
+func (m *TaskGroupRunner) transformResult(identity string, values map[string]interface{}) (err error) {
+	if m.resultTransformer == nil {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("recovered from panic in result transformer: '%+v'", r)
+		}
+		if err != nil {
+			err = errors.Wrapf(err, "failed to execute runtask '%s': invalid result", identity)
+		}
+	}()
+
+	results, ok := values[string(v1alpha1.TaskResultTLP)].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("task results not found")
+	}
+	result, ok := results[identity].(map[string]interface{})
+	if !ok {
+		result = map[string]interface{}{}
+	}
+	err = m.resultTransformer(identity, result)
+	if err == nil && len(result) != 0 {
+		results[identity] = result
+	}
+	return
 }
 
-func (m *TaskGroupRunner) AddRunTask(runtask *v1alpha1.RunTask) (err error) {
-	if runtask == nil {
-		err = fmt.Errorf("nil runtask: failed to add run task")
+// preTaskRun invokes the pre task run function if any with the template
+// values of the provided task. A panic in this function is returned as an
+// error.
+func (m *TaskGroupRunner) preTaskRun(te *taskExecutor) (err error) {
+	if m.preTaskRunFn == nil {
 		return
 	}
 
-	if len(runtask.Spec.Meta) == 0 {
-		err = fmt.Errorf("failed to add run task: nil meta task specs found: task name '%s'", runtask.Name)
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("recovered from panic in pre task run fn: '%+v'", r)
+		}
+		if err != nil {
+			err = errors.Wrapf(err, "failed to execute runtask '%s': pre task run fn failed", te.getTaskIdentity())
+		}
+	}()
+	return m.preTaskRunFn(te.getTaskIdentity(), te.templateValues)
+}
+
+// postTaskRun invokes the post task run functions if any with the result of
+// the task. The result is sent as an update if the run is being streamed.
+func (m *TaskGroupRunner) postTaskRun(values map[string]interface{}, identity string, err error) {
+	if len(m.postTaskRunFns) == 0 && !m.isStreamed() {
 		return
 	}
 
-	m.allTasks = append(m.allTasks, runtask)
-	return
-}
+	var result map[string]interface{}
+	if len(identity) != 0 {
+		// copy ensures the runner's template values are not mutated by the
+		// functions
+		taskResult, _ := util.GetNestedField(values, string(v1alpha1.TaskResultTLP), identity).(map[string]interface{})
+		result = util.DeepCopyMap(taskResult)
+		if result == nil {
+			result = map[string]interface{}{}
+		}
+		if err != nil {
+			result[string(v1alpha1.TaskResultExecuteErrTRTP)] = err.Error()
+		}
+	}
 
-// SetOutputTask sets this runner with a run task that will be used
-// to return the output after successful execution of this runner.
-//
-// NOTE:
-//  This output format is specified in the provided run task.
-func (m *TaskGroupRunner) SetOutputTask(runtask *v1alpha1.RunTask) (err error) {
-	if runtask == nil {
-		err = fmt.Errorf("failed to set output task: nil run task found")
-		return
+	status := TaskSucceeded
+	if err != nil {
+		status = TaskFailed
 	}
+	m.sendUpdate(identity, status, result, err)
 
-	if len(runtask.Spec.Meta) == 0 {
-		err = fmt.Errorf("failed to set output task: nil meta task specs found: task name '%s'", runtask.Name)
-		return
+	for _, fn := range m.postTaskRunFns {
+		m.invokePostTaskRunFn(fn, identity, result)
 	}
+}
 
-	if len(runtask.Spec.Task) == 0 {
-		err = fmt.Errorf("failed to set output task: nil task specs found: task name '%s'", runtask.Name)
-		return
+// invokePostTaskRunFn invokes the provided function by recovering from panic
+// if any
+func (m *TaskGroupRunner) invokePostTaskRunFn(fn PostTaskRunFn, identity string, result map[string]interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger().Error(fmt.Errorf("%+v", r), "recovered from panic in post task run fn", "task", identity)
+		}
+	}()
+
+	fn(result)
+}
+
+// SeedTaskResult sets the provided result as the result of the task with the
+// provided identity. The result is set at .TaskResult.<identity> before the
+// tasks of this runner are executed so that the tasks can be rendered without
+// executing the task that would have produced this result.
+//
+// NOTE:
+//  Seeded identity is reserved; a task having this identity fails as a
+// duplicate
+//
+// NOTE:
+//  This is meant for testing a CAS template in isolation & for composing
+// runners
+func (m *TaskGroupRunner) SeedTaskResult(identity string, result map[string]interface{}) {
+	if m.seededResults == nil {
+		m.seededResults = map[string]map[string]interface{}{}
 	}
+	m.seededResults[identity] = result
+}
 
-	m.outputTask = runtask
-	return
+// isSeeded flags if the result of the provided task identity is seeded
+func (m *TaskGroupRunner) isSeeded(identity string) bool {
+	for seeded := range m.seededResults {
+		if strings.EqualFold(seeded, identity) {
+			return true
+		}
+	}
+	return false
 }
 
-// SetFallback sets this runner with a fallback option in case this runner gets
-// into some specific errors e.g. version mismatch error
-func (m *TaskGroupRunner) SetFallback(castemplate string) {
-	m.fallbackTemplate = strings.TrimSpace(castemplate)
+// applySeededResults sets the seeded task results against the provided
+// template values
+func (m *TaskGroupRunner) applySeededResults(values map[string]interface{}) {
+	for identity, result := range m.seededResults {
+		util.SetNestedField(values, util.DeepCopyMap(result), string(v1alpha1.TaskResultTLP), identity)
+	}
 }
 
 // isTaskIDUnique verifies if the tasks present in this group runner
@@ -113,7 +1057,7 @@ func (m *TaskGroupRunner) SetFallback(castemplate string) {
 func (m *TaskGroupRunner) isTaskIDUnique(identity string) (unique bool) {
 	id := strings.ToLower(identity)
 
-	if util.ContainsString(m.allTaskIDs, id) {
+	if m.isSeeded(id) || util.ContainsString(m.allTaskIDs, id) {
 		unique = false
 		return
 	}
@@ -124,83 +1068,300 @@ func (m *TaskGroupRunner) isTaskIDUnique(identity string) (unique bool) {
 	return
 }
 
-// planForRollback plans for rollback in case of future errors while executing
-// the tasks. This will add to the list of rollback tasks
+// TaskExecutionError represents an error due to failure in running a task
+type TaskExecutionError struct {
+	// TaskName is the name of the run task
+	TaskName string
+	// TaskID is the identity of the task; is not set if the task failed
+	// before its identity was known
+	TaskID string
+	// Err is the error that resulted from running the task
+	Err error
+}
+
+func (e *TaskExecutionError) Error() string {
+	if len(e.TaskID) == 0 {
+		return fmt.Sprintf("failed to run runtask '%s': %s", e.TaskName, e.Err)
+	}
+	return fmt.Sprintf("failed to run runtask '%s' with id '%s': %s", e.TaskName, e.TaskID, e.Err)
+}
+
+// Cause returns the error that resulted from running the task
 //
 // NOTE:
-//  This is just the planning for rollback & not actual rollback.
-// In the events of issues this planning will be useful.
-func (m *TaskGroupRunner) planForRollback(te *taskExecutor, objectName string) error {
-	// There are cases where multiple objects may be created due to a single
-	// RunTask. In such cases, object name will have comma separated list of
-	// object names.
-	objNames := strings.Split(objectName, ",")
+//  This lets errors.Cause to return the original error
+func (e *TaskExecutionError) Cause() error {
+	return e.Err
+}
 
-	// plan the rollback for all the objects that got created
-	for _, name := range objNames {
-		// entire rollback plan is encapsulated in the task itself
-		rte, err := te.asRollbackInstance(strings.TrimSpace(name))
-		if err != nil {
-			return err
+// AsTaskExecutionError returns the task execution error if any that is
+// present in the chain of errors wrapped by the provided error
+func AsTaskExecutionError(err error) (*TaskExecutionError, bool) {
+	for err != nil {
+		if taskErr, ok := err.(*TaskExecutionError); ok {
+			return taskErr, true
 		}
-
-		if rte == nil {
-			// this task does not need a rollback
-			continue
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			break
 		}
+		err = cause.Cause()
+	}
+	return nil, false
+}
 
-		m.rollbacks = append(m.rollbacks, rte)
+// newTaskExecutionError wraps the provided error of running a task into a
+// task execution error
+func newTaskExecutionError(runtask *v1alpha1.RunTask, te *taskExecutor, err error) error {
+	if err == nil {
+		return nil
 	}
 
-	return nil
+	taskErr := &TaskExecutionError{TaskName: runtask.Name, Err: err}
+	if te != nil {
+		taskErr.TaskID = te.getTaskIdentity()
+	}
+	return taskErr
 }
 
-// rollback will rollback the previously run operation(s)
-func (m *TaskGroupRunner) rollback() {
-	count := len(m.rollbacks)
-	if count == 0 {
-		glog.Warningf("nothing to rollback: no rollback tasks were found")
+// runFallbackTemplate runs the provided fallback CAS Template if this runner's
+// version lies within the version range supported by the fallback template
+func (m *TaskGroupRunner) runFallbackTemplate(ctx context.Context, castemplate string, values map[string]interface{}) (output []byte, err error) {
+	cast, err := getFallbackCAST(castemplate)
+	if err != nil {
 		return
 	}
 
-	glog.Warningf("will rollback previously executed runtask(s)")
-
-	// execute the rollback tasks in **reverse order**
-	for i := count - 1; i >= 0; i-- {
-		err := m.rollbacks[i].ExecuteIt()
+	if len(m.version) != 0 {
+		err = template.VerifyVersionRange(m.version, cast.Spec.MinVersion, cast.Spec.MaxVersion)
 		if err != nil {
-			// warn this rollback error & continue with the next rollbacks
-			glog.Warningf("failed to rollback run task: '%s': error '%s'", m.rollbacks[i], err.Error())
+			return
 		}
 	}
-}
 
-// rollback will rollback the previously run operation(s)
-func (m *TaskGroupRunner) fallback(values map[string]interface{}) (output []byte, err error) {
-	glog.Warningf("task group runner will fallback to '%s'", m.fallbackTemplate)
-	f, err := NewFallbackRunner(m.fallbackTemplate, values)
+	var fetcher TaskSpecFetcher
+	if m.runTaskCache != nil && m.runTaskCache.Namespace() == cast.Spec.TaskNamespace {
+		fetcher = m.runTaskCache
+	}
+
+	f, err := newFallbackRunner(cast, values, fetcher)
 	if err != nil {
 		return
 	}
+	// fallback templates can invoke the template functions of this runner
+	f.templateFuncs = m.templateFuncs
 
-	return RunFallback(f)
+	return RunFallback(ctx, f)
 }
 
-// runATask will run a task based on the task specs & template values
-func (m *TaskGroupRunner) runATask(runtask *v1alpha1.RunTask, values map[string]interface{}) (err error) {
-	te, err := newTaskExecutor(runtask, values)
+// fallback runs the chain of fallback templates in order till one of them
+// succeeds. The errors of all the failed fallbacks are returned if none of
+// them succeed.
+//
+// NOTE:
+//  Each fallback template is run against its own copy of the provided
+// template values. Hence the provided values are never mutated.
+func (m *TaskGroupRunner) fallback(ctx context.Context, values map[string]interface{}) (output []byte, err error) {
+	runFallback := m.fallbackFn
+	if runFallback == nil {
+		runFallback = m.runFallbackTemplate
+	}
+
+	m.recordFallbackTriggered()
+
+	ctx, span := startSpan(ctx, "fallback")
+	defer func() { endSpan(span, err) }()
+
+	var errs *multierror.Error
+	for _, castemplate := range m.fallbackTemplates {
+		m.logger().Info("task group runner will fallback", "castemplate", castemplate)
+		output, err = runFallback(ctx, castemplate, util.DeepCopyMap(values))
+		if err == nil {
+			return
+		}
+
+		m.logger().Error(err, "failed to fallback", "castemplate", castemplate)
+		errs = multierror.Append(errs, errors.Wrapf(err, "failed to fallback to '%s'", castemplate))
+	}
+
+	return nil, errs.ErrorOrNil()
+}
+
+// prepareATask builds the executor of a task based on the task specs &
+// template values. It also verifies if this task's identity is unique within
+// this group.
+func (m *TaskGroupRunner) prepareATask(runtask *v1alpha1.RunTask, values map[string]interface{}) (te *taskExecutor, err error) {
+	err = m.injectSecret(runtask, values)
+	if err != nil {
+		return
+	}
+
+	te, err = newTaskExecutorWithFuncs(runtask, values, m.templateFuncs)
 	if err != nil {
+		redactJsonResult(values)
 		// log with verbose details
-		glog.Errorf("failed to initialize runtask executor: name '%s': meta yaml '%s': template values in yaml '%s': template values '%+v'", runtask.Name, runtask.Spec.Meta, template.ToYaml(values), values)
+		m.logger().Error(err, "failed to initialize runtask executor", "runtask", runtask.Name, "meta", runtask.Spec.Meta, "values", template.DebugSnapshot(redactTemplateValues(values, m.redactKeys)))
 		return
 	}
 
 	// check if the task ID is unique in this group
 	if !m.isTaskIDUnique(te.getTaskIdentity()) {
-		return fmt.Errorf("failed to execute the run task: multiple tasks having same identity is not allowed in a group run: duplicate id '%s'", te.getTaskIdentity())
+		return nil, fmt.Errorf("failed to execute the run task: multiple tasks having same identity is not allowed in a group run: duplicate id '%s'", te.getTaskIdentity())
+	}
+
+	te.resultCache = m.resultCache
+	te.defaultRetryPolicy = m.retryPolicy
+	te.log = m.log
+
+	err = m.scopeATask(te)
+	if err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// executeWithTimeout executes the task & returns an error if the execution
+// does not complete before the task's timeout or before the provided context
+// is done. The timeout error is recorded at .TaskResult.<taskID>.timeoutErr
+//
+// NOTE:
+//  Task is executed against a copy of its template values if there is a
+// timeout. The values changed by this task are merged back into the task's
+// template values once the execution completes. This ensures an execution
+// that is still in progress does not mutate the template values that are in
+// use by other tasks.
+//
+// NOTE:
+//  Task's context is done once the timeout elapses. This aborts the waits,
+// watches & plugin calls of the task. However, the kubernetes clientsets used
+// by the task executor do not accept a context. Hence a timed out task is
+// waited for till its API call returns so that the objects it created are
+// known & planned for rollback.
+func (m *TaskGroupRunner) executeWithTimeout(ctx context.Context, te *taskExecutor) (err error) {
+	if te.timeout <= 0 && ctx.Done() == nil {
+		// nothing to bound the execution with
+		return te.retryOnError()
+	}
+
+	taskCtx := ctx
+	if te.timeout > 0 {
+		var cancel context.CancelFunc
+		taskCtx, cancel = context.WithTimeout(ctx, te.timeout)
+		defer cancel()
+	}
+
+	isolated := *te
+	isolated.ctx = taskCtx
+	isolated.templateValues = util.DeepCopyMap(te.templateValues)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- isolated.retryOnError()
+	}()
+
+	select {
+	case err = <-done:
+	case <-taskCtx.Done():
+		// task's objects if any are known only after it returns
+		<-done
+		if ctx.Err() != nil {
+			err = errors.Wrapf(ctx.Err(), "failed to execute runtask '%s'", te.getTaskIdentity())
+		} else {
+			err = errors.Wrapf(context.DeadlineExceeded, "failed to execute runtask '%s': timed out after '%s'", te.getTaskIdentity(), te.timeout)
+		}
+	}
+
+	te.metaTaskExec = isolated.metaTaskExec
+	te.retries = isolated.retries
+	te.existed = isolated.existed
+	te.renderedTask = isolated.renderedTask
+	// values are not changed by anyone else while the task is executed
+	// & hence are the base of the isolated values
+	m.mergeTaskValues(te.templateValues, te.templateValues, isolated.templateValues)
+	if errors.Cause(err) == context.DeadlineExceeded && ctx.Err() == nil {
+		util.SetNestedField(te.templateValues, err, string(v1alpha1.TaskResultTLP), te.getTaskIdentity(), string(v1alpha1.TaskResultTimeoutErrTRTP))
+	}
+	return
+}
+
+// executeATask executes the task and plans for its rollback. Task is skipped
+// if its run if predicate evaluates to false or if its disable predicate
+// evaluates to true.
+func (m *TaskGroupRunner) executeATask(ctx context.Context, te *taskExecutor) (err error) {
+	runtask := te.runtask
+	values := te.templateValues
+
+	if te.metaTaskExec.isSkip() {
+		// neither executed nor planned for rollback
+		m.logger().Info("skipping task because "+te.metaTaskExec.skipReason(), "task", te.getTaskIdentity())
+		values = m.unscopeATask(te)
+		redactJsonResult(values)
+		m.recordSkipped(runtask.Name, te.getTaskIdentity())
+		return
+	}
+
+	if te.metaTaskExec.isSkippedGroup(m.skippedGroups) {
+		// neither executed nor planned for rollback
+		m.logger().Info("skipping task because its group is skipped", "task", te.getTaskIdentity(), "group", te.metaTaskExec.metaTask.GroupLabel)
+		values = m.unscopeATask(te)
+		redactJsonResult(values)
+		m.recordSkipped(runtask.Name, te.getTaskIdentity())
+		return
+	}
+
+	err = m.preTaskRun(te)
+	if err != nil {
+		m.logger().Error(err, "failed to run pre task run fn", "task", te.getTaskIdentity())
+	} else if err = te.validateRollback(); err != nil {
+		m.logger().Error(err, "will not execute runtask: its objects can not be rolled back", "task", te.getTaskIdentity())
+	}
+	if err != nil {
+		// neither executed nor planned for rollback
+		values = m.unscopeATask(te)
+		redactJsonResult(values)
+		m.recordExecuted(TaskResult{
+			Name:         runtask.Name,
+			Identity:     te.getTaskIdentity(),
+			Started:      time.Now(),
+			RenderedMeta: te.metaTaskExec.rendered,
+			Err:          err,
+		})
+		m.postTaskRun(values, te.getTaskIdentity(), err)
+		return
 	}
 
-	errExecute := te.Execute()
+	ctx, span := startTaskSpan(ctx, te)
+	defer func() { endSpan(span, err) }()
+
+	var before map[string]interface{}
+	if isLogValuesDiff() {
+		before = util.DeepCopyMap(values)
+	}
+
+	te.ctx = ctx
+	started := time.Now()
+	m.metrics.taskStarted(te)
+	defer m.metrics.taskDone(te, started, &err)
+	defer m.observeTask(te, started, &err)
+
+	m.recordStart(runtask.Name, te.getTaskIdentity())
+	errExecute := m.executeWithTimeout(ctx, te)
+	// local variables of this task are not available to the following tasks
+	values = m.unscopeATask(te)
+	var ignoredErr error
+	if te.metaTaskExec.isIgnoredError(errExecute) {
+		m.logger().Info("ignoring error of task", "task", te.getTaskIdentity(), "class", template.ErrorClass(errExecute), "error", errExecute)
+		util.SetNestedField(values, errExecute.Error(), string(v1alpha1.TaskResultTLP), te.getTaskIdentity(), string(v1alpha1.TaskResultIgnoredErrTRTP))
+		ignoredErr, errExecute = errExecute, nil
+	}
+	if errExecute == nil {
+		errExecute = m.transformResult(te.getTaskIdentity(), values)
+	}
+	if errExecute == nil {
+		// snapshot is taken before the json result gets redacted
+		m.snapshotValues(te.getTaskIdentity(), values)
+	}
 
 	// remove the json doc (i.e. []byte) from template values since it will not
 	// be used anymore and if these template values are logged will not clutter
@@ -208,13 +1369,21 @@ func (m *TaskGroupRunner) runATask(runtask *v1alpha1.RunTask, values map[string]
 	redactJsonResult(values)
 
 	if errExecute != nil {
-		glog.Errorf("failed to execute runtask: name '%s': meta yaml '%s': task yaml '%s': template values in yaml '%s': template values '%+v'", runtask.Name, runtask.Spec.Meta, runtask.Spec.Task, template.ToYaml(values), values)
+		m.logger().Error(errExecute, "failed to execute runtask", "runtask", runtask.Name, "meta", te.metaTaskExec.rendered, "task", te.renderedTask, "values", template.DebugSnapshot(redactTemplateValues(values, m.redactKeys)))
 	}
 
 	// this is planning & not the actual rollback
-	errRollback := m.planForRollback(te, util.GetNestedString(values, string(v1alpha1.TaskResultTLP), te.getTaskIdentity(), string(v1alpha1.ObjectNameTRTP)))
+	//
+	// NOTE:
+	//  objects that existed before this task was executed are never rolled
+	// back
+	var errRollback error
+	objectNames := taskObjectNames(values, te.getTaskIdentity())
+	if !te.existed {
+		errRollback = m.planForRollback(te, objectNames)
+	}
 	if errRollback != nil {
-		glog.Errorf("failed to plan for rollback: '%+v'", errRollback)
+		m.logger().Error(errRollback, "failed to plan for rollback", "task", te.getTaskIdentity())
 	}
 
 	// err will always contain the higher priority error
@@ -225,61 +1394,385 @@ func (m *TaskGroupRunner) runATask(runtask *v1alpha1.RunTask, values map[string]
 	if errExecute != nil {
 		err = errExecute
 	}
+
+	span.SetAttribute("objectNames", objectNames)
+
+	status := TaskSucceeded
+	if te.existed {
+		status = TaskSkipped
+	}
+	m.recordExecuted(TaskResult{
+		Name:         runtask.Name,
+		Status:       status,
+		Identity:     te.getTaskIdentity(),
+		Started:      started,
+		Duration:     time.Since(started),
+		Retries:      te.retries,
+		ObjectNames:  objectNames,
+		RenderedMeta: te.metaTaskExec.rendered,
+		RenderedTask: te.renderedTask,
+		Err:          err,
+		IgnoredErr:   ignoredErr,
+	})
+	m.recordEnd(runtask.Name, te.getTaskIdentity(), err, time.Since(started))
+	if before != nil {
+		m.logTaskValuesDiff(te.getTaskIdentity(), before, values)
+	}
+	m.postTaskRun(values, te.getTaskIdentity(), err)
+	return
+}
+
+// runATask will run a task based on the task specs & template values
+func (m *TaskGroupRunner) runATask(ctx context.Context, runtask *v1alpha1.RunTask, values map[string]interface{}) (err error) {
+	err = m.allowTask(runtask)
+	if err != nil {
+		m.postTaskRun(values, "", err)
+		return newTaskExecutionError(runtask, nil, err)
+	}
+
+	te, err := m.prepareATask(runtask, values)
+	if err != nil {
+		m.postTaskRun(values, "", err)
+		return newTaskExecutionError(runtask, nil, err)
+	}
+
+	err = m.resumeOrExecute(ctx, te)
+	m.recordTaskOutcome(err)
+	return newTaskExecutionError(runtask, te, err)
+}
+
+// stages groups the tasks of this runner in the order of their execution.
+// Tasks belonging to the same stage are executed in parallel while the stages
+// themselves are executed in sequence.
+func (m *TaskGroupRunner) stages() (stages [][]*v1alpha1.RunTask) {
+	if len(m.parallelGroups) == 0 {
+		if m.concurrency > 1 {
+			// all the tasks are executed in parallel
+			return append(stages, m.allTasks)
+		}
+
+		for _, runtask := range m.allTasks {
+			stages = append(stages, []*v1alpha1.RunTask{runtask})
+		}
+		return
+	}
+
+	// index of a task w.r.t the parallel group it belongs to
+	groupOf := map[int]int{}
+	for gidx, group := range m.parallelGroups {
+		for _, tidx := range group {
+			groupOf[tidx] = gidx
+		}
+	}
+
+	for tidx := 0; tidx < len(m.allTasks); tidx++ {
+		gidx, ok := groupOf[tidx]
+		if !ok {
+			stages = append(stages, []*v1alpha1.RunTask{m.allTasks[tidx]})
+			continue
+		}
+
+		var stage []*v1alpha1.RunTask
+		for _, idx := range m.parallelGroups[gidx] {
+			stage = append(stage, m.allTasks[idx])
+		}
+		stages = append(stages, stage)
+
+		// skip the rest of the tasks of this group
+		tidx = m.parallelGroups[gidx][len(m.parallelGroups[gidx])-1]
+	}
+
 	return
 }
 
 // runAllTasks will run all tasks in the sequence as defined in the array
-func (m *TaskGroupRunner) runAllTasks(values map[string]interface{}) (err error) {
-	for _, runtask := range m.allTasks {
-		err = m.runATask(runtask, values)
+func (m *TaskGroupRunner) runAllTasks(ctx context.Context, values map[string]interface{}) (err error) {
+	stages := m.stages()
+	if len(m.dependencies) != 0 {
+		stages, err = m.dependencyStages(values)
 		if err != nil {
 			return
 		}
 	}
 
-	return
+	var errs *multierror.Error
+	for _, stage := range stages {
+		if ctx.Err() != nil {
+			err = errors.Wrap(ctx.Err(), "failed to execute runtasks")
+			if errs != nil {
+				// include the errors of the tasks that failed previously
+				err = multierror.Append(errs, err)
+			}
+			return
+		}
+
+		if len(stage) > 1 {
+			err = m.runTasksInParallel(ctx, stage, values)
+		} else {
+			err = m.runATask(ctx, stage[0], values)
+		}
+		m.saveCheckpoint(values)
+
+		if err == nil {
+			continue
+		}
+		if m.isAbort(err) {
+			if errs != nil {
+				// include the errors of the tasks that failed previously
+				err = multierror.Append(errs, err)
+			}
+			return
+		}
+		// record this error & continue with the remaining tasks
+		m.logger().Error(err, "will continue with the remaining runtasks")
+		errs = multierror.Append(errs, err)
+	}
+
+	return errs.ErrorOrNil()
 }
 
-// runOutput gets the output of this runner once all the tasks were executed
-// successfully
-func (m *TaskGroupRunner) runOutput(values map[string]interface{}) (output []byte, err error) {
+// runOutput gets the output of this runner as defined by its output tasks.
+// Outputs of multiple output tasks are joined by the output separator.
+func (m *TaskGroupRunner) runOutput(ctx context.Context, values map[string]interface{}) (output []byte, err error) {
+	_, span := startSpan(ctx, "output")
+	defer func() { endSpan(span, err) }()
 
-	if m.outputTask == nil || len(m.outputTask.Spec.Task) == 0 {
-		// nothing needs to be done
-		return
+	var outputs [][]byte
+	for _, outputTask := range m.outputTasks {
+		if len(outputTask.Spec.Task) == 0 {
+			// nothing needs to be done
+			continue
+		}
+
+		te, err := newTaskExecutorWithFuncs(outputTask, values, m.templateFuncs)
+		if err != nil {
+			return nil, err
+		}
+
+		out, err := te.Output()
+		if err != nil {
+			// log with verbose details
+			m.logger().Error(err, "failed to execute output task", "runtask", outputTask.Name, "task", outputTask.Spec.Task, "values", template.DebugSnapshot(redactTemplateValues(values, m.redactKeys)))
+			return nil, err
+		}
+
+		out, err = renderOutput(outputTask, out, values)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, out)
 	}
 
-	te, err := newTaskExecutor(m.outputTask, values)
-	if err != nil {
+	if len(outputs) == 0 {
 		return
 	}
+	return bytes.Join(outputs, []byte(m.getOutputSeparator())), nil
+}
+
+// runPartialOutput gets the output of this runner when its tasks failed.
+// Nothing is returned unless this runner is set to always run its output.
+//
+// NOTE:
+//  Failure to run the output task is only logged since the error of the
+// failed tasks is returned to the caller
+func (m *TaskGroupRunner) runPartialOutput(ctx context.Context, values map[string]interface{}) []byte {
+	if !m.alwaysRunOutput {
+		return nil
+	}
 
-	output, err = te.Output()
+	output, err := m.runOutput(ctx, values)
 	if err != nil {
-		// log with verbose details
-		glog.Errorf("failed to execute output task: runtask '%+v': template values in yaml '%s': template values '%+v'", m.outputTask, template.ToYaml(values), values)
+		m.logger().Error(err, "failed to get partial output")
+		return nil
 	}
-	return
+	return output
 }
 
-// Run will run all the defined tasks & will rollback in case of any error
+// Run will run all the defined tasks & will rollback in case of any error.
+// Tasks that are yet to be executed are not executed once the provided
+// context is done & the executed tasks are rolled back. Retries & watches of
+// the task being executed are aborted as well.
 //
 // NOTE: values is mutated (i.e. gets modified after each task execution) to
 // let the task execution result be made available to the next task before execution
 // of this next task
-func (m *TaskGroupRunner) Run(values map[string]interface{}) (output []byte, err error) {
-	err = m.runAllTasks(values)
-	if err == nil {
-		return m.runOutput(values)
+//
+// NOTE: output is returned along with the error if this runner is set to
+// always run its output
+//
+// NOTE: tasks, output, rollback & fallback are traced by the tracer set in
+// the provided context via WithTracer if any or else by the tracer set via
+// WithRunnerTracer
+func (m *TaskGroupRunner) Run(ctx context.Context, values map[string]interface{}) (output []byte, err error) {
+	result := m.runWithReport(ctx, values)
+	return result.Output, result.Err
+}
+
+// RunLegacy will run all the defined tasks & will rollback in case of any
+// error. This is same as Run without a context.
+//
+// TODO
+//  Deprecated: use Run with a context. This will be removed once all the
+// callers pass a context.
+func (m *TaskGroupRunner) RunLegacy(values map[string]interface{}) (output []byte, err error) {
+	return m.Run(context.Background(), values)
+}
+
+// RunWithReport will run all the defined tasks & will rollback in case of
+// any error. It returns the output as well as the details of the executed &
+// rolled back tasks.
+//
+// NOTE:
+//  values is mutated similar to Run
+func (m *TaskGroupRunner) RunWithReport(values map[string]interface{}) RunResult {
+	return m.runWithReport(context.Background(), values)
+}
+
+// runWithReport runs all the tasks & reports the outcome
+//
+// NOTE:
+//  A runner is run only once. An error is returned if this runner was
+// already run & was not reset since.
+func (m *TaskGroupRunner) runWithReport(ctx context.Context, values map[string]interface{}) (result RunResult) {
+	m.mutex.Lock()
+	ran := m.ran
+	m.ran = true
+	m.mutex.Unlock()
+	if ran {
+		result.Err = fmt.Errorf("failed to run: runner was already run: construct a new runner or invoke Reset before running it again")
+		return
+	}
+
+	started := time.Now()
+	result.Output, result.Err = m.run(ctx, values)
+	result.Duration = time.Since(started)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	result.ExecutedTasks = append(result.ExecutedTasks, m.executed...)
+	result.RolledBackTasks, result.LeakedObjects = m.rollbacks.report()
+	result.FellBack = m.fellBack
+	return
+}
+
+// RunReport will run all the defined tasks & will rollback in case of any
+// error. It returns a report of the executed tasks along with the output.
+//
+// NOTE:
+//  Report is returned even if there was an error
+//
+// NOTE:
+//  values is mutated similar to Run
+func (m *TaskGroupRunner) RunReport(values map[string]interface{}) (*GroupRunReport, error) {
+	result := m.RunWithReport(values)
+	return result.asGroupRunReport(), result.Err
+}
+
+// Reset clears the state of the previous run of this runner so that this
+// runner can be run again. The run tasks, output task, fallback templates &
+// options set against this runner are preserved.
+//
+// NOTE:
+//  A runner that is run again without being reset returns an error without
+// executing any of its tasks
+//
+// NOTE:
+//  This is safe only after a run has completed. Resetting a runner that is
+// being run results in undefined behaviour.
+func (m *TaskGroupRunner) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.allTaskIDs = nil
+	m.executed = nil
+	m.fellBack = false
+	m.objectsCreated = 0
+	m.completed = nil
+	m.resumed = nil
+	m.resumedRollbacks = nil
+	m.ran = false
+	m.lastValues = nil
+	m.rollbacks.reset()
+}
+
+// run will run all the defined tasks & will rollback in case of any error
+//
+// NOTE:
+//  RollbackError is returned if the rollback failed as well
+func (m *TaskGroupRunner) run(ctx context.Context, values map[string]interface{}) (output []byte, err error) {
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+
+	// strict validation verifies all of these as well once the results are
+	// seeded
+	if !m.strictValidation {
+		err = m.validate(nil, false)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to validate runner")
+		}
+	}
+
+	// input values are preserved so that the fallback templates start clean
+	// i.e. without the results of this runner's tasks
+	var inputs map[string]interface{}
+	if len(m.fallbackTemplates) != 0 {
+		inputs = util.DeepCopyMap(values)
+	}
+
+	m.applySeededResults(values)
+
+	// retained to merge the task results after the run
+	m.mutex.Lock()
+	m.lastValues = values
+	m.mutex.Unlock()
+
+	m.resume(values)
+	defer m.deleteCheckpoint()
+
+	// secrets are cached only for the duration of this run
+	m.secrets = newSecretCache(m.getSecretFetcher())
+	defer func() { m.secrets = nil }()
+
+	ctx = m.tracedContext(ctx)
+
+	if m.strictValidation {
+		err = m.Validate(values)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to validate runtasks")
+		}
 	}
 
-	glog.Warningf("%+v: failed to execute runtasks", err)
-	m.rollback()
+	var rollbackErr error
+	err = m.negotiateVersion()
+	if err != nil {
+		m.logger().Error(err, "failed to negotiate version")
+	} else {
+		err = m.runAllTasks(ctx, values)
+		if err == nil {
+			return m.runOutput(ctx, values)
+		}
+
+		m.logger().Error(err, "failed to execute runtasks")
+		if !m.isAbort(err) {
+			// there is no rollback in a best effort flow
+			return m.runPartialOutput(ctx, values), err
+		}
+		rollbackErr = m.rollback(ctx)
+	}
 
-	if template.IsVersionMismatch(err) && len(m.fallbackTemplate) != 0 {
-		newvalues := values
-		return m.fallback(newvalues)
+	if m.isFallback(err) {
+		m.fellBack = true
+		output, err = m.fallback(ctx, inputs)
+		if err == nil {
+			return
+		}
 	}
 
-	return nil, err
+	if rollbackErr != nil {
+		err = &RollbackError{Err: err, RollbackErr: rollbackErr}
+	}
+	return m.runPartialOutput(ctx, values), err
 }
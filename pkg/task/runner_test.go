@@ -17,9 +17,67 @@ limitations under the License.
 package task
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/template"
+	"github.com/openebs/maya/pkg/util"
+	menv "github.com/openebs/maya/types/v1"
+	"github.com/pkg/errors"
 )
 
+func init() {
+	// k8s client gets instantiated from the k8s master address without
+	// contacting the cluster. This lets the Command kind run tasks get executed
+	// in these unit tests since these tasks do not invoke any K8s API.
+	os.Setenv(string(menv.K8sMasterENVK), "http://127.0.0.1:0")
+}
+
+// setK8sMaster points the k8s clients of the tasks to the provided address.
+// The returned function restores the address that was set before.
+func setK8sMaster(address string) (restore func()) {
+	key := string(menv.K8sMasterENVK)
+	previous, found := os.LookupEnv(key)
+	os.Setenv(key, address)
+	return func() {
+		if !found {
+			os.Unsetenv(key)
+			return
+		}
+		os.Setenv(key, previous)
+	}
+}
+
+// fakeCommandRunTask returns a Command kind run task with the provided
+// identity & post run template
+func fakeCommandRunTask(id, post string) *v1alpha1.RunTask {
+	r := &v1alpha1.RunTask{}
+	r.Name = id
+	r.Spec.Meta = "id: " + id + "\nkind: Command\naction: put"
+	r.Spec.PostRun = post
+	return r
+}
+
+// fakeTemplateValues returns the template values that are typically set by
+// the cas template engine
+func fakeTemplateValues() map[string]interface{} {
+	return map[string]interface{}{
+		string(v1alpha1.TaskResultTLP): map[string]interface{}{},
+		string(v1alpha1.ListItemsTLP):  map[string]interface{}{},
+	}
+}
+
 // TODO
 func TestNewTaskGroupRunner(t *testing.T) {}
 
@@ -37,3 +95,2125 @@ func TestRunAllTasks(t *testing.T) {}
 
 // TODO
 func TestRun(t *testing.T) {}
+
+func TestRunWithTimeout(t *testing.T) {
+	tests := map[string]struct {
+		timeout time.Duration
+		isErr   bool
+	}{
+		"run with timeout - +ve test case - no timeout":      {timeout: 0},
+		"run with timeout - +ve test case - enough timeout":  {timeout: time.Minute},
+		"run with timeout - -ve test case - expired timeout": {timeout: time.Nanosecond, isErr: true},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner(WithTimeout(mock.timeout))
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+
+			// ensure the timeout if any has expired
+			time.Sleep(time.Millisecond)
+			_, err := r.Run(context.Background(), fakeTemplateValues())
+			if mock.isErr && err == nil {
+				t.Fatalf("failed to test run with timeout: expected 'error': actual 'no error'")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("failed to test run with timeout: expected 'no error': actual '%s'", err)
+			}
+		})
+	}
+}
+
+func TestRunWithTimeoutListItems(t *testing.T) {
+	tests := map[string]struct {
+		timeout  time.Duration
+		parallel bool
+	}{
+		"run with timeout list items - +ve test case - no timeout":          {timeout: 0},
+		"run with timeout list items - +ve test case - enough timeout":      {timeout: time.Minute},
+		"run with timeout list items - +ve test case - parallel no timeout": {timeout: 0, parallel: true},
+		"run with timeout list items - +ve test case - parallel timeout":    {timeout: time.Minute, parallel: true},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner(WithTimeout(mock.timeout))
+			t1 := fakeCommandRunTask("t1", `{{- "vol1" | saveAs "vols.ns1" .ListItems | noop -}}`)
+			t2 := fakeCommandRunTask("t2", `{{- "vol2" | saveAs "vols.ns2" .ListItems | noop -}}`)
+			if mock.parallel {
+				r.AddParallelRunTasks(t1, t2)
+			} else {
+				r.AddRunTasks([]*v1alpha1.RunTask{t1, t2})
+			}
+			r.AddOutputTask(&v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{Meta: "id: o1\nkind: Command\naction: output", Task: "items: {{ .ListItems.vols }}"}})
+
+			output, err := r.Run(context.Background(), fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test run with timeout list items: expected 'no error': actual '%s'", err)
+			}
+			if string(output) != "items: map[ns1:vol1 ns2:vol2]" {
+				t.Fatalf("failed to test run with timeout list items: expected 'items: map[ns1:vol1 ns2:vol2]': actual '%s'", output)
+			}
+		})
+	}
+}
+
+func TestTimedOutTaskIsRolledBack(t *testing.T) {
+	// api server that creates the service only after the task timed out
+	var deleted int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodPost:
+			time.Sleep(200 * time.Millisecond)
+			body, _ := ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		case http.MethodDelete:
+			atomic.AddInt32(&deleted, 1)
+			w.Write([]byte(`{"apiVersion":"v1","kind":"Status","status":"Success","code":200}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"apiVersion":"v1","kind":"Status","status":"Failure","reason":"NotFound","code":404}`))
+		}
+	}))
+	defer server.Close()
+	defer setK8sMaster(server.URL)()
+
+	runtask := &v1alpha1.RunTask{}
+	runtask.Name = "putsvc"
+	runtask.Spec.Meta = "id: putsvc\napiVersion: v1\nkind: Service\naction: put\nrunNamespace: default\ntimeout: 20ms"
+	runtask.Spec.Task = "apiVersion: v1\nkind: Service\nmetadata:\n  name: slow-svc"
+	runtask.Spec.PostRun = `{{- jsonpath .JsonResult "{.metadata.name}" | trim | saveAs "putsvc.objectName" .TaskResult | noop -}}`
+
+	r := NewTaskGroupRunner()
+	r.AddRunTask(runtask)
+	result := r.RunWithReport(fakeTemplateValues())
+	if errors.Cause(result.Err) != context.DeadlineExceeded {
+		t.Fatalf("failed to test timed out task is rolled back: expected 'deadline exceeded': actual '%v'", result.Err)
+	}
+	if len(result.ExecutedTasks) != 1 || !reflect.DeepEqual(result.ExecutedTasks[0].ObjectNames, []string{"slow-svc"}) {
+		t.Fatalf("failed to test timed out task is rolled back: expected object 'slow-svc': actual '%+v'", result.ExecutedTasks)
+	}
+	if len(result.RolledBackTasks) != 1 || atomic.LoadInt32(&deleted) != 1 {
+		t.Fatalf("failed to test timed out task is rolled back: expected 'slow-svc' to be deleted: actual '%+v'", result.RolledBackTasks)
+	}
+}
+
+func TestPostTaskRunFn(t *testing.T) {
+	tests := map[string]struct {
+		post        string
+		isErr       bool
+		expectedErr bool
+	}{
+		"post task run fn - +ve test case - successful task": {
+			post: `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+		},
+		"post task run fn - -ve test case - failed task": {
+			post:        `{{- fail "t1 failed" -}}`,
+			isErr:       true,
+			expectedErr: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			var (
+				invoked bool
+				result  map[string]interface{}
+			)
+			r := NewTaskGroupRunner(
+				WithPostTaskRunFn(func(taskResult map[string]interface{}) {
+					panic("post task run fn panics")
+				}),
+				WithPostTaskRunFn(func(taskResult map[string]interface{}) {
+					invoked = true
+					result = taskResult
+				}),
+			)
+			r.AddRunTask(fakeCommandRunTask("t1", mock.post))
+
+			values := fakeTemplateValues()
+			_, err := r.Run(context.Background(), values)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test post task run fn: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			if !invoked {
+				t.Fatalf("failed to test post task run fn: expected fn to be invoked: actual 'not invoked'")
+			}
+			if !mock.isErr && result[string(v1alpha1.ObjectNameTRTP)] != "t1-obj" {
+				t.Fatalf("failed to test post task run fn: expected task result 't1-obj': actual '%v'", result[string(v1alpha1.ObjectNameTRTP)])
+			}
+
+			actualErr := result[string(v1alpha1.TaskResultExecuteErrTRTP)]
+			if mock.expectedErr != (actualErr != nil) {
+				t.Fatalf("failed to test post task run fn: expected annotated error '%t': actual '%v'", mock.expectedErr, actualErr)
+			}
+			if util.GetNestedField(values, string(v1alpha1.TaskResultTLP), "t1", string(v1alpha1.TaskResultExecuteErrTRTP)) != nil {
+				t.Fatalf("failed to test post task run fn: expected template values not to be annotated")
+			}
+		})
+	}
+}
+
+func TestRunWithContext(t *testing.T) {
+	tests := map[string]struct {
+		cancel bool
+		isErr  bool
+	}{
+		"run with context - +ve test case - active context":    {cancel: false},
+		"run with context - -ve test case - cancelled context": {cancel: true, isErr: true},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if mock.cancel {
+				cancel()
+			}
+
+			r := NewTaskGroupRunner()
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+			r.AddRunTask(fakeCommandRunTask("t2", `{{- "t2-obj" | saveAs "t2.objectName" .TaskResult | noop -}}`))
+
+			values := fakeTemplateValues()
+			_, err := r.Run(ctx, values)
+			if !mock.isErr {
+				if err != nil {
+					t.Fatalf("failed to test run with context: expected 'no error': actual '%s'", err)
+				}
+				return
+			}
+
+			if errors.Cause(err) != context.Canceled {
+				t.Fatalf("failed to test run with context: expected '%s': actual '%v'", context.Canceled, err)
+			}
+			if len(r.rollbacks.planned) != 0 {
+				t.Fatalf("failed to test run with context: expected no task execution: actual rollbacks '%d'", len(r.rollbacks.planned))
+			}
+		})
+	}
+}
+
+func TestRunWithReport(t *testing.T) {
+	tests := map[string]struct {
+		posts              []string
+		isErr              bool
+		expectedExecuted   int
+		expectedRolledBack int
+	}{
+		"run with report - +ve test case - all tasks succeed": {
+			posts: []string{
+				`{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+				`{{- "t2-obj" | saveAs "t2.objectName" .TaskResult | noop -}}`,
+			},
+			expectedExecuted: 2,
+		},
+		"run with report - -ve test case - second task fails": {
+			posts: []string{
+				`{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+				`{{- fail "t2 failed" -}}`,
+				`{{- "t3-obj" | saveAs "t3.objectName" .TaskResult | noop -}}`,
+			},
+			isErr:              true,
+			expectedExecuted:   2,
+			expectedRolledBack: 1,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			for idx, post := range mock.posts {
+				r.AddRunTask(fakeCommandRunTask(fmt.Sprintf("t%d", idx+1), post))
+			}
+
+			result := r.RunWithReport(fakeTemplateValues())
+			if mock.isErr != (result.Err != nil) {
+				t.Fatalf("failed to test run with report: expected error '%t': actual '%v'", mock.isErr, result.Err)
+			}
+			if len(result.ExecutedTasks) != mock.expectedExecuted {
+				t.Fatalf("failed to test run with report: expected executed tasks '%d': actual '%d'", mock.expectedExecuted, len(result.ExecutedTasks))
+			}
+			if len(result.RolledBackTasks) != mock.expectedRolledBack {
+				t.Fatalf("failed to test run with report: expected rolled back tasks '%d': actual '%d'", mock.expectedRolledBack, len(result.RolledBackTasks))
+			}
+			last := result.ExecutedTasks[len(result.ExecutedTasks)-1]
+			if mock.isErr != (last.Err != nil) {
+				t.Fatalf("failed to test run with report: expected last task error '%t': actual '%v'", mock.isErr, last.Err)
+			}
+			if result.Duration <= 0 {
+				t.Fatalf("failed to test run with report: expected non zero duration")
+			}
+		})
+	}
+}
+
+func TestRunIf(t *testing.T) {
+	tests := map[string]struct {
+		created        string
+		expectedStatus TaskStatus
+	}{
+		"run if - +ve test case - predicate is true":  {created: "true", expectedStatus: TaskSucceeded},
+		"run if - +ve test case - predicate is false": {created: "false", expectedStatus: TaskSkipped},
+		"run if - +ve test case - predicate is zero":  {created: "0", expectedStatus: TaskSkipped},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`+
+				`{{- "`+mock.created+`" | saveAs "t1.created" .TaskResult | noop -}}`))
+			t2 := fakeCommandRunTask("t2", `{{- "t2-obj" | saveAs "t2.objectName" .TaskResult | noop -}}`)
+			t2.Spec.Meta = t2.Spec.Meta + "\nrunIf: {{ .TaskResult.t1.created }}"
+			r.AddRunTask(t2)
+
+			result := r.RunWithReport(fakeTemplateValues())
+			if result.Err != nil {
+				t.Fatalf("failed to test run if: expected 'no error': actual '%s'", result.Err)
+			}
+			if result.ExecutedTasks[1].Status != mock.expectedStatus {
+				t.Fatalf("failed to test run if: expected status '%s': actual '%s'", mock.expectedStatus, result.ExecutedTasks[1].Status)
+			}
+			if mock.expectedStatus == TaskSkipped && len(r.rollbacks.planned) != 1 {
+				t.Fatalf("failed to test run if: expected skipped task not to be planned for rollback: actual rollbacks '%d'", len(r.rollbacks.planned))
+			}
+		})
+	}
+}
+
+func TestRunReport(t *testing.T) {
+	tests := map[string]struct {
+		posts              []string
+		isErr              bool
+		expectedEntries    int
+		expectedRolledBack bool
+		expectedObjects    []string
+	}{
+		"run report - +ve test case - all tasks succeed": {
+			posts: []string{
+				`{{- "t1-obj1, t1-obj2" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+			},
+			expectedEntries: 1,
+			expectedObjects: []string{"t1-obj1", "t1-obj2"},
+		},
+		"run report - -ve test case - second task fails": {
+			posts: []string{
+				`{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+				`{{- fail "t2 failed" -}}`,
+			},
+			isErr:              true,
+			expectedEntries:    2,
+			expectedRolledBack: true,
+			expectedObjects:    []string{"t1-obj"},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			for idx, post := range mock.posts {
+				r.AddRunTask(fakeCommandRunTask(fmt.Sprintf("t%d", idx+1), post))
+			}
+
+			report, err := r.RunReport(fakeTemplateValues())
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test run report: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			if len(report.Tasks) != mock.expectedEntries {
+				t.Fatalf("failed to test run report: expected entries '%d': actual '%d'", mock.expectedEntries, len(report.Tasks))
+			}
+			if report.RolledBack != mock.expectedRolledBack {
+				t.Fatalf("failed to test run report: expected rolled back '%t': actual '%t'", mock.expectedRolledBack, report.RolledBack)
+			}
+			if !reflect.DeepEqual(report.Tasks[0].ObjectNames, mock.expectedObjects) {
+				t.Fatalf("failed to test run report: expected object names '%v': actual '%v'", mock.expectedObjects, report.Tasks[0].ObjectNames)
+			}
+			if mock.isErr && len(report.Tasks[1].Error) == 0 {
+				t.Fatalf("failed to test run report: expected error in failed task entry: actual 'no error'")
+			}
+		})
+	}
+}
+
+func TestRunReportRenderedTask(t *testing.T) {
+	RegisterTaskPlugin("echo", fakeEchoPlugin{})
+
+	tests := map[string]struct {
+		task         string
+		isErr        bool
+		expectedMeta string
+		expectedTask string
+	}{
+		"rendered task - +ve test case - task succeeds": {
+			task:         "owner: {{ .owner }}",
+			expectedMeta: "id: e1\napiVersion: v1\nkind: Plugin\naction: plugin/echo\nrunNamespace: ns-pvc-1",
+			expectedTask: "owner: pvc-1",
+		},
+		"rendered task - -ve test case - task fails": {
+			task:         "{{ if eq .owner \"pvc-1\" }}fail{{ end }}",
+			isErr:        true,
+			expectedMeta: "id: e1\napiVersion: v1\nkind: Plugin\naction: plugin/echo\nrunNamespace: ns-pvc-1",
+			expectedTask: "fail",
+		},
+		"rendered task - +ve test case - task without yaml": {
+			expectedMeta: "id: e1\napiVersion: v1\nkind: Plugin\naction: plugin/echo\nrunNamespace: ns-pvc-1",
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.AddRunTask(&v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{
+				Meta: "id: e1\napiVersion: v1\nkind: Plugin\naction: plugin/echo\nrunNamespace: ns-{{ .owner }}",
+				Task: mock.task,
+			}})
+			values := fakeTemplateValues()
+			values["owner"] = "pvc-1"
+
+			report, err := r.RunReport(values)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test rendered task: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			if len(report.Tasks) != 1 {
+				t.Fatalf("failed to test rendered task: expected entries '1': actual '%d'", len(report.Tasks))
+			}
+			if report.Tasks[0].RenderedMeta != mock.expectedMeta {
+				t.Fatalf("failed to test rendered task: expected meta '%s': actual '%s'", mock.expectedMeta, report.Tasks[0].RenderedMeta)
+			}
+			if report.Tasks[0].RenderedTask != mock.expectedTask {
+				t.Fatalf("failed to test rendered task: expected task '%s': actual '%s'", mock.expectedTask, report.Tasks[0].RenderedTask)
+			}
+		})
+	}
+}
+
+func TestTaskObjectNames(t *testing.T) {
+	tests := map[string]struct {
+		result   map[string]interface{}
+		expected []string
+	}{
+		"task object names - +ve test case - comma separated names": {
+			result:   map[string]interface{}{"objectName": "obj-1, obj-2"},
+			expected: []string{"obj-1", "obj-2"},
+		},
+		"task object names - +ve test case - list of names is preferred": {
+			result: map[string]interface{}{
+				"objectName":  "obj-1,obj-2",
+				"objectNames": []interface{}{"obj,1", "obj-2", nil, ""},
+			},
+			expected: []string{"obj,1", "obj-2"},
+		},
+		"task object names - +ve test case - list of strings": {
+			result:   map[string]interface{}{"objectNames": []string{"obj,1", " "}},
+			expected: []string{"obj,1"},
+		},
+		"task object names - +ve test case - no names": {
+			result: map[string]interface{}{},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			values := fakeTemplateValues()
+			util.SetNestedField(values, mock.result, string(v1alpha1.TaskResultTLP), "t1")
+
+			actual := taskObjectNames(values, "t1")
+			if !reflect.DeepEqual(actual, mock.expected) {
+				t.Fatalf("failed to test task object names: expected '%v': actual '%v'", mock.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPlanForRollbackObjectNames(t *testing.T) {
+	runtask := &v1alpha1.RunTask{}
+	runtask.Spec.Meta = "id: svc\napiVersion: v1\nkind: Service\naction: put\nrunNamespace: default"
+	te, err := newTaskExecutor(runtask, fakeTemplateValues())
+	if err != nil {
+		t.Fatalf("failed to test plan for rollback: expected 'no error': actual '%s'", err)
+	}
+
+	r := NewTaskGroupRunner()
+	err = r.planForRollback(te, []string{"svc,1", "svc-2"})
+	if err != nil {
+		t.Fatalf("failed to test plan for rollback: expected 'no error': actual '%s'", err)
+	}
+	var planned []string
+	for _, rte := range r.rollbacks.planned {
+		planned = append(planned, rte.getTaskObjectName())
+	}
+	if !reflect.DeepEqual(planned, []string{"svc,1", "svc-2"}) {
+		t.Fatalf("failed to test plan for rollback: expected rollbacks of '[svc,1 svc-2]': actual '%v'", planned)
+	}
+}
+
+func TestPlanForRollbackLeaked(t *testing.T) {
+	runtask := &v1alpha1.RunTask{}
+	runtask.Spec.Meta = "id: pvc\napiVersion: v1\nkind: PersistentVolumeClaim\naction: put\nrunNamespace: default"
+	te, err := newTaskExecutor(runtask, fakeTemplateValues())
+	if err != nil {
+		t.Fatalf("failed to test plan for rollback leaked: expected 'no error': actual '%s'", err)
+	}
+
+	r := NewTaskGroupRunner()
+	err = r.planForRollback(te, []string{"pvc-1", "pvc-2"})
+	if err == nil {
+		t.Fatalf("failed to test plan for rollback leaked: expected 'rollback error': actual 'no error'")
+	}
+	if len(r.rollbacks.planned) != 0 {
+		t.Fatalf("failed to test plan for rollback leaked: expected no rollbacks: actual '%d'", len(r.rollbacks.planned))
+	}
+	if !reflect.DeepEqual(r.rollbacks.leaked, []string{"pvc-1", "pvc-2"}) {
+		t.Fatalf("failed to test plan for rollback leaked: expected leaked objects '[pvc-1 pvc-2]': actual '%v'", r.rollbacks.leaked)
+	}
+}
+
+func TestInvalidRollbackIsNotExecuted(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	defer setK8sMaster(server.URL)()
+
+	pvc := &v1alpha1.RunTask{}
+	pvc.Name = "pvc"
+	pvc.Spec.Meta = "id: pvc\napiVersion: v1\nkind: PersistentVolumeClaim\naction: put\nrunNamespace: default"
+	pvc.Spec.Task = "apiVersion: v1\nkind: PersistentVolumeClaim\nmetadata:\n  name: pvc-1"
+
+	r := NewTaskGroupRunner()
+	r.AddRunTask(pvc)
+	result := r.RunWithReport(fakeTemplateValues())
+	if result.Err == nil {
+		t.Fatalf("failed to test invalid rollback: expected 'rollback error': actual 'no error'")
+	}
+	if !strings.Contains(result.Err.Error(), "invalid rollback of task 'pvc'") {
+		t.Fatalf("failed to test invalid rollback: expected invalid rollback error: actual '%s'", result.Err)
+	}
+	if atomic.LoadInt32(&requests) != 0 {
+		t.Fatalf("failed to test invalid rollback: expected task not to be executed: actual '%d' requests", requests)
+	}
+}
+
+func TestSetPreTaskRunFn(t *testing.T) {
+	tests := map[string]struct {
+		fn               PreTaskRunFn
+		isErr            bool
+		expectedOutput   string
+		expectedStatuses []TaskStatus
+	}{
+		"pre task run fn - +ve test case - values are augmented": {
+			fn: func(taskID string, values map[string]interface{}) error {
+				values["node"] = taskID + "-node"
+				return nil
+			},
+			expectedOutput:   "t2-node",
+			expectedStatuses: []TaskStatus{TaskSucceeded, TaskSucceeded},
+		},
+		"pre task run fn - -ve test case - error fails the run": {
+			fn: func(taskID string, values map[string]interface{}) error {
+				if taskID == "t1" {
+					return fmt.Errorf("lease not acquired")
+				}
+				return nil
+			},
+			isErr:            true,
+			expectedStatuses: []TaskStatus{TaskFailed},
+		},
+		"pre task run fn - -ve test case - panic fails the run": {
+			fn: func(taskID string, values map[string]interface{}) error {
+				panic("lease panicked")
+			},
+			isErr:            true,
+			expectedStatuses: []TaskStatus{TaskFailed},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.SetPreTaskRunFn(mock.fn)
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- .node | saveAs "t1.node" .TaskResult | noop -}}`))
+			r.AddRunTask(fakeCommandRunTask("t2", `{{- .node | saveAs "t2.node" .TaskResult | noop -}}`))
+			r.SetOutputTask(&v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{
+				Meta: "id: o1\nkind: Command\naction: output",
+				Task: `{{ nestedString .TaskResult "t2" "node" }}`,
+			}})
+
+			result := r.RunWithReport(fakeTemplateValues())
+			if mock.isErr != (result.Err != nil) {
+				t.Fatalf("failed to test pre task run fn: expected error '%t': actual '%v'", mock.isErr, result.Err)
+			}
+			if !mock.isErr && string(result.Output) != mock.expectedOutput {
+				t.Fatalf("failed to test pre task run fn: expected output '%s': actual '%s'", mock.expectedOutput, result.Output)
+			}
+			var statuses []TaskStatus
+			for _, task := range result.ExecutedTasks {
+				statuses = append(statuses, task.Status)
+			}
+			if !reflect.DeepEqual(statuses, mock.expectedStatuses) {
+				t.Fatalf("failed to test pre task run fn: expected statuses '%v': actual '%v'", mock.expectedStatuses, statuses)
+			}
+		})
+	}
+}
+
+func TestSetResultTransformer(t *testing.T) {
+	tests := map[string]struct {
+		fn               ResultTransformerFn
+		isErr            bool
+		expectedOutput   string
+		expectedStatuses []TaskStatus
+	}{
+		"result transformer - +ve test case - result is normalized": {
+			fn: func(taskID string, result map[string]interface{}) error {
+				result["node"] = strings.ToUpper(result["node"].(string))
+				return nil
+			},
+			expectedOutput:   "NODE-1",
+			expectedStatuses: []TaskStatus{TaskSucceeded, TaskSucceeded},
+		},
+		"result transformer - -ve test case - error fails the run": {
+			fn: func(taskID string, result map[string]interface{}) error {
+				if taskID == "t1" {
+					return fmt.Errorf("expected field 'uid' not found")
+				}
+				return nil
+			},
+			isErr:            true,
+			expectedStatuses: []TaskStatus{TaskFailed},
+		},
+		"result transformer - -ve test case - panic fails the run": {
+			fn: func(taskID string, result map[string]interface{}) error {
+				panic("transformer panicked")
+			},
+			isErr:            true,
+			expectedStatuses: []TaskStatus{TaskFailed},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.SetResultTransformer(mock.fn)
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "node-1" | saveAs "t1.node" .TaskResult | noop -}}`))
+			r.AddRunTask(fakeCommandRunTask("t2", `{{- nestedString .TaskResult "t1" "node" | saveAs "t2.node" .TaskResult | noop -}}`))
+			r.SetOutputTask(&v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{
+				Meta: "id: o1\nkind: Command\naction: output",
+				Task: `{{ nestedString .TaskResult "t2" "node" }}`,
+			}})
+
+			result := r.RunWithReport(fakeTemplateValues())
+			if mock.isErr != (result.Err != nil) {
+				t.Fatalf("failed to test result transformer: expected error '%t': actual '%v'", mock.isErr, result.Err)
+			}
+			if !mock.isErr && string(result.Output) != mock.expectedOutput {
+				t.Fatalf("failed to test result transformer: expected output '%s': actual '%s'", mock.expectedOutput, result.Output)
+			}
+			var statuses []TaskStatus
+			for _, task := range result.ExecutedTasks {
+				statuses = append(statuses, task.Status)
+			}
+			if !reflect.DeepEqual(statuses, mock.expectedStatuses) {
+				t.Fatalf("failed to test result transformer: expected statuses '%v': actual '%v'", mock.expectedStatuses, statuses)
+			}
+		})
+	}
+}
+
+func TestIgnoreErrors(t *testing.T) {
+	notFound := `{{- "" | notFoundErr "snapshot not found" | saveIf "t1.notFoundErr" .TaskResult | noop -}}`
+	tests := map[string]struct {
+		post             string
+		ignoreErrors     string
+		isErr            bool
+		expectedOutput   string
+		expectedStatuses []TaskStatus
+	}{
+		"ignore errors - +ve test case - matching class is ignored": {
+			post:             notFound,
+			ignoreErrors:     "\nignoreErrors:\n- NotFound",
+			expectedOutput:   "snapshot not found",
+			expectedStatuses: []TaskStatus{TaskSucceeded, TaskSucceeded},
+		},
+		"ignore errors - +ve test case - class is matched case insensitively": {
+			post:             notFound,
+			ignoreErrors:     "\nignoreErrors:\n- notfound",
+			expectedOutput:   "snapshot not found",
+			expectedStatuses: []TaskStatus{TaskSucceeded, TaskSucceeded},
+		},
+		"ignore errors - -ve test case - other class fails the run": {
+			post:             notFound,
+			ignoreErrors:     "\nignoreErrors:\n- Verify",
+			isErr:            true,
+			expectedStatuses: []TaskStatus{TaskFailed},
+		},
+		"ignore errors - -ve test case - no ignore errors fails the run": {
+			post:             notFound,
+			isErr:            true,
+			expectedStatuses: []TaskStatus{TaskFailed},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			t1 := fakeCommandRunTask("t1", mock.post)
+			t1.Spec.Meta = t1.Spec.Meta + mock.ignoreErrors
+
+			r := NewTaskGroupRunner()
+			r.AddRunTask(t1)
+			r.AddRunTask(fakeCommandRunTask("t2", ""))
+			r.SetOutputTask(&v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{
+				Meta: "id: o1\nkind: Command\naction: output",
+				Task: `{{ nestedString .TaskResult "t1" "ignoredErr" }}`,
+			}})
+
+			result := r.RunWithReport(fakeTemplateValues())
+			if mock.isErr != (result.Err != nil) {
+				t.Fatalf("failed to test ignore errors: expected error '%t': actual '%v'", mock.isErr, result.Err)
+			}
+			if !mock.isErr && string(result.Output) != mock.expectedOutput {
+				t.Fatalf("failed to test ignore errors: expected output '%s': actual '%s'", mock.expectedOutput, result.Output)
+			}
+			var statuses []TaskStatus
+			for _, task := range result.ExecutedTasks {
+				statuses = append(statuses, task.Status)
+			}
+			if !reflect.DeepEqual(statuses, mock.expectedStatuses) {
+				t.Fatalf("failed to test ignore errors: expected statuses '%v': actual '%v'", mock.expectedStatuses, statuses)
+			}
+			if !mock.isErr && !template.IsNotFound(result.ExecutedTasks[0].IgnoredErr) {
+				t.Fatalf("failed to test ignore errors: expected ignored not found error: actual '%v'", result.ExecutedTasks[0].IgnoredErr)
+			}
+		})
+	}
+}
+
+func TestAddRunTasks(t *testing.T) {
+	t1 := fakeCommandRunTask("t1", "")
+	noMeta := fakeCommandRunTask("t4", "")
+	noMeta.Spec.Meta = ""
+
+	tests := map[string]struct {
+		existing      []*v1alpha1.RunTask
+		runtasks      []*v1alpha1.RunTask
+		expectedErrs  int
+		errIndexes    []int
+		expectedTasks int
+	}{
+		"add run tasks - +ve test case - unique tasks": {
+			runtasks:      []*v1alpha1.RunTask{t1, fakeCommandRunTask("t2", "")},
+			expectedTasks: 2,
+		},
+		"add run tasks - +ve test case - same task instance twice": {
+			runtasks:      []*v1alpha1.RunTask{t1, t1},
+			expectedTasks: 1,
+		},
+		"add run tasks - -ve test case - all errors are collected": {
+			runtasks:      []*v1alpha1.RunTask{nil, noMeta, t1, fakeCommandRunTask("t1", "")},
+			expectedErrs:  3,
+			errIndexes:    []int{0, 1, 3},
+			expectedTasks: 0,
+		},
+		"add run tasks - -ve test case - task already present in runner": {
+			existing:      []*v1alpha1.RunTask{t1},
+			runtasks:      []*v1alpha1.RunTask{fakeCommandRunTask("t2", ""), fakeCommandRunTask("t1", "")},
+			expectedErrs:  1,
+			expectedTasks: 1,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			for _, rt := range mock.existing {
+				r.AddRunTask(rt)
+			}
+
+			errs := r.AddRunTasks(mock.runtasks)
+			if len(errs) != mock.expectedErrs {
+				t.Fatalf("failed to test add run tasks: expected errors '%d': actual '%v'", mock.expectedErrs, errs)
+			}
+			for i, idx := range mock.errIndexes {
+				if !strings.Contains(errs[i].Error(), fmt.Sprintf("index '%d'", idx)) {
+					t.Fatalf("failed to test add run tasks: expected error of index '%d': actual '%s'", idx, errs[i])
+				}
+			}
+			if len(r.allTasks) != mock.expectedTasks {
+				t.Fatalf("failed to test add run tasks: expected tasks '%d': actual '%d'", mock.expectedTasks, len(r.allTasks))
+			}
+		})
+	}
+}
+
+func TestReplaceAndRemoveRunTask(t *testing.T) {
+	tests := map[string]struct {
+		update         func(r *TaskGroupRunner) error
+		expectedTasks  []string
+		expectedGroups [][]int
+		isErr          bool
+	}{
+		"replace run task - +ve test case - task in parallel group": {
+			update:         func(r *TaskGroupRunner) error { return r.ReplaceRunTask("t2", fakeCommandRunTask("x", "")) },
+			expectedTasks:  []string{"t1", "x", "t3", "t4"},
+			expectedGroups: [][]int{{1, 2}},
+		},
+		"replace run task - -ve test case - task not found": {
+			update: func(r *TaskGroupRunner) error { return r.ReplaceRunTask("t9", fakeCommandRunTask("x", "")) },
+			isErr:  true,
+		},
+		"replace run task - -ve test case - duplicate name": {
+			update: func(r *TaskGroupRunner) error { return r.ReplaceRunTask("t2", fakeCommandRunTask("t4", "")) },
+			isErr:  true,
+		},
+		"replace run task - -ve test case - nil run task": {
+			update: func(r *TaskGroupRunner) error { return r.ReplaceRunTask("t2", nil) },
+			isErr:  true,
+		},
+		"remove run task - +ve test case - task in parallel group": {
+			update:         func(r *TaskGroupRunner) error { return r.RemoveRunTask("t2") },
+			expectedTasks:  []string{"t1", "t3", "t4"},
+			expectedGroups: [][]int{{1}},
+		},
+		"remove run task - +ve test case - task before parallel group": {
+			update:         func(r *TaskGroupRunner) error { return r.RemoveRunTask("t1") },
+			expectedTasks:  []string{"t2", "t3", "t4"},
+			expectedGroups: [][]int{{0, 1}},
+		},
+		"remove run task - -ve test case - task not found": {
+			update: func(r *TaskGroupRunner) error { return r.RemoveRunTask("t9") },
+			isErr:  true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.AddRunTask(fakeCommandRunTask("t1", ""))
+			r.AddParallelRunTasks(fakeCommandRunTask("t2", ""), fakeCommandRunTask("t3", ""))
+			r.AddRunTask(fakeCommandRunTask("t4", ""))
+
+			err := mock.update(r)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test replace & remove run task: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			if mock.isErr {
+				return
+			}
+
+			var names []string
+			for _, runtask := range r.allTasks {
+				names = append(names, runtask.Name)
+			}
+			if !reflect.DeepEqual(names, mock.expectedTasks) {
+				t.Fatalf("failed to test replace & remove run task: expected tasks '%v': actual '%v'", mock.expectedTasks, names)
+			}
+			if !reflect.DeepEqual(r.parallelGroups, mock.expectedGroups) {
+				t.Fatalf("failed to test replace & remove run task: expected groups '%v': actual '%v'", mock.expectedGroups, r.parallelGroups)
+			}
+		})
+	}
+}
+
+func TestMustAddRunTasks(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("failed to test must add run tasks: expected 'panic': actual 'no panic'")
+		}
+	}()
+
+	r := NewTaskGroupRunner()
+	r.MustAddRunTasks([]*v1alpha1.RunTask{nil})
+}
+
+func TestDisabledTaskIdentity(t *testing.T) {
+	t1 := fakeCommandRunTask("t1", "")
+	t1.Spec.Meta = t1.Spec.Meta + "\ndisable: true"
+
+	r := NewTaskGroupRunner()
+	r.AddRunTask(t1)
+	r.AddRunTask(fakeCommandRunTask("t1", ""))
+
+	result := r.RunWithReport(fakeTemplateValues())
+	if result.Err == nil {
+		t.Fatalf("failed to test disabled task identity: expected 'duplicate id error': actual 'no error'")
+	}
+	// duplicate is detected before any of the tasks is executed
+	if !strings.Contains(result.Err.Error(), "duplicate id 't1'") || len(result.ExecutedTasks) != 0 {
+		t.Fatalf("failed to test disabled task identity: expected duplicate id error before executing any task: actual '%s': executed '%+v'", result.Err, result.ExecutedTasks)
+	}
+}
+
+func TestFallbackValues(t *testing.T) {
+	var seen []map[string]interface{}
+	r := NewTaskGroupRunner()
+	r.fallbackFn = func(ctx context.Context, castemplate string, values map[string]interface{}) ([]byte, error) {
+		seen = append(seen, util.DeepCopyMap(values))
+		// mutate the values similar to the tasks of a fallback template
+		util.SetNestedField(values, castemplate+"-obj", string(v1alpha1.TaskResultTLP), castemplate, "objectName")
+		values["Volume"] = castemplate
+		return nil, fmt.Errorf("%s failed", castemplate)
+	}
+	r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}{{- true | versionMismatchErr "not supported" | saveIf "t1.versionMismatchErr" .TaskResult | noop -}}`))
+	r.SetFallback([]string{"cast-v2", "cast-legacy"})
+
+	values := fakeTemplateValues()
+	values["Volume"] = "vol-1"
+	r.Run(context.Background(), values)
+
+	if values["Volume"] != "vol-1" {
+		t.Fatalf("failed to test fallback values: expected caller's values to be intact: actual '%v'", values)
+	}
+	for _, castemplate := range []string{"cast-v2", "cast-legacy"} {
+		if util.GetNestedField(values, string(v1alpha1.TaskResultTLP), castemplate) != nil {
+			t.Fatalf("failed to test fallback values: expected no result of '%s' in caller's values: actual '%v'", castemplate, values)
+		}
+	}
+
+	expected := map[string]interface{}{
+		string(v1alpha1.TaskResultTLP): map[string]interface{}{},
+		string(v1alpha1.ListItemsTLP):  map[string]interface{}{},
+		"Volume":                       "vol-1",
+	}
+	if len(seen) != 2 {
+		t.Fatalf("failed to test fallback values: expected '2' fallbacks: actual '%d'", len(seen))
+	}
+	for idx, s := range seen {
+		if !reflect.DeepEqual(s, expected) {
+			t.Fatalf("failed to test fallback values: expected fallback '%d' to start with input values '%v': actual '%v'", idx, expected, s)
+		}
+	}
+}
+
+func TestFallbackChain(t *testing.T) {
+	tests := map[string]struct {
+		fallbacks      []string
+		failing        map[string]bool
+		expectedOutput string
+		expectedTried  []string
+		isErr          bool
+	}{
+		"fallback chain - +ve test case - first fallback succeeds": {
+			fallbacks:      []string{"cast-v2", "cast-legacy"},
+			expectedOutput: "cast-v2",
+			expectedTried:  []string{"cast-v2"},
+		},
+		"fallback chain - +ve test case - second fallback succeeds": {
+			fallbacks:      []string{"cast-v2", "cast-legacy"},
+			failing:        map[string]bool{"cast-v2": true},
+			expectedOutput: "cast-legacy",
+			expectedTried:  []string{"cast-v2", "cast-legacy"},
+		},
+		"fallback chain - -ve test case - all fallbacks fail": {
+			fallbacks:     []string{"cast-v2", "cast-legacy"},
+			failing:       map[string]bool{"cast-v2": true, "cast-legacy": true},
+			expectedTried: []string{"cast-v2", "cast-legacy"},
+			isErr:         true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			var tried []string
+			r := NewTaskGroupRunner()
+			r.fallbackFn = func(ctx context.Context, castemplate string, values map[string]interface{}) ([]byte, error) {
+				tried = append(tried, castemplate)
+				if mock.failing[castemplate] {
+					return nil, fmt.Errorf("%s failed", castemplate)
+				}
+				return []byte(castemplate), nil
+			}
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- true | versionMismatchErr "not supported" | saveIf "t1.versionMismatchErr" .TaskResult | noop -}}`))
+			// set fallback replaces the fallbacks added previously
+			r.AddFallback("ignored")
+			r.SetFallback(mock.fallbacks)
+
+			output, err := r.Run(context.Background(), fakeTemplateValues())
+			if mock.isErr && err == nil {
+				t.Fatalf("failed to test fallback chain: expected 'error': actual 'no error'")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("failed to test fallback chain: expected 'no error': actual '%s'", err)
+			}
+			if string(output) != mock.expectedOutput {
+				t.Fatalf("failed to test fallback chain: expected output '%s': actual '%s'", mock.expectedOutput, output)
+			}
+			if !reflect.DeepEqual(tried, mock.expectedTried) {
+				t.Fatalf("failed to test fallback chain: expected fallbacks '%v': actual '%v'", mock.expectedTried, tried)
+			}
+			if mock.isErr {
+				for _, f := range mock.fallbacks {
+					if !strings.Contains(err.Error(), f+" failed") {
+						t.Fatalf("failed to test fallback chain: expected error of '%s' to be aggregated: actual '%s'", f, err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSetFallbackPredicate(t *testing.T) {
+	versionMismatch := `{{- true | versionMismatchErr "not supported" | saveIf "t1.versionMismatchErr" .TaskResult | noop -}}`
+	crdNotFound := `{{- fail "crd not found" -}}`
+	isCRDNotFound := func(err error) bool {
+		return strings.Contains(err.Error(), "crd not found")
+	}
+
+	tests := map[string]struct {
+		post         string
+		predicate    func(err error) bool
+		isFallenBack bool
+	}{
+		"fallback predicate - +ve test case - default falls back on version mismatch": {
+			post:         versionMismatch,
+			isFallenBack: true,
+		},
+		"fallback predicate - +ve test case - default does not fall back on other errors": {
+			post: crdNotFound,
+		},
+		"fallback predicate - +ve test case - predicate falls back on matching error": {
+			post:         crdNotFound,
+			predicate:    isCRDNotFound,
+			isFallenBack: true,
+		},
+		"fallback predicate - +ve test case - predicate overrides version mismatch": {
+			post:      versionMismatch,
+			predicate: isCRDNotFound,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			var tried []string
+			r := NewTaskGroupRunner()
+			r.fallbackFn = func(ctx context.Context, castemplate string, values map[string]interface{}) ([]byte, error) {
+				tried = append(tried, castemplate)
+				return []byte(castemplate), nil
+			}
+			r.AddRunTask(fakeCommandRunTask("t1", mock.post))
+			r.SetFallback([]string{"cast-v2"})
+			r.SetFallbackPredicate(mock.predicate)
+
+			output, err := r.Run(context.Background(), fakeTemplateValues())
+			if mock.isFallenBack != (len(tried) != 0) {
+				t.Fatalf("failed to test fallback predicate: expected fallback '%t': actual fallbacks '%v'", mock.isFallenBack, tried)
+			}
+			if mock.isFallenBack && (err != nil || string(output) != "cast-v2") {
+				t.Fatalf("failed to test fallback predicate: expected output 'cast-v2': actual output '%s' & error '%v'", output, err)
+			}
+			if !mock.isFallenBack && err == nil {
+				t.Fatalf("failed to test fallback predicate: expected 'error': actual 'no error'")
+			}
+		})
+	}
+}
+
+func TestNegotiateVersion(t *testing.T) {
+	tests := map[string]struct {
+		version      string
+		minVersion   string
+		maxVersion   string
+		isFallenBack bool
+	}{
+		"negotiate version - +ve test case - no version":       {version: "", minVersion: "1.2.0", maxVersion: "2.0.0"},
+		"negotiate version - +ve test case - in range":         {version: "1.5.0", minVersion: "1.2.0", maxVersion: "2.0.0"},
+		"negotiate version - +ve test case - no range":         {version: "1.5.0"},
+		"negotiate version - +ve test case - below min":        {version: "1.1.0", minVersion: "1.2.0", maxVersion: "2.0.0", isFallenBack: true},
+		"negotiate version - +ve test case - above max":        {version: "2.0.1", minVersion: "1.2.0", maxVersion: "2.0.0", isFallenBack: true},
+		"negotiate version - +ve test case - only max bound":   {version: "0.7.0", maxVersion: "2.0.0"},
+		"negotiate version - +ve test case - equals min bound": {version: "1.2.0", minVersion: "1.2.0"},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.fallbackFn = func(ctx context.Context, castemplate string, values map[string]interface{}) ([]byte, error) {
+				return []byte(castemplate), nil
+			}
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+			r.SetFallback([]string{"cast-legacy"})
+			r.SetVersion(mock.version)
+			r.SetVersionRange(mock.minVersion, mock.maxVersion)
+
+			result := r.RunWithReport(fakeTemplateValues())
+			if result.Err != nil {
+				t.Fatalf("failed to test negotiate version: expected 'no error': actual '%s'", result.Err)
+			}
+			if result.FellBack != mock.isFallenBack {
+				t.Fatalf("failed to test negotiate version: expected fallback '%t': actual '%t'", mock.isFallenBack, result.FellBack)
+			}
+			if mock.isFallenBack && len(result.ExecutedTasks) != 0 {
+				t.Fatalf("failed to test negotiate version: expected no tasks to be executed: actual '%d'", len(result.ExecutedTasks))
+			}
+		})
+	}
+}
+
+func TestRollbackRetries(t *testing.T) {
+	tests := map[string]struct {
+		retries         int
+		cancel          bool
+		expectedRetries int
+	}{
+		"rollback retries - +ve test case - no retries":            {retries: 0, expectedRetries: 0},
+		"rollback retries - +ve test case - two retries":           {retries: 2, expectedRetries: 2},
+		"rollback retries - +ve test case - cancelled run context": {retries: 2, cancel: true, expectedRetries: 2},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			ok, err := newTaskExecutor(fakeCommandRunTask("cmd", ""), fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test rollback retries: expected 'no error': actual '%s'", err)
+			}
+
+			// deleting a service fails since there is no k8s cluster
+			svc := &v1alpha1.RunTask{}
+			svc.Name = "svc"
+			svc.Spec.Meta = "id: svc\napiVersion: v1\nkind: Service\naction: delete\nrunNamespace: default\nobjectName: svc-1"
+			failing, err := newTaskExecutor(svc, fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test rollback retries: expected 'no error': actual '%s'", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if mock.cancel {
+				// rollbacks are executed & retried even if the run is done
+				cancel()
+			}
+
+			r := NewTaskGroupRunner()
+			r.SetRollbackRetries(mock.retries, time.Millisecond)
+			r.rollbacks.planned = []*taskExecutor{failing, ok}
+			err = r.rollback(ctx)
+			if err == nil {
+				t.Fatalf("failed to test rollback retries: expected 'rollback error': actual 'no error'")
+			}
+
+			if len(r.rollbacks.rolledBack) != 2 {
+				t.Fatalf("failed to test rollback retries: expected '2' rollbacks: actual '%d'", len(r.rollbacks.rolledBack))
+			}
+			// rollbacks are executed in reverse order
+			if r.rollbacks.rolledBack[0].Identity != "cmd" || r.rollbacks.rolledBack[1].Identity != "svc" {
+				t.Fatalf("failed to test rollback retries: expected rollbacks in reverse order: actual '%+v'", r.rollbacks.rolledBack)
+			}
+			if r.rollbacks.rolledBack[1].Retries != mock.expectedRetries {
+				t.Fatalf("failed to test rollback retries: expected retries '%d': actual '%d'", mock.expectedRetries, r.rollbacks.rolledBack[1].Retries)
+			}
+			if !reflect.DeepEqual(r.rollbacks.leaked, []string{"svc-1"}) {
+				t.Fatalf("failed to test rollback retries: expected leaked objects '[svc-1]': actual '%v'", r.rollbacks.leaked)
+			}
+		})
+	}
+}
+
+func TestRollbackError(t *testing.T) {
+	// deleting a service fails since there is no k8s cluster
+	svc := &v1alpha1.RunTask{}
+	svc.Name = "svc"
+	svc.Spec.Meta = "id: svc\napiVersion: v1\nkind: Service\naction: delete\nrunNamespace: default\nobjectName: svc-1"
+	failing, err := newTaskExecutor(svc, fakeTemplateValues())
+	if err != nil {
+		t.Fatalf("failed to test rollback error: expected 'no error': actual '%s'", err)
+	}
+
+	r := NewTaskGroupRunner()
+	r.AddRunTask(fakeCommandRunTask("t1", `{{- fail "t1 failed" -}}`))
+	r.rollbacks.planned = []*taskExecutor{failing}
+
+	_, err = r.Run(context.Background(), fakeTemplateValues())
+	rbErr, ok := err.(*RollbackError)
+	if !ok {
+		t.Fatalf("failed to test rollback error: expected 'rollback error': actual '%#v'", err)
+	}
+	if rbErr.RollbackErr == nil || !strings.Contains(rbErr.RollbackErr.Error(), "svc") {
+		t.Fatalf("failed to test rollback error: expected failed rollback of 'svc': actual '%v'", rbErr.RollbackErr)
+	}
+	if !strings.Contains(errors.Cause(err).Error(), "t1 failed") {
+		t.Fatalf("failed to test rollback error: expected cause 't1 failed': actual '%s'", errors.Cause(err))
+	}
+}
+
+func TestStopOnRollbackFailure(t *testing.T) {
+	tests := map[string]struct {
+		stop               bool
+		expectedRolledBack int
+		expectedLeaked     []string
+	}{
+		"stop on rollback failure - +ve test case - continue on failure": {
+			expectedRolledBack: 3,
+			expectedLeaked:     []string{"svc-1"},
+		},
+		"stop on rollback failure - +ve test case - stop on failure": {
+			stop:               true,
+			expectedRolledBack: 2,
+			expectedLeaked:     []string{"svc-1", "c2-obj"},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			c1, err := newTaskExecutor(fakeCommandRunTask("c1", ""), fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test stop on rollback failure: expected 'no error': actual '%s'", err)
+			}
+			c2 := fakeCommandRunTask("c2", "")
+			c2.Spec.Meta = c2.Spec.Meta + "\nobjectName: c2-obj"
+			c2e, err := newTaskExecutor(c2, fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test stop on rollback failure: expected 'no error': actual '%s'", err)
+			}
+
+			// deleting a service fails since there is no k8s cluster
+			svc := &v1alpha1.RunTask{}
+			svc.Name = "svc"
+			svc.Spec.Meta = "id: svc\napiVersion: v1\nkind: Service\naction: delete\nrunNamespace: default\nobjectName: svc-1"
+			failing, err := newTaskExecutor(svc, fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test stop on rollback failure: expected 'no error': actual '%s'", err)
+			}
+
+			r := NewTaskGroupRunner()
+			r.SetStopOnRollbackFailure(mock.stop)
+			// rollbacks are executed in reverse order i.e. c1, svc & then c2
+			r.rollbacks.planned = []*taskExecutor{c2e, failing, c1}
+			err = r.rollback(context.Background())
+			if err == nil {
+				t.Fatalf("failed to test stop on rollback failure: expected 'rollback error': actual 'no error'")
+			}
+
+			if len(r.rollbacks.rolledBack) != mock.expectedRolledBack {
+				t.Fatalf("failed to test stop on rollback failure: expected '%d' rollbacks: actual '%+v'", mock.expectedRolledBack, r.rollbacks.rolledBack)
+			}
+			if !reflect.DeepEqual(r.rollbacks.leaked, mock.expectedLeaked) {
+				t.Fatalf("failed to test stop on rollback failure: expected leaked objects '%v': actual '%v'", mock.expectedLeaked, r.rollbacks.leaked)
+			}
+
+			halted, ok := AsRollbackHaltedError(err)
+			if ok != mock.stop {
+				t.Fatalf("failed to test stop on rollback failure: expected halted error '%t': actual '%#v'", mock.stop, err)
+			}
+			if !mock.stop {
+				return
+			}
+			if halted.FailedTask != "svc" || !reflect.DeepEqual(halted.RolledBack, []string{"c1"}) || !reflect.DeepEqual(halted.Pending, []string{"c2"}) {
+				t.Fatalf("failed to test stop on rollback failure: expected halt at 'svc' after 'c1' with 'c2' pending: actual '%+v'", halted)
+			}
+		})
+	}
+}
+
+func TestRollbackPriority(t *testing.T) {
+	t1 := fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`)
+	t2 := fakeCommandRunTask("t2", `{{- "t2-obj" | saveAs "t2.objectName" .TaskResult | noop -}}`)
+	// t1 is rolled back before t2 in spite of being executed first
+	t1.Spec.Meta = t1.Spec.Meta + "\nrollbackPriority: 5"
+
+	r := NewTaskGroupRunner()
+	r.AddRunTasks([]*v1alpha1.RunTask{t1, t2, fakeCommandRunTask("t3", `{{- fail "t3 failed" -}}`)})
+	result := r.RunWithReport(fakeTemplateValues())
+	if result.Err == nil {
+		t.Fatalf("failed to test rollback priority: expected 'error': actual 'no error'")
+	}
+
+	var order []string
+	for _, rb := range result.RolledBackTasks {
+		order = append(order, rb.Identity)
+	}
+	if len(order) != 2 || !strings.HasPrefix(order[0], "t1") || !strings.HasPrefix(order[1], "t2") {
+		t.Fatalf("failed to test rollback priority: expected rollback of 't1' before 't2': actual '%v'", order)
+	}
+}
+
+func TestRollbackOnly(t *testing.T) {
+	tests := map[string]struct {
+		ids                []string
+		expectedRolledBack []string
+		expectedRemaining  int
+	}{
+		"rollback only - +ve test case - selected tasks": {
+			ids:                []string{"t1", "t3"},
+			expectedRolledBack: []string{"t3", "t1"},
+			expectedRemaining:  1,
+		},
+		"rollback only - +ve test case - unknown task": {
+			ids:               []string{"t9"},
+			expectedRemaining: 3,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.SetErrorPolicy(ContinueOnError)
+			r.AddRunTasks([]*v1alpha1.RunTask{
+				fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`),
+				fakeCommandRunTask("t2", `{{- "t2-obj" | saveAs "t2.objectName" .TaskResult | noop -}}`),
+				fakeCommandRunTask("t3", `{{- "t3-obj" | saveAs "t3.objectName" .TaskResult | noop -}}`),
+				fakeCommandRunTask("t4", `{{- fail "t4 failed" -}}`),
+			})
+
+			// executed tasks are not rolled back with continue on error policy
+			_, err := r.Run(context.Background(), fakeTemplateValues())
+			if err == nil || len(r.rollbacks.rolledBack) != 0 {
+				t.Fatalf("failed to test rollback only: expected 'error' without rollback: actual error '%v' rollbacks '%d'", err, len(r.rollbacks.rolledBack))
+			}
+
+			err = r.RollbackOnly(mock.ids...)
+			if err != nil {
+				t.Fatalf("failed to test rollback only: expected 'no error': actual '%s'", err)
+			}
+
+			var rolledBack []string
+			for _, rb := range r.rollbacks.rolledBack {
+				rolledBack = append(rolledBack, rb.Identity)
+			}
+			if !reflect.DeepEqual(rolledBack, mock.expectedRolledBack) {
+				t.Fatalf("failed to test rollback only: expected rollbacks '%v': actual '%v'", mock.expectedRolledBack, rolledBack)
+			}
+			if len(r.rollbacks.planned) != mock.expectedRemaining {
+				t.Fatalf("failed to test rollback only: expected '%d' remaining rollbacks: actual '%d'", mock.expectedRemaining, len(r.rollbacks.planned))
+			}
+		})
+	}
+}
+
+func TestRollbackNotFound(t *testing.T) {
+	// deleting a service that is already gone results in a not found error
+	server := fakeServiceAPIServer()
+	defer server.Close()
+
+	defer setK8sMaster(server.URL)()
+
+	svc := &v1alpha1.RunTask{}
+	svc.Name = "svc"
+	svc.Spec.Meta = "id: svc\napiVersion: v1\nkind: Service\naction: delete\nrunNamespace: default\nobjectName: missing-svc"
+	gone, err := newTaskExecutor(svc, fakeTemplateValues())
+	if err != nil {
+		t.Fatalf("failed to test rollback not found: expected 'no error': actual '%s'", err)
+	}
+
+	r := NewTaskGroupRunner()
+	r.SetRollbackRetries(2, time.Millisecond)
+	r.rollbacks.planned = []*taskExecutor{gone}
+	err = r.rollback(context.Background())
+	if err != nil {
+		t.Fatalf("failed to test rollback not found: expected 'no error': actual '%s'", err)
+	}
+
+	if len(r.rollbacks.rolledBack) != 1 || r.rollbacks.rolledBack[0].Status != TaskSucceeded || r.rollbacks.rolledBack[0].Retries != 0 {
+		t.Fatalf("failed to test rollback not found: expected '1' succeeded rollback without retries: actual '%+v'", r.rollbacks.rolledBack)
+	}
+	if len(r.rollbacks.leaked) != 0 {
+		t.Fatalf("failed to test rollback not found: expected no leaked objects: actual '%v'", r.rollbacks.leaked)
+	}
+}
+
+func TestRollbackDeadline(t *testing.T) {
+	tests := map[string]struct {
+		deadline  time.Duration
+		delay     time.Duration
+		isTimeout bool
+	}{
+		"rollback deadline - +ve test case - no deadline": {
+			delay: 50 * time.Millisecond,
+		},
+		"rollback deadline - +ve test case - within deadline": {
+			deadline: time.Second,
+		},
+		"rollback deadline - -ve test case - deadline exceeded": {
+			deadline:  20 * time.Millisecond,
+			delay:     time.Second,
+			isTimeout: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			// storage controller that is slow to respond
+			release := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-time.After(mock.delay):
+				case <-release:
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"apiVersion":"v1","kind":"Status","status":"Failure","reason":"NotFound","code":404}`))
+			}))
+			defer server.Close()
+			defer close(release)
+
+			defer setK8sMaster(server.URL)()
+
+			svc := &v1alpha1.RunTask{}
+			svc.Name = "svc"
+			svc.Spec.Meta = "id: svc\napiVersion: v1\nkind: Service\naction: delete\nrunNamespace: default\nobjectName: slow-svc"
+			slow, err := newTaskExecutor(svc, fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test rollback deadline: expected 'no error': actual '%s'", err)
+			}
+
+			r := NewTaskGroupRunner()
+			r.SetRollbackDeadline(mock.deadline)
+			r.rollbacks.planned = []*taskExecutor{slow}
+
+			started := time.Now()
+			err = r.rollback(context.Background())
+			if mock.isTimeout != IsRollbackTimeout(err) {
+				t.Fatalf("failed to test rollback deadline: expected timeout '%t': actual '%v'", mock.isTimeout, err)
+			}
+			if mock.isTimeout && time.Since(started) >= mock.delay {
+				t.Fatalf("failed to test rollback deadline: expected to return before the rollback completes")
+			}
+			if mock.isTimeout && !IsRollbackTimeout(&RollbackError{Err: errors.New("t1 failed"), RollbackErr: err}) {
+				t.Fatalf("failed to test rollback deadline: expected rollback error to be a rollback timeout")
+			}
+		})
+	}
+}
+
+func TestRollbackDeadlineInBackground(t *testing.T) {
+	tests := map[string]struct {
+		reset              bool
+		expectedRolledBack int
+	}{
+		"rollback deadline in background - +ve test case - outcome is published": {
+			expectedRolledBack: 1,
+		},
+		"rollback deadline in background - +ve test case - outcome is discarded after reset": {
+			reset: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			// storage controller that responds only once released
+			release := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-release
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"apiVersion":"v1","kind":"Status","status":"Failure","reason":"NotFound","code":404}`))
+			}))
+			defer server.Close()
+
+			defer setK8sMaster(server.URL)()
+
+			svc := &v1alpha1.RunTask{}
+			svc.Name = "svc"
+			svc.Spec.Meta = "id: svc\napiVersion: v1\nkind: Service\naction: delete\nrunNamespace: default\nobjectName: slow-svc"
+			slow, err := newTaskExecutor(svc, fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test rollback deadline in background: expected 'no error': actual '%s'", err)
+			}
+
+			r := NewTaskGroupRunner()
+			r.SetRollbackDeadline(20 * time.Millisecond)
+			r.AddRunTask(fakeCommandRunTask("t1", ""))
+			r.rollbacks.planned = []*taskExecutor{slow}
+
+			err = r.rollback(context.Background())
+			if !IsRollbackTimeout(err) {
+				t.Fatalf("failed to test rollback deadline in background: expected rollback timeout: actual '%v'", err)
+			}
+
+			// runner is inspected & run again while the rollback continues
+			// in the background
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result := r.RunWithReport(fakeTemplateValues())
+				if mock.reset {
+					r.Reset()
+					result = r.RunWithReport(fakeTemplateValues())
+				}
+				if result.Err != nil {
+					t.Errorf("failed to test rollback deadline in background: expected 'no error': actual '%s'", result.Err)
+				}
+			}()
+			close(release)
+			wg.Wait()
+			r.rollbacks.background.Wait()
+
+			if len(r.rollbacks.rolledBack) != mock.expectedRolledBack {
+				t.Fatalf("failed to test rollback deadline in background: expected '%d' rolled back tasks: actual '%+v'", mock.expectedRolledBack, r.rollbacks.rolledBack)
+			}
+		})
+	}
+}
+
+func TestAlwaysRunOutput(t *testing.T) {
+	tests := map[string]struct {
+		always         bool
+		policy         ErrorPolicy
+		expectedOutput string
+	}{
+		"always run output - +ve test case - output is not run": {
+			expectedOutput: "",
+		},
+		"always run output - +ve test case - output after rollback": {
+			always:         true,
+			expectedOutput: "t1=t1-obj t2=",
+		},
+		"always run output - +ve test case - output on continue on error": {
+			always:         true,
+			policy:         ContinueOnError,
+			expectedOutput: "t1=t1-obj t2=",
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+			r.AddRunTask(fakeCommandRunTask("t2", `{{- fail "t2 failed" -}}`))
+			r.SetOutputTask(&v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{
+				Meta: "id: out\nkind: Command\naction: output",
+				Task: `t1={{ nestedString .TaskResult "t1" "objectName" }} t2={{ nestedString .TaskResult "t2" "objectName" }}`,
+			}})
+			r.SetErrorPolicy(mock.policy)
+			r.SetAlwaysRunOutput(mock.always)
+
+			output, err := r.Run(context.Background(), fakeTemplateValues())
+			if err == nil {
+				t.Fatalf("failed to test always run output: expected 'error': actual 'no error'")
+			}
+			if string(output) != mock.expectedOutput {
+				t.Fatalf("failed to test always run output: expected output '%s': actual '%s'", mock.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	tests := map[string]struct {
+		reset bool
+		isErr bool
+	}{
+		"reset - +ve test case - rerun after reset":   {reset: true},
+		"reset - -ve test case - rerun without reset": {isErr: true},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.SetConcurrency(2)
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+			r.AddParallelRunTasks(
+				fakeCommandRunTask("t2", `{{- "t2-obj" | saveAs "t2.objectName" .TaskResult | noop -}}`),
+				fakeCommandRunTask("t3", `{{- "t3-obj" | saveAs "t3.objectName" .TaskResult | noop -}}`),
+			)
+
+			_, err := r.Run(context.Background(), fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test reset: expected 'no error': actual '%s'", err)
+			}
+			if mock.reset {
+				r.Reset()
+			}
+
+			// runner can not be run again if not reset
+			result := r.RunWithReport(fakeTemplateValues())
+			if mock.isErr != (result.Err != nil) {
+				t.Fatalf("failed to test reset: expected error '%t': actual '%v'", mock.isErr, result.Err)
+			}
+			if mock.isErr && (!strings.Contains(result.Err.Error(), "runner was already run") || len(result.ExecutedTasks) != 0) {
+				t.Fatalf("failed to test reset: expected already run error without executed tasks: actual '%v': executed '%d'", result.Err, len(result.ExecutedTasks))
+			}
+			if mock.reset && len(result.ExecutedTasks) != 3 {
+				t.Fatalf("failed to test reset: expected '3' executed tasks: actual '%d'", len(result.ExecutedTasks))
+			}
+		})
+	}
+}
+
+func TestSetMaxObjectsCreated(t *testing.T) {
+	tests := map[string]struct {
+		max              int
+		policy           ErrorPolicy
+		expectedExecuted int
+		isErr            bool
+	}{
+		"max objects created - +ve test case - no limit": {
+			expectedExecuted: 3,
+		},
+		"max objects created - +ve test case - within limit": {
+			max:              3,
+			expectedExecuted: 3,
+		},
+		"max objects created - -ve test case - limit exceeded": {
+			max:              1,
+			expectedExecuted: 2,
+			isErr:            true,
+		},
+		"max objects created - -ve test case - limit exceeded on continue on error": {
+			max:              1,
+			policy:           ContinueOnError,
+			expectedExecuted: 2,
+			isErr:            true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.SetMaxObjectsCreated(mock.max)
+			r.SetErrorPolicy(mock.policy)
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+			r.AddRunTask(fakeCommandRunTask("t2", `{{- "t2-obj" | saveAs "t2.objectName" .TaskResult | noop -}}`))
+			r.AddRunTask(fakeCommandRunTask("t3", `{{- "t3-obj" | saveAs "t3.objectName" .TaskResult | noop -}}`))
+
+			result := r.RunWithReport(fakeTemplateValues())
+			if mock.isErr != IsMaxObjectsCreated(result.Err) {
+				t.Fatalf("failed to test max objects created: expected limit exceeded '%t': actual '%v'", mock.isErr, result.Err)
+			}
+			if len(result.ExecutedTasks) != mock.expectedExecuted {
+				t.Fatalf("failed to test max objects created: expected '%d' executed tasks: actual '%d'", mock.expectedExecuted, len(result.ExecutedTasks))
+			}
+			// created objects are rolled back once the limit is exceeded
+			if mock.isErr && len(result.RolledBackTasks) != mock.expectedExecuted {
+				t.Fatalf("failed to test max objects created: expected '%d' rolled back tasks: actual '%d'", mock.expectedExecuted, len(result.RolledBackTasks))
+			}
+		})
+	}
+}
+
+func TestSeedTaskResult(t *testing.T) {
+	tests := map[string]struct {
+		task     *v1alpha1.RunTask
+		expected string
+		isErr    bool
+	}{
+		"seed task result - +ve test case - seeded result is rendered": {
+			task:     fakeCommandRunTask("t1", `{{- .TaskResult.upstream.objectName | saveAs "t1.objectName" .TaskResult | noop -}}`),
+			expected: "seeded-obj",
+		},
+		"seed task result - -ve test case - seeded id is reserved": {
+			task:  fakeCommandRunTask("Upstream", `{{- "t1-obj" | saveAs "Upstream.objectName" .TaskResult | noop -}}`),
+			isErr: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.SeedTaskResult("upstream", map[string]interface{}{"objectName": "seeded-obj"})
+			r.AddRunTask(mock.task)
+
+			values := fakeTemplateValues()
+			if err := r.Validate(values); mock.isErr != (err != nil) {
+				t.Fatalf("failed to test seed task result: expected validate error '%t': actual '%v'", mock.isErr, err)
+			}
+			if _, err := r.DryRun(values); mock.isErr != (err != nil) {
+				t.Fatalf("failed to test seed task result: expected dry run error '%t': actual '%v'", mock.isErr, err)
+			}
+
+			_, err := r.Run(context.Background(), values)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test seed task result: expected run error '%t': actual '%v'", mock.isErr, err)
+			}
+			if mock.isErr {
+				return
+			}
+			actual := util.GetNestedString(values, string(v1alpha1.TaskResultTLP), "t1", string(v1alpha1.ObjectNameTRTP))
+			if actual != mock.expected {
+				t.Fatalf("failed to test seed task result: expected '%s': actual '%s'", mock.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAddOutputTask(t *testing.T) {
+	fakeOutputTask := func(name, task string) *v1alpha1.RunTask {
+		r := &v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{Meta: "id: " + name + "\nkind: Command\naction: output", Task: task}}
+		r.Name = name
+		return r
+	}
+
+	tests := map[string]struct {
+		outputs        []*v1alpha1.RunTask
+		separator      string
+		expectedOutput string
+		isErr          bool
+	}{
+		"add output task - +ve test case - single output": {
+			outputs:        []*v1alpha1.RunTask{fakeOutputTask("o1", "out-1")},
+			expectedOutput: "out-1",
+		},
+		"add output task - +ve test case - default separator": {
+			outputs:        []*v1alpha1.RunTask{fakeOutputTask("o1", "out-1"), fakeOutputTask("o2", `{{ nestedString .TaskResult "t1" "objectName" }}`)},
+			expectedOutput: "out-1\n---\nt1-obj",
+		},
+		"add output task - +ve test case - custom separator": {
+			outputs:        []*v1alpha1.RunTask{fakeOutputTask("o1", "out-1"), fakeOutputTask("o2", "out-2")},
+			separator:      ",",
+			expectedOutput: "out-1,out-2",
+		},
+		"add output task - -ve test case - nil task specs": {
+			outputs: []*v1alpha1.RunTask{fakeOutputTask("o1", "")},
+			isErr:   true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+			r.SetOutputSeparator(mock.separator)
+			for _, o := range mock.outputs {
+				err := r.AddOutputTask(o)
+				if mock.isErr != (err != nil) {
+					t.Fatalf("failed to test add output task: expected error '%t': actual '%v'", mock.isErr, err)
+				}
+			}
+			if mock.isErr {
+				return
+			}
+
+			output, err := r.Run(context.Background(), fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test add output task: expected 'no error': actual '%s'", err)
+			}
+			if string(output) != mock.expectedOutput {
+				t.Fatalf("failed to test add output task: expected output '%s': actual '%s'", mock.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestOutputFormat(t *testing.T) {
+	tests := map[string]struct {
+		format         string
+		task           string
+		expectedOutput string
+		isErr          bool
+	}{
+		"output format - +ve test case - as is": {
+			task:           "name: t1-obj",
+			expectedOutput: "name: t1-obj",
+		},
+		"output format - +ve test case - yaml to json": {
+			format:         "json",
+			task:           `name: {{ nestedString .TaskResult "t1" "objectName" }}`,
+			expectedOutput: `{"name":"t1-obj"}`,
+		},
+		"output format - +ve test case - json to yaml": {
+			format:         "yaml",
+			task:           `{"name":"t1-obj"}`,
+			expectedOutput: "name: t1-obj\n",
+		},
+		"output format - -ve test case - invalid yaml": {
+			format: "json",
+			task:   "name: [t1-obj",
+			isErr:  true,
+		},
+		"output format - -ve test case - unsupported format": {
+			format: "xml",
+			task:   "name: t1-obj",
+			isErr:  true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := &v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{Meta: "id: o1\nkind: Command\naction: output", Task: mock.task}}
+			o.Name = "o1"
+			if len(mock.format) != 0 {
+				o.Spec.Meta = o.Spec.Meta + "\noutputFormat: " + mock.format
+			}
+
+			r := NewTaskGroupRunner()
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+			r.SetOutputTask(o)
+
+			output, err := r.Run(context.Background(), fakeTemplateValues())
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test output format: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			if !mock.isErr && string(output) != mock.expectedOutput {
+				t.Fatalf("failed to test output format: expected output '%s': actual '%s'", mock.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestSkipGroups(t *testing.T) {
+	fakeGroupRunTask := func(id, group string) *v1alpha1.RunTask {
+		r := fakeCommandRunTask(id, `{{- "`+id+`-obj" | saveAs "`+id+`.objectName" .TaskResult | noop -}}`)
+		r.Spec.Meta = r.Spec.Meta + "\ngroupLabel: " + group
+		return r
+	}
+
+	tests := map[string]struct {
+		skipped           []string
+		expectedStatuses  []TaskStatus
+		expectedRollbacks int
+	}{
+		"skip groups - +ve test case - no skipped groups": {
+			expectedStatuses:  []TaskStatus{TaskSucceeded, TaskSucceeded, TaskSucceeded},
+			expectedRollbacks: 3,
+		},
+		"skip groups - +ve test case - skip cstor group": {
+			skipped:           []string{"cstor"},
+			expectedStatuses:  []TaskStatus{TaskSucceeded, TaskSkipped, TaskSucceeded},
+			expectedRollbacks: 2,
+		},
+		"skip groups - +ve test case - skip all groups": {
+			skipped:          []string{"jiva", "cstor"},
+			expectedStatuses: []TaskStatus{TaskSkipped, TaskSkipped, TaskSkipped},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.AddRunTasks([]*v1alpha1.RunTask{
+				fakeGroupRunTask("t1", "jiva"),
+				fakeGroupRunTask("t2", "cstor"),
+				fakeGroupRunTask("t3", "jiva"),
+			})
+			r.SkipGroups(mock.skipped...)
+
+			result := r.RunWithReport(fakeTemplateValues())
+			if result.Err != nil {
+				t.Fatalf("failed to test skip groups: expected 'no error': actual '%s'", result.Err)
+			}
+
+			var statuses []TaskStatus
+			for _, task := range result.ExecutedTasks {
+				statuses = append(statuses, task.Status)
+			}
+			if !reflect.DeepEqual(statuses, mock.expectedStatuses) {
+				t.Fatalf("failed to test skip groups: expected statuses '%v': actual '%v'", mock.expectedStatuses, statuses)
+			}
+			if len(r.rollbacks.planned) != mock.expectedRollbacks {
+				t.Fatalf("failed to test skip groups: expected '%d' rollbacks: actual '%d'", mock.expectedRollbacks, len(r.rollbacks.planned))
+			}
+		})
+	}
+}
+
+func TestRedactTemplateValues(t *testing.T) {
+	values := map[string]interface{}{
+		"Volume": map[string]interface{}{"owner": "pvc-1", "chapSecret": "secret-1"},
+		"TaskResult": map[string]interface{}{
+			"t1": map[string]interface{}{"authToken": "token-1", "objectName": "t1-obj"},
+		},
+	}
+	// values with nested lists & string maps
+	nested := map[string]interface{}{
+		"Config": map[string]string{"chapSecret": "secret-2", "port": "3260"},
+		"TaskResult": map[string]interface{}{
+			"t2": map[string]interface{}{
+				"users": []interface{}{
+					map[string]interface{}{"authToken": "token-2", "name": "u1"},
+					"u2",
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		values   map[string]interface{}
+		keys     []string
+		expected map[string]interface{}
+	}{
+		"redact template values - +ve test case - no keys": {
+			values:   values,
+			expected: values,
+		},
+		"redact template values - +ve test case - suffix match": {
+			values: values,
+			keys:   []string{"chapSecret", "authToken"},
+			expected: map[string]interface{}{
+				"Volume": map[string]interface{}{"owner": "pvc-1", "chapSecret": "--redacted--"},
+				"TaskResult": map[string]interface{}{
+					"t1": map[string]interface{}{"authToken": "--redacted--", "objectName": "t1-obj"},
+				},
+			},
+		},
+		"redact template values - +ve test case - exact path": {
+			values: values,
+			keys:   []string{"TaskResult.t1"},
+			expected: map[string]interface{}{
+				"Volume":     map[string]interface{}{"owner": "pvc-1", "chapSecret": "secret-1"},
+				"TaskResult": map[string]interface{}{"t1": "--redacted--"},
+			},
+		},
+		"redact template values - +ve test case - partial key does not match": {
+			values:   values,
+			keys:     []string{"Secret", "1.authToken"},
+			expected: values,
+		},
+		"redact template values - +ve test case - string map": {
+			values: nested,
+			keys:   []string{"chapSecret"},
+			expected: map[string]interface{}{
+				"Config":     map[string]string{"chapSecret": "--redacted--", "port": "3260"},
+				"TaskResult": nested["TaskResult"],
+			},
+		},
+		"redact template values - +ve test case - list items": {
+			values: nested,
+			keys:   []string{"t2.users.authToken"},
+			expected: map[string]interface{}{
+				"Config": nested["Config"],
+				"TaskResult": map[string]interface{}{
+					"t2": map[string]interface{}{
+						"users": []interface{}{
+							map[string]interface{}{"authToken": "--redacted--", "name": "u1"},
+							"u2",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := redactTemplateValues(mock.values, mock.keys)
+			if !reflect.DeepEqual(actual, mock.expected) {
+				t.Fatalf("failed to test redact template values: expected '%v': actual '%v'", mock.expected, actual)
+			}
+			// provided template values are not mutated
+			if util.GetNestedString(values, "Volume", "chapSecret") != "secret-1" ||
+				nested["Config"].(map[string]string)["chapSecret"] != "secret-2" ||
+				util.GetNestedField(nested, "TaskResult", "t2", "users").([]interface{})[0].(map[string]interface{})["authToken"] != "token-2" {
+				t.Fatalf("failed to test redact template values: expected template values not to be mutated")
+			}
+		})
+	}
+}
+
+func TestSetRedactKeys(t *testing.T) {
+	logger := &fakeLogger{}
+	r := NewTaskGroupRunner()
+	r.SetLogger(logger)
+	r.SetRedactKeys("chapSecret")
+	r.AddRunTask(fakeCommandRunTask("t1", `{{- fail "t1 failed" -}}`))
+
+	values := fakeTemplateValues()
+	values["Volume"] = map[string]interface{}{"chapSecret": "secret-1"}
+	r.Run(context.Background(), values)
+
+	if logger.contains("secret-1") || !logger.contains("chapSecret: --redacted--") {
+		t.Fatalf("failed to test set redact keys: expected chapSecret to be redacted: actual '%v'", logger.messages)
+	}
+}
+
+func TestTaskExecutionError(t *testing.T) {
+	tests := map[string]struct {
+		runtasks         []*v1alpha1.RunTask
+		parallel         bool
+		expectedTaskName string
+		isVersionErr     bool
+	}{
+		"task execution error - +ve test case - failed task": {
+			runtasks: []*v1alpha1.RunTask{
+				fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`),
+				fakeCommandRunTask("t2", `{{- fail "t2 failed" -}}`),
+			},
+			expectedTaskName: "t2",
+		},
+		"task execution error - +ve test case - failed parallel task": {
+			runtasks: []*v1alpha1.RunTask{
+				fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`),
+				fakeCommandRunTask("t2", `{{- fail "t2 failed" -}}`),
+			},
+			parallel:         true,
+			expectedTaskName: "t2",
+		},
+		"task execution error - +ve test case - version mismatch": {
+			runtasks: []*v1alpha1.RunTask{
+				fakeCommandRunTask("t1", `{{- true | versionMismatchErr "not supported" | saveIf "t1.versionMismatchErr" .TaskResult | noop -}}`),
+			},
+			expectedTaskName: "t1",
+			isVersionErr:     true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			if mock.parallel {
+				r.AddParallelRunTasks(mock.runtasks...)
+			} else {
+				r.AddRunTasks(mock.runtasks)
+			}
+
+			_, err := r.Run(context.Background(), fakeTemplateValues())
+			taskErr, ok := AsTaskExecutionError(err)
+			if !ok {
+				t.Fatalf("failed to test task execution error: expected 'task execution error': actual '%#v'", err)
+			}
+			if taskErr.TaskName != mock.expectedTaskName || taskErr.TaskID != mock.expectedTaskName {
+				t.Fatalf("failed to test task execution error: expected task '%s': actual '%s' with id '%s'", mock.expectedTaskName, taskErr.TaskName, taskErr.TaskID)
+			}
+			if template.IsVersionMismatch(err) != mock.isVersionErr {
+				t.Fatalf("failed to test task execution error: expected version mismatch '%t': actual '%t'", mock.isVersionErr, !mock.isVersionErr)
+			}
+		})
+	}
+}
+
+func TestRollbackOrder(t *testing.T) {
+	tests := map[string]struct {
+		priorities    map[string]int
+		planned       []string
+		expectedOrder []string
+	}{
+		"rollback order - +ve test case - default priority": {
+			planned:       []string{"t1", "t2", "t3"},
+			expectedOrder: []string{"t3", "t2", "t1"},
+		},
+		"rollback order - +ve test case - higher priority first": {
+			priorities:    map[string]int{"t1": 10},
+			planned:       []string{"t1", "t2", "t3"},
+			expectedOrder: []string{"t1", "t3", "t2"},
+		},
+		"rollback order - +ve test case - lower priority last": {
+			priorities:    map[string]int{"t3": -1, "t1": 5, "t2": 5},
+			planned:       []string{"t1", "t2", "t3"},
+			expectedOrder: []string{"t2", "t1", "t3"},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			for _, id := range mock.planned {
+				mts := MetaTaskSpec{
+					MetaTaskIdentity: MetaTaskIdentity{Identity: id, Kind: "Command"},
+					MetaTaskProps:    MetaTaskProps{RollbackPriority: mock.priorities[id]},
+				}
+				rbSpec, _, err := getRollbackMetaInstances(mts, id+"-obj")
+				if err != nil {
+					t.Fatalf("failed to test rollback order: expected 'no error': actual '%s'", err)
+				}
+				r.rollbacks.planned = append(r.rollbacks.planned, &taskExecutor{metaTaskExec: &metaTaskExecutor{metaTask: rbSpec}})
+			}
+
+			var order []string
+			rollbacks, _ := r.rollbacks.ordered()
+			for _, rte := range rollbacks {
+				order = append(order, rte.getTaskIdentity())
+			}
+			if !reflect.DeepEqual(order, mock.expectedOrder) {
+				t.Fatalf("failed to test rollback order: expected '%v': actual '%v'", mock.expectedOrder, order)
+			}
+		})
+	}
+}
+
+func TestContinueOnError(t *testing.T) {
+	tests := map[string]struct {
+		policy           ErrorPolicy
+		parallel         bool
+		expectedExecuted int
+		expectedErrs     []string
+	}{
+		"continue on error - +ve test case - fail fast": {
+			policy:           FailFast,
+			expectedExecuted: 2,
+			expectedErrs:     []string{"t2 failed"},
+		},
+		"continue on error - +ve test case - continue on error": {
+			policy:           ContinueOnError,
+			expectedExecuted: 4,
+			expectedErrs:     []string{"t2 failed", "t4 failed"},
+		},
+		"continue on error - +ve test case - continue on error in parallel": {
+			policy:           ContinueOnError,
+			parallel:         true,
+			expectedExecuted: 4,
+			expectedErrs:     []string{"t2 failed", "t4 failed"},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.SetErrorPolicy(mock.policy)
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+			r.AddRunTask(fakeCommandRunTask("t2", `{{- fail "t2 failed" -}}`))
+			r.AddRunTask(fakeCommandRunTask("t3", `{{- "t3-obj" | saveAs "t3.objectName" .TaskResult | noop -}}`))
+			r.AddRunTask(fakeCommandRunTask("t4", `{{- fail "t4 failed" -}}`))
+			if mock.parallel {
+				r.SetConcurrency(2)
+			}
+
+			result := r.RunWithReport(fakeTemplateValues())
+			if result.Err == nil {
+				t.Fatalf("failed to test continue on error: expected 'error': actual 'no error'")
+			}
+			for _, e := range mock.expectedErrs {
+				if !strings.Contains(result.Err.Error(), e) {
+					t.Fatalf("failed to test continue on error: expected error '%s': actual '%s'", e, result.Err)
+				}
+			}
+			if len(result.ExecutedTasks) != mock.expectedExecuted {
+				t.Fatalf("failed to test continue on error: expected executed tasks '%d': actual '%d'", mock.expectedExecuted, len(result.ExecutedTasks))
+			}
+			if mock.policy == ContinueOnError && len(result.RolledBackTasks) != 0 {
+				t.Fatalf("failed to test continue on error: expected no rollbacks: actual '%d'", len(result.RolledBackTasks))
+			}
+		})
+	}
+}
+
+func TestSetTemplateFuncs(t *testing.T) {
+	tests := map[string]struct {
+		fns   template.FuncMap
+		isErr bool
+	}{
+		"set template funcs - +ve test case - custom function": {
+			fns: template.FuncMap{"orgName": func(s string) string { return "org-" + s }},
+		},
+		"set template funcs - -ve test case - collides with built-in": {
+			fns:   template.FuncMap{"saveAs": func(s string) string { return s }},
+			isErr: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			err := r.SetTemplateFuncs(mock.fns)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test set template funcs: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			if mock.isErr {
+				return
+			}
+
+			task := &v1alpha1.RunTask{}
+			task.Name = "t1"
+			task.Spec.Meta = `id: {{ "t1" | orgName }}` + "\nkind: Command\naction: put"
+			task.Spec.PostRun = `{{- "pvc" | orgName | saveAs "org-t1.objectName" .TaskResult | noop -}}`
+			r.AddRunTask(task)
+
+			values := fakeTemplateValues()
+			if err := r.Validate(values); err != nil {
+				t.Fatalf("failed to test set template funcs: expected no validate error: actual '%s'", err)
+			}
+			if _, err := r.DryRun(values); err != nil {
+				t.Fatalf("failed to test set template funcs: expected no dry run error: actual '%s'", err)
+			}
+			if _, err := r.Run(context.Background(), values); err != nil {
+				t.Fatalf("failed to test set template funcs: expected no run error: actual '%s'", err)
+			}
+			actual := util.GetNestedString(values, string(v1alpha1.TaskResultTLP), "org-t1", string(v1alpha1.ObjectNameTRTP))
+			if actual != "org-pvc" {
+				t.Fatalf("failed to test set template funcs: expected 'org-pvc': actual '%s'", actual)
+			}
+		})
+	}
+}
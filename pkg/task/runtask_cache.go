@@ -0,0 +1,187 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"sync"
+	"time"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	rt_informer "github.com/openebs/maya/pkg/client/generated/informer/externalversions/openebs.io/v1alpha1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DefaultRunTaskCacheTTL is the duration a cached runtask is used if the
+// runtask informer is not available
+const DefaultRunTaskCacheTTL = 5 * time.Minute
+
+// runTaskCacheEntry is a runtask cached against its name
+type runTaskCacheEntry struct {
+	runtask *v1alpha1.RunTask
+	fetched time.Time
+}
+
+// RunTaskCache caches the runtasks fetched by the provided task fetcher.
+// Cached runtasks are evicted when they are updated or deleted in the
+// cluster as observed by a runtask informer.
+//
+// NOTE:
+//  Cached runtasks expire after the ttl if the informer is not available or
+// is yet to sync
+//
+// NOTE:
+//  This is an implementation of TaskSpecFetcher
+type RunTaskCache struct {
+	// namespace of the cached runtasks
+	namespace string
+	// fetcher fetches the runtasks that are not cached
+	fetcher TaskSpecFetcher
+	// ttl is the duration a runtask is cached if the informer is not
+	// available
+	ttl time.Duration
+	// hasSynced flags if the informer is available & has synced; is optional
+	hasSynced func() bool
+	// now returns the current time; defaults to time.Now
+	now func() time.Time
+	// entries are the cached runtasks mapped by their names
+	entries map[string]runTaskCacheEntry
+	// mutex guards the entries
+	mutex sync.RWMutex
+}
+
+// newRunTaskCache returns a new instance of RunTaskCache that is not backed
+// by an informer
+func newRunTaskCache(namespace string, fetcher TaskSpecFetcher, ttl time.Duration) *RunTaskCache {
+	if ttl <= 0 {
+		ttl = DefaultRunTaskCacheTTL
+	}
+	return &RunTaskCache{
+		namespace: namespace,
+		fetcher:   fetcher,
+		ttl:       ttl,
+		now:       time.Now,
+		entries:   map[string]runTaskCacheEntry{},
+	}
+}
+
+// NewCachedRunTaskLister returns a new instance of RunTaskCache that caches
+// the runtasks of the provided namespace. A runtask informer that evicts the
+// updated & deleted runtasks runs till the provided stop channel is closed.
+//
+// NOTE:
+//  Cached runtasks expire after the provided ttl if the informer is not
+// available; DefaultRunTaskCacheTTL is used if ttl is not set
+func NewCachedRunTaskLister(namespace string, ttl time.Duration, stopCh <-chan struct{}) (*RunTaskCache, error) {
+	f, err := NewK8sTaskSpecFetcher(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	c := newRunTaskCache(namespace, f, ttl)
+	oecs := f.k8sClient.GetOECS()
+	if oecs == nil {
+		packageLogger().Info("runtask informer is not available: runtasks will be cached for ttl", "namespace", namespace, "ttl", c.ttl)
+		return c, nil
+	}
+
+	informer := rt_informer.NewRunTaskInformer(oecs, namespace, 0, cache.Indexers{})
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: c.onUpdate,
+		DeleteFunc: c.onDelete,
+	})
+	c.hasSynced = func() bool {
+		select {
+		case <-stopCh:
+			// informer is not available once stopped
+			return false
+		default:
+			return informer.HasSynced()
+		}
+	}
+	go informer.Run(stopCh)
+	return c, nil
+}
+
+// Namespace returns the namespace of the cached runtasks
+func (c *RunTaskCache) Namespace() string {
+	return c.namespace
+}
+
+// isExpired flags if the provided entry needs to be fetched again
+func (c *RunTaskCache) isExpired(entry runTaskCacheEntry) bool {
+	if c.hasSynced != nil && c.hasSynced() {
+		// informer evicts the stale entries
+		return false
+	}
+	return c.now().Sub(entry.fetched) >= c.ttl
+}
+
+// Fetch returns the runtask with the provided name. Runtask is fetched via
+// this cache's task fetcher if it is not cached or has expired.
+//
+// NOTE:
+//  A copy of the cached runtask is returned so that the cached runtask is
+// not mutated by its callers
+func (c *RunTaskCache) Fetch(taskName string) (runtask *v1alpha1.RunTask, err error) {
+	c.mutex.RLock()
+	entry, found := c.entries[taskName]
+	c.mutex.RUnlock()
+	if found && !c.isExpired(entry) {
+		return entry.runtask.DeepCopy(), nil
+	}
+
+	runtask, err = c.fetcher.Fetch(taskName)
+	if err != nil {
+		return
+	}
+
+	c.mutex.Lock()
+	c.entries[taskName] = runTaskCacheEntry{runtask: runtask.DeepCopy(), fetched: c.now()}
+	c.mutex.Unlock()
+	return
+}
+
+// Evict removes the runtask with the provided name from this cache
+func (c *RunTaskCache) Evict(taskName string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, taskName)
+}
+
+// onUpdate evicts the updated runtask
+func (c *RunTaskCache) onUpdate(oldObj, newObj interface{}) {
+	if runtask, ok := newObj.(*v1alpha1.RunTask); ok {
+		if isDebug() {
+			packageLogger().Info("runtask was updated: evicting it from cache", "runtask", runtask.Name)
+		}
+		c.Evict(runtask.Name)
+	}
+}
+
+// onDelete evicts the deleted runtask
+func (c *RunTaskCache) onDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if runtask, ok := obj.(*v1alpha1.RunTask); ok {
+		if isDebug() {
+			packageLogger().Info("runtask was deleted: evicting it from cache", "runtask", runtask.Name)
+		}
+		c.Evict(runtask.Name)
+	}
+}
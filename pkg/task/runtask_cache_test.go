@@ -0,0 +1,158 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// fakeTaskFetcher returns a runtask whose task specifications are the number
+// of times the runtask was fetched
+type fakeTaskFetcher struct {
+	fetched map[string]int
+}
+
+func (f *fakeTaskFetcher) Fetch(taskName string) (*v1alpha1.RunTask, error) {
+	if taskName == "missing" {
+		return nil, fmt.Errorf("runtask '%s' not found", taskName)
+	}
+	f.fetched[taskName]++
+
+	runtask := &v1alpha1.RunTask{}
+	runtask.Name = taskName
+	runtask.Spec.Meta = "id: " + taskName + "\nkind: Command\naction: output"
+	runtask.Spec.Task = fmt.Sprintf("%d", f.fetched[taskName])
+	return runtask, nil
+}
+
+func TestRunTaskCacheFetch(t *testing.T) {
+	tests := map[string]struct {
+		synced   bool
+		elapsed  time.Duration
+		evict    func(c *RunTaskCache)
+		expected string
+	}{
+		"runtask cache - +ve test case - cached within ttl": {
+			elapsed:  time.Minute,
+			expected: "1",
+		},
+		"runtask cache - +ve test case - expired after ttl": {
+			elapsed:  10 * time.Minute,
+			expected: "2",
+		},
+		"runtask cache - +ve test case - no expiry with synced informer": {
+			synced:   true,
+			elapsed:  10 * time.Minute,
+			expected: "1",
+		},
+		"runtask cache - +ve test case - evicted on update": {
+			synced: true,
+			evict: func(c *RunTaskCache) {
+				old := &v1alpha1.RunTask{}
+				old.Name = "rt1"
+				c.onUpdate(old, old.DeepCopy())
+			},
+			expected: "2",
+		},
+		"runtask cache - +ve test case - evicted on delete": {
+			synced: true,
+			evict: func(c *RunTaskCache) {
+				deleted := &v1alpha1.RunTask{}
+				deleted.Name = "rt1"
+				c.onDelete(cache.DeletedFinalStateUnknown{Key: "default/rt1", Obj: deleted})
+			},
+			expected: "2",
+		},
+		"runtask cache - +ve test case - other runtask is updated": {
+			synced: true,
+			evict: func(c *RunTaskCache) {
+				other := &v1alpha1.RunTask{}
+				other.Name = "rt2"
+				c.onUpdate(other, other.DeepCopy())
+			},
+			expected: "1",
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			c := newRunTaskCache("default", &fakeTaskFetcher{fetched: map[string]int{}}, 0)
+			c.now = func() time.Time { return now }
+			c.hasSynced = func() bool { return mock.synced }
+
+			first, err := c.Fetch("rt1")
+			if err != nil {
+				t.Fatalf("failed to test runtask cache: expected no error: actual '%s'", err)
+			}
+			// mutating the fetched runtask does not mutate the cached one
+			first.Spec.Task = "mutated"
+
+			now = now.Add(mock.elapsed)
+			if mock.evict != nil {
+				mock.evict(c)
+			}
+
+			second, err := c.Fetch("rt1")
+			if err != nil {
+				t.Fatalf("failed to test runtask cache: expected no error: actual '%s'", err)
+			}
+			if second.Spec.Task != mock.expected {
+				t.Fatalf("failed to test runtask cache: expected '%s': actual '%s'", mock.expected, second.Spec.Task)
+			}
+		})
+	}
+}
+
+func TestRunTaskCacheFetchError(t *testing.T) {
+	c := newRunTaskCache("default", &fakeTaskFetcher{fetched: map[string]int{}}, time.Minute)
+	_, err := c.Fetch("missing")
+	if err == nil {
+		t.Fatalf("failed to test runtask cache fetch error: expected error: actual no error")
+	}
+	if len(c.entries) != 0 {
+		t.Fatalf("failed to test runtask cache fetch error: expected no cached runtasks: actual '%d'", len(c.entries))
+	}
+}
+
+func TestNewFallbackRunnerWithFetcher(t *testing.T) {
+	fetcher := &fakeTaskFetcher{fetched: map[string]int{}}
+	c := newRunTaskCache("default", fetcher, time.Minute)
+
+	cast := &v1alpha1.CASTemplate{}
+	cast.Spec.TaskNamespace = "default"
+	cast.Spec.RunTasks.Tasks = []string{"rt1", "rt2"}
+	cast.Spec.OutputTask = "rt1"
+
+	for i := 0; i < 2; i++ {
+		options, err := newFallbackRunner(cast, nil, c)
+		if err != nil {
+			t.Fatalf("failed to test new fallback runner with fetcher: expected no error: actual '%s'", err)
+		}
+		if len(options.allTasks) != 2 {
+			t.Fatalf("failed to test new fallback runner with fetcher: expected '2' runtasks: actual '%d'", len(options.allTasks))
+		}
+	}
+	if fetcher.fetched["rt1"] != 1 || fetcher.fetched["rt2"] != 1 {
+		t.Fatalf("failed to test new fallback runner with fetcher: expected runtasks to be fetched once: actual '%v'", fetcher.fetched)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+// reservedScopeKeys are the top level properties that are managed by the
+// runner & hence can not be set as a task's local variables
+var reservedScopeKeys = []v1alpha1.TopLevelProperty{
+	v1alpha1.TaskResultTLP,
+	v1alpha1.ListItemsTLP,
+	v1alpha1.CurrentJSONResultTLP,
+}
+
+// scopeATask layers the local variables set in the task's meta scope if any
+// over the task's template values. The task is executed against these
+// layered values while the rest of the tasks continue to use the original
+// values.
+//
+// Example:
+//  id: createsvc
+//  kind: Service
+//  action: put
+//  scope:
+//    svcName: {{ .Volume.owner }}-ctrl-svc
+//
+// The local variable is accessed as {{ .svcName }} in the task & post run
+// specifications of this task only.
+//
+// NOTE:
+//  Layering is shallow. Hence results set by the task e.g. at .TaskResult
+// are set against the original values.
+func (m *TaskGroupRunner) scopeATask(te *taskExecutor) error {
+	scope := te.metaTaskExec.getScope()
+	if len(scope) == 0 {
+		return nil
+	}
+
+	for _, key := range reservedScopeKeys {
+		if _, found := scope[string(key)]; found {
+			return fmt.Errorf("invalid scope of task '%s': '%s' is reserved", te.getTaskIdentity(), key)
+		}
+	}
+
+	layered := make(map[string]interface{}, len(te.templateValues)+len(scope))
+	for k, v := range te.templateValues {
+		layered[k] = v
+	}
+	for k, v := range scope {
+		layered[k] = v
+	}
+
+	te.globalValues = te.templateValues
+	te.templateValues = layered
+	return nil
+}
+
+// unscopeATask discards the local variables of the task & sets the task's
+// template values back to the original values. Top level values set or
+// removed by the task other than its local variables are synced to the
+// original values. A global value shadowed by a local variable is retained
+// as is.
+func (m *TaskGroupRunner) unscopeATask(te *taskExecutor) map[string]interface{} {
+	if te.globalValues == nil {
+		return te.templateValues
+	}
+
+	scope := te.metaTaskExec.getScope()
+	for k, v := range te.templateValues {
+		if _, local := scope[k]; local {
+			continue
+		}
+		te.globalValues[k] = v
+	}
+	for k := range te.globalValues {
+		if _, local := scope[k]; local {
+			continue
+		}
+		if _, found := te.templateValues[k]; !found {
+			delete(te.globalValues, k)
+		}
+	}
+
+	te.templateValues = te.globalValues
+	te.globalValues = nil
+	return te.templateValues
+}
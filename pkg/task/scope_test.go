@@ -0,0 +1,83 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+func TestTaskScope(t *testing.T) {
+	tests := map[string]struct {
+		scope          string
+		isErr          bool
+		expectedOutput string
+	}{
+		"task scope - +ve test case - local variable is visible to its task only": {
+			scope:          "\nscope:\n  node: local-node",
+			expectedOutput: "t1=local-node/global-owner,t2=/global-owner",
+		},
+		"task scope - +ve test case - shadowed global value is restored": {
+			scope:          "\nscope:\n  owner: local-owner",
+			expectedOutput: "t1=/local-owner,t2=/global-owner",
+		},
+		"task scope - +ve test case - local variable is rendered": {
+			scope:          "\nscope:\n  node: {{ .owner }}-node",
+			expectedOutput: "t1=global-owner-node/global-owner,t2=/global-owner",
+		},
+		"task scope - -ve test case - reserved key is rejected": {
+			scope: "\nscope:\n  TaskResult: none",
+			isErr: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			t1 := fakeCommandRunTask("t1", `{{- .node | default "" | saveAs "t1.node" .TaskResult | noop -}}{{- .owner | saveAs "t1.owner" .TaskResult | noop -}}`)
+			t1.Spec.Meta = t1.Spec.Meta + mock.scope
+			t2 := fakeCommandRunTask("t2", `{{- .node | default "" | saveAs "t2.node" .TaskResult | noop -}}{{- .owner | saveAs "t2.owner" .TaskResult | noop -}}`)
+
+			r := NewTaskGroupRunner()
+			r.AddRunTask(t1)
+			r.AddRunTask(t2)
+			r.SetOutputTask(&v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{
+				Meta: "id: o1\nkind: Command\naction: output",
+				Task: `t1={{ nestedString .TaskResult "t1" "node" }}/{{ nestedString .TaskResult "t1" "owner" }},t2={{ nestedString .TaskResult "t2" "node" }}/{{ nestedString .TaskResult "t2" "owner" }}`,
+			}})
+
+			values := fakeTemplateValues()
+			values["owner"] = "global-owner"
+			result := r.RunWithReport(values)
+			if mock.isErr != (result.Err != nil) {
+				t.Fatalf("failed to test task scope: expected error '%t': actual '%v'", mock.isErr, result.Err)
+			}
+			if mock.isErr {
+				return
+			}
+			if string(result.Output) != mock.expectedOutput {
+				t.Fatalf("failed to test task scope: expected output '%s': actual '%s'", mock.expectedOutput, result.Output)
+			}
+			if values["owner"] != "global-owner" {
+				t.Fatalf("failed to test task scope: expected global owner 'global-owner': actual '%v'", values["owner"])
+			}
+			if _, found := values["node"]; found {
+				t.Fatalf("failed to test task scope: expected local variable to be discarded: actual '%v'", values["node"])
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	m_k8s_client "github.com/openebs/maya/pkg/client/k8s"
+	menv "github.com/openebs/maya/pkg/env/v1alpha1"
+	api_core_v1 "k8s.io/api/core/v1"
+	mach_apis_meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecretFetcher is the contract to fetch a K8s Secret
+type SecretFetcher interface {
+	FetchSecret(namespace, name string) (secret *api_core_v1.Secret, err error)
+}
+
+// K8sSecretFetcher fetches K8s Secrets from K8s cluster
+type K8sSecretFetcher struct{}
+
+// FetchSecret returns the K8s Secret with the provided name & namespace
+//
+// NOTE:
+//  This is an implementation of SecretFetcher interface
+func (f K8sSecretFetcher) FetchSecret(namespace, name string) (*api_core_v1.Secret, error) {
+	kc, err := m_k8s_client.NewK8sClient(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return kc.GetSecret(name, mach_apis_meta_v1.GetOptions{})
+}
+
+// secretCache caches the secrets that are fetched during a run so that a
+// secret referred to by more than one task is fetched only once
+type secretCache struct {
+	fetcher SecretFetcher
+	// secrets are the fetched secrets mapped by their namespace & name
+	secrets map[string]*api_core_v1.Secret
+	mutex   sync.Mutex
+}
+
+// newSecretCache returns a new instance of secretCache
+func newSecretCache(fetcher SecretFetcher) *secretCache {
+	return &secretCache{
+		fetcher: fetcher,
+		secrets: map[string]*api_core_v1.Secret{},
+	}
+}
+
+// value returns the value of the key of the secret referred to by the
+// provided reference
+func (c *secretCache) value(ref *v1alpha1.SecretRef) (string, error) {
+	if len(ref.Name) == 0 || len(ref.Key) == 0 {
+		return "", fmt.Errorf("failed to get secret value: name '%s': key '%s': both name and key are required", ref.Name, ref.Key)
+	}
+
+	namespace := ref.Namespace
+	if len(namespace) == 0 {
+		namespace = menv.Get(menv.OpenEBSNamespace)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cacheKey := namespace + "/" + ref.Name
+	secret, ok := c.secrets[cacheKey]
+	if !ok {
+		var err error
+		secret, err = c.fetcher.FetchSecret(namespace, ref.Name)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get secret value: name '%s': namespace '%s'", ref.Name, namespace)
+		}
+		c.secrets[cacheKey] = secret
+	}
+
+	if value, ok := secret.Data[ref.Key]; ok {
+		return string(value), nil
+	}
+	if value, ok := secret.StringData[ref.Key]; ok {
+		return value, nil
+	}
+	return "", fmt.Errorf("failed to get secret value: name '%s': namespace '%s': key '%s' not found", ref.Name, namespace, ref.Key)
+}
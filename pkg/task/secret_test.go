@@ -0,0 +1,126 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+	api_core_v1 "k8s.io/api/core/v1"
+)
+
+// fakeSecretFetcher returns the secrets mapped by their namespace & name
+// and counts the fetches
+type fakeSecretFetcher struct {
+	secrets map[string]*api_core_v1.Secret
+	fetched int
+}
+
+func (f *fakeSecretFetcher) FetchSecret(namespace, name string) (*api_core_v1.Secret, error) {
+	f.fetched++
+	secret, ok := f.secrets[namespace+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("secret '%s/%s' not found", namespace, name)
+	}
+	return secret, nil
+}
+
+func TestSecretRef(t *testing.T) {
+	fakeSecretTask := func(id string, ref *v1alpha1.SecretRef) *v1alpha1.RunTask {
+		r := fakeCommandRunTask(id, `{{- .Secret | saveAs "`+id+`.objectName" .TaskResult | noop -}}`)
+		r.Spec.SecretRef = ref
+		return r
+	}
+
+	tests := map[string]struct {
+		tasks           []*v1alpha1.RunTask
+		expected        string
+		expectedFetched int
+		isErr           bool
+	}{
+		"secret ref - +ve test case - data key": {
+			tasks:           []*v1alpha1.RunTask{fakeSecretTask("t1", &v1alpha1.SecretRef{Name: "cstor", Namespace: "openebs", Key: "password"})},
+			expected:        "pass-1",
+			expectedFetched: 1,
+		},
+		"secret ref - +ve test case - string data key": {
+			tasks:           []*v1alpha1.RunTask{fakeSecretTask("t1", &v1alpha1.SecretRef{Name: "cstor", Namespace: "openebs", Key: "user"})},
+			expected:        "admin",
+			expectedFetched: 1,
+		},
+		"secret ref - +ve test case - secret is fetched once per run": {
+			tasks: []*v1alpha1.RunTask{
+				fakeSecretTask("t1", &v1alpha1.SecretRef{Name: "cstor", Namespace: "openebs", Key: "password"}),
+				fakeSecretTask("t2", &v1alpha1.SecretRef{Name: "cstor", Namespace: "openebs", Key: "user"}),
+			},
+			expected:        "pass-1",
+			expectedFetched: 1,
+		},
+		"secret ref - -ve test case - missing secret": {
+			tasks:           []*v1alpha1.RunTask{fakeSecretTask("t1", &v1alpha1.SecretRef{Name: "jiva", Namespace: "openebs", Key: "password"})},
+			expectedFetched: 1,
+			isErr:           true,
+		},
+		"secret ref - -ve test case - missing key": {
+			tasks:           []*v1alpha1.RunTask{fakeSecretTask("t1", &v1alpha1.SecretRef{Name: "cstor", Namespace: "openebs", Key: "token"})},
+			expectedFetched: 1,
+			isErr:           true,
+		},
+		"secret ref - -ve test case - missing name": {
+			tasks: []*v1alpha1.RunTask{fakeSecretTask("t1", &v1alpha1.SecretRef{Namespace: "openebs", Key: "password"})},
+			isErr: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &fakeSecretFetcher{secrets: map[string]*api_core_v1.Secret{
+				"openebs/cstor": {
+					Data:       map[string][]byte{"password": []byte("pass-1")},
+					StringData: map[string]string{"user": "admin"},
+				},
+			}}
+			r := NewTaskGroupRunner()
+			r.SetSecretFetcher(f)
+			for _, task := range mock.tasks {
+				r.AddRunTask(task)
+			}
+
+			values := fakeTemplateValues()
+			_, err := r.Run(context.Background(), values)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test secret ref: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			if f.fetched != mock.expectedFetched {
+				t.Fatalf("failed to test secret ref: expected fetches '%d': actual '%d'", mock.expectedFetched, f.fetched)
+			}
+			if mock.isErr {
+				return
+			}
+			actual := util.GetNestedString(values, string(v1alpha1.TaskResultTLP), "t1", string(v1alpha1.ObjectNameTRTP))
+			if actual != mock.expected {
+				t.Fatalf("failed to test secret ref: expected '%s': actual '%s'", mock.expected, actual)
+			}
+			if values[string(v1alpha1.SecretTLP)] != "--redacted--" {
+				t.Fatalf("failed to test secret ref: expected redacted secret: actual '%v'", values[string(v1alpha1.SecretTLP)])
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+// runnerJSONVersion is the version of the json representation of a task
+// group runner
+const runnerJSONVersion = "v1"
+
+// runnerJSON is the json representation of a task group runner. This holds
+// the specifications of the runner & none of its runtime state e.g.
+// rollbacks.
+type runnerJSON struct {
+	// Version is the version of this representation; a runner is not
+	// unmarshalled from an unsupported version
+	Version string `json:"version"`
+	// RunTasks are the tasks of the runner in their order of execution
+	RunTasks []*v1alpha1.RunTask `json:"runTasks,omitempty"`
+	// OutputTasks are the output tasks of the runner
+	OutputTasks []*v1alpha1.RunTask `json:"outputTasks,omitempty"`
+	// FallbackTemplates are the CAS Templates the runner falls back to in
+	// their order of preference
+	FallbackTemplates []string `json:"fallbackTemplates,omitempty"`
+}
+
+// MarshalJSON returns the json representation of this runner's tasks, output
+// tasks & fallback templates. This is meant to persist a runner that is
+// configured partially e.g. in a CAS Template.
+//
+// NOTE:
+//  Runtime state e.g. rollbacks & options e.g. timeout or hooks are not
+// part of this representation
+func (m *TaskGroupRunner) MarshalJSON() ([]byte, error) {
+	return json.Marshal(runnerJSON{
+		Version:           runnerJSONVersion,
+		RunTasks:          m.allTasks,
+		OutputTasks:       m.outputTasks,
+		FallbackTemplates: m.fallbackTemplates,
+	})
+}
+
+// UnmarshalJSON sets this runner's tasks, output tasks & fallback templates
+// from the provided json representation. These replace the ones that were
+// set previously. Rest of this runner's options are retained.
+//
+// NOTE:
+//  Tasks are validated the same way as they are when added to a runner.
+// This runner is not modified if there are errors.
+func (m *TaskGroupRunner) UnmarshalJSON(data []byte) error {
+	var r runnerJSON
+	err := json.Unmarshal(data, &r)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal task group runner")
+	}
+	if r.Version != runnerJSONVersion {
+		return fmt.Errorf("failed to unmarshal task group runner: unsupported version '%s': supported version '%s'", r.Version, runnerJSONVersion)
+	}
+
+	decoded := &TaskGroupRunner{}
+	for idx, runtask := range r.RunTasks {
+		err = decoded.AddRunTask(runtask)
+		if err != nil {
+			return errors.Wrapf(err, "failed to unmarshal task group runner: invalid runtask at index '%d'", idx)
+		}
+	}
+	for idx, runtask := range r.OutputTasks {
+		err = decoded.AddOutputTask(runtask)
+		if err != nil {
+			return errors.Wrapf(err, "failed to unmarshal task group runner: invalid output task at index '%d'", idx)
+		}
+	}
+	decoded.SetFallback(r.FallbackTemplates)
+
+	m.allTasks = decoded.allTasks
+	m.taskIndex = nil
+	m.outputTasks = decoded.outputTasks
+	m.fallbackTemplates = decoded.fallbackTemplates
+	return nil
+}
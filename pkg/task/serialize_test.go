@@ -0,0 +1,175 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+func TestTaskGroupRunnerJSONRoundTrip(t *testing.T) {
+	output := &v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{
+		Meta: "id: o1\nkind: Command\naction: output",
+		Task: `{{ nestedString .TaskResult "t2" "node" }}`,
+	}}
+
+	tests := map[string]struct {
+		runtasks    []*v1alpha1.RunTask
+		outputTasks []*v1alpha1.RunTask
+		fallbacks   []string
+	}{
+		"runner json - +ve test case - empty runner": {},
+		"runner json - +ve test case - tasks only": {
+			runtasks: []*v1alpha1.RunTask{fakeCommandRunTask("t1", ""), fakeCommandRunTask("t2", "")},
+		},
+		"runner json - +ve test case - tasks, output & fallbacks": {
+			runtasks: []*v1alpha1.RunTask{
+				fakeCommandRunTask("t1", `{{- "node-1" | saveAs "t1.node" .TaskResult | noop -}}`),
+				fakeCommandRunTask("t2", `{{- "node-2" | saveAs "t2.node" .TaskResult | noop -}}`),
+			},
+			outputTasks: []*v1alpha1.RunTask{output},
+			fallbacks:   []string{"cast-1", "cast-2"},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			for _, runtask := range mock.runtasks {
+				r.AddRunTask(runtask)
+			}
+			for _, runtask := range mock.outputTasks {
+				r.AddOutputTask(runtask)
+			}
+			r.SetFallback(mock.fallbacks)
+
+			b, err := json.Marshal(r)
+			if err != nil {
+				t.Fatalf("failed to test runner json: expected no error: actual '%+v'", err)
+			}
+			decoded := NewTaskGroupRunner()
+			err = json.Unmarshal(b, decoded)
+			if err != nil {
+				t.Fatalf("failed to test runner json: expected no error: actual '%+v'", err)
+			}
+
+			if len(decoded.allTasks) != len(r.allTasks) || len(decoded.outputTasks) != len(r.outputTasks) {
+				t.Fatalf("failed to test runner json: expected tasks '%d' & output tasks '%d': actual '%d' & '%d'", len(r.allTasks), len(r.outputTasks), len(decoded.allTasks), len(decoded.outputTasks))
+			}
+			for idx, runtask := range decoded.allTasks {
+				if !reflect.DeepEqual(runtask.Spec.Meta, r.allTasks[idx].Spec.Meta) || !reflect.DeepEqual(runtask.Spec.PostRun, r.allTasks[idx].Spec.PostRun) {
+					t.Fatalf("failed to test runner json: expected task '%+v': actual '%+v'", r.allTasks[idx].Spec, runtask.Spec)
+				}
+			}
+			// round trip is lossless if the runner is marshalled the same
+			again, err := json.Marshal(decoded)
+			if err != nil {
+				t.Fatalf("failed to test runner json: expected no error: actual '%+v'", err)
+			}
+			if string(again) != string(b) {
+				t.Fatalf("failed to test runner json: expected json '%s': actual '%s'", b, again)
+			}
+			if !reflect.DeepEqual(decoded.fallbackTemplates, r.fallbackTemplates) {
+				t.Fatalf("failed to test runner json: expected fallbacks '%v': actual '%v'", r.fallbackTemplates, decoded.fallbackTemplates)
+			}
+		})
+	}
+}
+
+func TestTaskGroupRunnerJSONRun(t *testing.T) {
+	r := NewTaskGroupRunner()
+	r.AddRunTask(fakeCommandRunTask("t1", `{{- "node-1" | saveAs "t1.node" .TaskResult | noop -}}`))
+	r.SetOutputTask(&v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{
+		Meta: "id: o1\nkind: Command\naction: output",
+		Task: `{{ nestedString .TaskResult "t1" "node" }}`,
+	}})
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("failed to test runner json run: expected no error: actual '%+v'", err)
+	}
+	decoded := NewTaskGroupRunner()
+	err = json.Unmarshal(b, decoded)
+	if err != nil {
+		t.Fatalf("failed to test runner json run: expected no error: actual '%+v'", err)
+	}
+
+	output, err := decoded.Run(context.Background(), fakeTemplateValues())
+	if err != nil {
+		t.Fatalf("failed to test runner json run: expected no error: actual '%+v'", err)
+	}
+	if string(output) != "node-1" {
+		t.Fatalf("failed to test runner json run: expected output 'node-1': actual '%s'", output)
+	}
+}
+
+func TestTaskGroupRunnerUnmarshalJSON(t *testing.T) {
+	tests := map[string]struct {
+		data          string
+		isErr         bool
+		expectedTasks int
+	}{
+		"unmarshal runner - +ve test case - valid json": {
+			data:          `{"version":"v1","runTasks":[{"metadata":{"name":"t1"},"spec":{"meta":"id: t1\nkind: Command\naction: put"}}]}`,
+			expectedTasks: 1,
+		},
+		"unmarshal runner - -ve test case - unsupported version": {
+			data:  `{"version":"v2","runTasks":[{"metadata":{"name":"t1"},"spec":{"meta":"id: t1\nkind: Command\naction: put"}}]}`,
+			isErr: true,
+		},
+		"unmarshal runner - -ve test case - missing version": {
+			data:  `{"runTasks":[]}`,
+			isErr: true,
+		},
+		"unmarshal runner - -ve test case - task without meta": {
+			data:  `{"version":"v1","runTasks":[{"metadata":{"name":"t1"},"spec":{}}]}`,
+			isErr: true,
+		},
+		"unmarshal runner - -ve test case - output task without task": {
+			data:  `{"version":"v1","outputTasks":[{"metadata":{"name":"o1"},"spec":{"meta":"id: o1"}}]}`,
+			isErr: true,
+		},
+		"unmarshal runner - -ve test case - invalid json": {
+			data:  `{"version":`,
+			isErr: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.AddRunTask(fakeCommandRunTask("existing", ""))
+
+			err := r.UnmarshalJSON([]byte(mock.data))
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test unmarshal runner: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			expectedTasks := mock.expectedTasks
+			if mock.isErr {
+				// runner is not modified on error
+				expectedTasks = 1
+			}
+			if len(r.allTasks) != expectedTasks {
+				t.Fatalf("failed to test unmarshal runner: expected tasks '%d': actual '%d'", expectedTasks, len(r.allTasks))
+			}
+		})
+	}
+}
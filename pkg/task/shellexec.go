@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ShellExecPluginName is the name that ShellExecPlugin is typically
+// registered with
+const ShellExecPluginName = "shellexec"
+
+// ShellExecPlugin executes the command set in the meta specifications of a
+// task as a subprocess. The command's standard output is the result of the
+// task.
+//
+// A sample registration of this plugin by a binary:
+//
+//  task.RegisterTaskPlugin(task.ShellExecPluginName, &task.ShellExecPlugin{
+//    AllowedCommands: []string{"/sbin/iscsiadm -m session"},
+//  })
+//
+// A sample shellexec task:
+//
+// meta: |
+//   id: iscsisessions
+//   kind: Command
+//   action: plugin/shellexec
+//   command: /sbin/iscsiadm -m session
+//   timeout: 30s
+//
+// NOTE:
+//  The whitelist is set by the binary & not by the templates. Hence a
+// template can not execute a command that is not allowed by the operator.
+type ShellExecPlugin struct {
+	// AllowedCommands is the whitelist of commands along with their arguments
+	// that can be executed. A task's command is executed only if it matches
+	// one of these exactly.
+	AllowedCommands []string
+	// AllowStdin flags if the rendered task specifications can be passed to
+	// the command as its standard input. A task having task specifications
+	// is not executed if this is not set.
+	//
+	// NOTE:
+	//  Allowing the standard input of an interpreter e.g. /bin/sh lets the
+	// templates execute any script
+	AllowStdin bool
+
+	// execCommand builds the command to be executed; defaults to
+	// exec.CommandContext
+	execCommand func(ctx context.Context, name string, args ...string) *exec.Cmd
+}
+
+// isAllowed returns true if the provided command along with its arguments is
+// present in the whitelist
+func (p *ShellExecPlugin) isAllowed(command []string) bool {
+	for _, allowed := range p.AllowedCommands {
+		if strings.Join(strings.Fields(allowed), " ") == strings.Join(command, " ") {
+			return true
+		}
+	}
+	return false
+}
+
+// Execute executes the command set in the provided meta specifications. The
+// provided task specifications if any are passed to the command as its
+// standard input. The command is killed if it does not complete within the
+// task's timeout or if the provided context is done.
+func (p *ShellExecPlugin) Execute(ctx context.Context, meta MetaTaskSpec, spec string, values map[string]interface{}) (result []byte, err error) {
+	command := strings.Fields(meta.Command)
+	if len(command) == 0 {
+		return nil, fmt.Errorf("invalid shellexec meta specifications: command is missing")
+	}
+	if !p.isAllowed(command) {
+		return nil, fmt.Errorf("refused to execute command '%s': command is not allowed", meta.Command)
+	}
+	if len(spec) != 0 && !p.AllowStdin {
+		return nil, fmt.Errorf("refused to execute command '%s': standard input is not allowed", meta.Command)
+	}
+
+	timeout, _ := time.ParseDuration(strings.TrimSpace(meta.Timeout))
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	execCommand := p.execCommand
+	if execCommand == nil {
+		execCommand = exec.CommandContext
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := execCommand(ctx, command[0], command[1:]...)
+	cmd.Stdin = strings.NewReader(spec)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if ctx.Err() != nil {
+		return nil, errors.Wrapf(ctx.Err(), "failed to execute command '%s'", command[0])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute command '%s': %s: %s", command[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
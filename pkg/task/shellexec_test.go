@@ -0,0 +1,158 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeExecCommand executes the shellexec helper process of this test binary
+// instead of the provided command
+func fakeExecCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cs := append([]string{"-test.run=TestShellExecHelperProcess", "--", name}, args...)
+	cmd := exec.CommandContext(ctx, os.Args[0], cs...)
+	cmd.Env = append(os.Environ(), "GO_WANT_SHELLEXEC_HELPER_PROCESS=1")
+	return cmd
+}
+
+// TestShellExecHelperProcess is not a real test. It is executed as a
+// subprocess by fakeExecCommand & behaves as per the fake command.
+func TestShellExecHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_SHELLEXEC_HELPER_PROCESS") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	args = args[1:]
+
+	switch args[0] {
+	case "/bin/cat":
+		io.Copy(os.Stdout, os.Stdin)
+	case "/bin/echo":
+		fmt.Fprint(os.Stdout, strings.Join(args[1:], " "))
+	case "/bin/sleep":
+		time.Sleep(10 * time.Second)
+	default:
+		fmt.Fprint(os.Stderr, "command failed")
+		os.Exit(1)
+	}
+}
+
+func TestShellExecPlugin(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := map[string]struct {
+		ctx        context.Context
+		command    string
+		allowed    []string
+		allowStdin bool
+		timeout    string
+		spec       string
+		expected   string
+		isErr      bool
+	}{
+		"shellexec - +ve test case - spec as stdin": {
+			command:    "/bin/cat",
+			allowed:    []string{"/bin/cat"},
+			allowStdin: true,
+			spec:       "iscsiadm -m discovery",
+			expected:   "iscsiadm -m discovery",
+		},
+		"shellexec - +ve test case - command with args": {
+			command:  "/bin/echo  pvc-1 ok",
+			allowed:  []string{"/bin/sh", "/bin/echo pvc-1 ok"},
+			expected: "pvc-1 ok",
+		},
+		"shellexec - -ve test case - command is missing": {
+			allowed: []string{"/bin/cat"},
+			isErr:   true,
+		},
+		"shellexec - -ve test case - allowed commands is missing": {
+			command: "/bin/cat",
+			isErr:   true,
+		},
+		"shellexec - -ve test case - command is not allowed": {
+			command: "/bin/rm -rf /",
+			allowed: []string{"/bin/cat"},
+			isErr:   true,
+		},
+		"shellexec - -ve test case - args are not allowed": {
+			command: "/bin/echo pvc-2 ok",
+			allowed: []string{"/bin/echo pvc-1 ok"},
+			isErr:   true,
+		},
+		"shellexec - -ve test case - stdin is not allowed": {
+			command: "/bin/cat",
+			allowed: []string{"/bin/cat"},
+			spec:    "rm -rf /",
+			isErr:   true,
+		},
+		"shellexec - -ve test case - command failed": {
+			command: "/bin/false",
+			allowed: []string{"/bin/false"},
+			isErr:   true,
+		},
+		"shellexec - -ve test case - timed out": {
+			command: "/bin/sleep",
+			allowed: []string{"/bin/sleep"},
+			timeout: "100ms",
+			isErr:   true,
+		},
+		"shellexec - -ve test case - cancelled context": {
+			ctx:     cancelled,
+			command: "/bin/sleep",
+			allowed: []string{"/bin/sleep"},
+			isErr:   true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := mock.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			p := &ShellExecPlugin{AllowedCommands: mock.allowed, AllowStdin: mock.allowStdin, execCommand: fakeExecCommand}
+			meta := MetaTaskSpec{}
+			meta.Command = mock.command
+			meta.Timeout = mock.timeout
+
+			result, err := p.Execute(ctx, meta, mock.spec, nil)
+			if mock.isErr && err == nil {
+				t.Fatalf("failed to test shellexec plugin: expected error: actual no error")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("failed to test shellexec plugin: expected no error: actual '%s'", err)
+			}
+			if string(result) != mock.expected {
+				t.Fatalf("failed to test shellexec plugin: expected '%s': actual '%s'", mock.expected, result)
+			}
+		})
+	}
+}
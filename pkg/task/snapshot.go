@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+)
+
+// ValuesSnapshotHook is a closure definition that provides option to
+// inspect the template values of a run after each task that succeeds
+//
+// NOTE:
+//  This is meant for diagnostic tooling & not for production flows. The
+// provided values are a deep copy of the runner's template values. Hence
+// mutating these does not affect the run.
+type ValuesSnapshotHook func(taskID string, values map[string]interface{})
+
+// WithValuesSnapshotHook sets the hook that is invoked with a snapshot of
+// the template values after each task of the task group runner succeeds
+func WithValuesSnapshotHook(hook ValuesSnapshotHook) TaskGroupRunnerOption {
+	return func(m *TaskGroupRunner) {
+		m.valuesSnapshotHook = hook
+	}
+}
+
+// snapshotValues invokes the values snapshot hook if any with a deep copy of
+// the provided template values. The values of the redacted keys & of the
+// secret referred to by the task are redacted in this copy.
+//
+// NOTE:
+//  A panic in the hook is recovered from & is logged
+func (m *TaskGroupRunner) snapshotValues(identity string, values map[string]interface{}) {
+	if m.valuesSnapshotHook == nil {
+		return
+	}
+
+	snapshot := util.DeepCopyMap(redactTemplateValues(values, m.redactKeys))
+	if _, ok := snapshot[string(v1alpha1.SecretTLP)]; ok {
+		snapshot[string(v1alpha1.SecretTLP)] = "--redacted--"
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger().Error(fmt.Errorf("%+v", r), "recovered from panic in values snapshot hook", "task", identity)
+		}
+	}()
+	m.valuesSnapshotHook(identity, snapshot)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+)
+
+func TestValuesSnapshotHook(t *testing.T) {
+	tests := map[string]struct {
+		posts            []string
+		isErr            bool
+		expectedSnapshot []string
+	}{
+		"values snapshot - +ve test case - all tasks succeed": {
+			posts: []string{
+				`{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+				`{{- "t2-obj" | saveAs "t2.objectName" .TaskResult | noop -}}`,
+			},
+			expectedSnapshot: []string{"t1", "t2"},
+		},
+		"values snapshot - -ve test case - failed task is not snapshotted": {
+			posts: []string{
+				`{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`,
+				`{{- fail "t2 failed" -}}`,
+			},
+			isErr:            true,
+			expectedSnapshot: []string{"t1"},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			var snapshots []string
+			hook := func(taskID string, values map[string]interface{}) {
+				snapshots = append(snapshots, taskID)
+				objectName := util.GetNestedString(values, string(v1alpha1.TaskResultTLP), taskID, string(v1alpha1.ObjectNameTRTP))
+				if objectName != taskID+"-obj" {
+					t.Errorf("failed to test values snapshot: expected object name '%s-obj': actual '%s'", taskID, objectName)
+				}
+				// mutating the snapshot should not affect the run
+				util.SetNestedField(values, "mutated", string(v1alpha1.TaskResultTLP), "t1", string(v1alpha1.ObjectNameTRTP))
+			}
+
+			r := NewTaskGroupRunner(WithValuesSnapshotHook(hook))
+			for idx, post := range mock.posts {
+				r.AddRunTask(fakeCommandRunTask([]string{"t1", "t2"}[idx], post))
+			}
+			r.SetOutputTask(&v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{
+				Meta: "id: o1\nkind: Command\naction: output",
+				Task: `{{ nestedString .TaskResult "t1" "objectName" }}`,
+			}})
+
+			output, err := r.Run(context.Background(), fakeTemplateValues())
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test values snapshot: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+			if !reflect.DeepEqual(snapshots, mock.expectedSnapshot) {
+				t.Fatalf("failed to test values snapshot: expected snapshots '%v': actual '%v'", mock.expectedSnapshot, snapshots)
+			}
+			if !mock.isErr && string(output) != "t1-obj" {
+				t.Fatalf("failed to test values snapshot: expected output 't1-obj': actual '%s'", output)
+			}
+		})
+	}
+}
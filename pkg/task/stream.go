@@ -0,0 +1,112 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+)
+
+// TaskUpdate represents the completion of a task of a streamed run. The last
+// update of the stream represents the completion of the run.
+type TaskUpdate struct {
+	// TaskID is the identity of the task; is not set for the last update
+	TaskID string
+	// Status of the task or of the run if this is the last update
+	Status TaskStatus
+	// Output is the partial output i.e. yaml of the task's result. It is
+	// the runner's output if this is the last update.
+	Output []byte
+	// Err is the error if any that resulted from the task or from the run if
+	// this is the last update
+	Err error
+	// Final flags the last update of the stream
+	Final bool
+}
+
+// RunStream runs all the tasks similar to Run. It returns a channel that
+// receives an update as each task completes. The output & error of the run
+// are sent as the last update after which the channel is closed.
+//
+// NOTE:
+//  Channel is buffered to hold all the updates of a run. Hence the runner is
+// not blocked even if the channel is drained only after the run completes.
+//
+// NOTE:
+//  values is mutated similar to Run
+func (m *TaskGroupRunner) RunStream(values map[string]interface{}) (<-chan TaskUpdate, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.updateCh != nil {
+		return nil, fmt.Errorf("failed to stream run: runner is already streaming a run")
+	}
+
+	// one update per task & the last update
+	ch := make(chan TaskUpdate, len(m.allTasks)+1)
+	m.updateCh = ch
+
+	go func() {
+		output, err := m.Run(context.Background(), values)
+
+		m.mutex.Lock()
+		m.updateCh = nil
+		m.mutex.Unlock()
+
+		status := TaskSucceeded
+		if err != nil {
+			status = TaskFailed
+		}
+		ch <- TaskUpdate{Status: status, Output: output, Err: err, Final: true}
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+// isStreamed flags if the run of this runner is being streamed
+func (m *TaskGroupRunner) isStreamed() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.updateCh != nil
+}
+
+// sendUpdate sends an update of the task with the provided identity if the
+// run is being streamed. The provided task result is sent as the task's
+// partial output.
+func (m *TaskGroupRunner) sendUpdate(identity string, status TaskStatus, result map[string]interface{}, err error) {
+	m.mutex.Lock()
+	ch := m.updateCh
+	m.mutex.Unlock()
+
+	if ch == nil {
+		return
+	}
+
+	update := TaskUpdate{TaskID: identity, Status: status, Err: err}
+	if len(result) != 0 {
+		output, errMarshal := yaml.Marshal(result)
+		if errMarshal != nil {
+			m.logger().Error(errMarshal, "failed to marshal result of task", "task", identity)
+		}
+		update.Output = output
+	}
+	ch <- update
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+func TestRunStream(t *testing.T) {
+	tests := map[string]struct {
+		runtasks         []*v1alpha1.RunTask
+		expectedStatuses []string
+		expectedOutput   string
+		isErr            bool
+	}{
+		"run stream - +ve test case - all tasks succeed": {
+			runtasks: []*v1alpha1.RunTask{
+				fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`),
+				fakeCommandRunTask("t2", `{{- "t2-obj" | saveAs "t2.objectName" .TaskResult | noop -}}`),
+			},
+			expectedStatuses: []string{"t1=Succeeded", "t2=Succeeded", "=Succeeded"},
+			expectedOutput:   "t1-obj",
+		},
+		"run stream - -ve test case - task fails": {
+			runtasks: []*v1alpha1.RunTask{
+				fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`),
+				fakeCommandRunTask("t2", `{{- fail "t2 failed" -}}`),
+				fakeCommandRunTask("t3", `{{- "t3-obj" | saveAs "t3.objectName" .TaskResult | noop -}}`),
+			},
+			expectedStatuses: []string{"t1=Succeeded", "t2=Failed", "=Failed"},
+			isErr:            true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.AddRunTasks(mock.runtasks)
+			r.SetOutputTask(&v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{
+				Meta: "id: o1\nkind: Command\naction: output",
+				Task: `{{ nestedString .TaskResult "t1" "objectName" }}`,
+			}})
+
+			updates, err := r.RunStream(fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test run stream: expected 'no error': actual '%s'", err)
+			}
+
+			var statuses []string
+			var last TaskUpdate
+			for u := range updates {
+				statuses = append(statuses, u.TaskID+"="+string(u.Status))
+				if !u.Final && u.Status == TaskSucceeded && !strings.Contains(string(u.Output), u.TaskID+"-obj") {
+					t.Fatalf("failed to test run stream: expected partial output of task '%s': actual '%s'", u.TaskID, u.Output)
+				}
+				last = u
+			}
+
+			if !reflect.DeepEqual(statuses, mock.expectedStatuses) {
+				t.Fatalf("failed to test run stream: expected updates '%v': actual '%v'", mock.expectedStatuses, statuses)
+			}
+			if !last.Final {
+				t.Fatalf("failed to test run stream: expected last update to be final")
+			}
+			if mock.isErr != (last.Err != nil) {
+				t.Fatalf("failed to test run stream: expected error '%t': actual '%v'", mock.isErr, last.Err)
+			}
+			if string(last.Output) != mock.expectedOutput {
+				t.Fatalf("failed to test run stream: expected output '%s': actual '%s'", mock.expectedOutput, last.Output)
+			}
+		})
+	}
+}
@@ -17,17 +17,18 @@ limitations under the License.
 package task
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
-	"github.com/golang/glog"
 	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
 
 	m_k8s_client "github.com/openebs/maya/pkg/client/k8s"
 	m_k8s "github.com/openebs/maya/pkg/k8s"
 	"github.com/openebs/maya/pkg/template"
 	"github.com/openebs/maya/pkg/util"
+	"github.com/pkg/errors"
 	api_apps_v1beta1 "k8s.io/api/apps/v1beta1"
 	api_core_v1 "k8s.io/api/core/v1"
 	api_extn_v1beta1 "k8s.io/api/extensions/v1beta1"
@@ -52,6 +53,10 @@ type taskExecutor struct {
 	// executed
 	templateValues map[string]interface{}
 
+	// globalValues are the template values shared by the tasks; is set only
+	// if templateValues are layered with this task's local variables
+	globalValues map[string]interface{}
+
 	// metaTaskExec is the instance to be used to execute meta
 	// operations on this task
 	metaTaskExec *metaTaskExecutor
@@ -59,19 +64,77 @@ type taskExecutor struct {
 	// runtask is the specifications that determine a task & operations associated
 	// with it
 	runtask *v1alpha1.RunTask
+
+	// timeout is the maximum duration this task is allowed to execute; there
+	// is no timeout if this is not set
+	timeout time.Duration
+
+	// retries is the number of times this task was re-executed due to
+	// retryable errors
+	retries int
+
+	// defaultRetryPolicy is the retry policy used if this task does not set
+	// its own retry policy; is optional
+	defaultRetryPolicy v1alpha1.RetryPolicy
+
+	// renderedTask is the task yaml after templating; is set once the task
+	// yaml is templated during execution
+	renderedTask string
+
+	// resultCache caches the results of read only tasks; is optional
+	resultCache ResultCache
+
+	// funcs are the template functions available to this task's templates
+	// in addition to the ones supported by the template library; is optional
+	funcs template.FuncMap
+
+	// existed flags if this put task was skipped since its object already
+	// existed
+	existed bool
+
+	// ctx is the context this task is executed with; is optional
+	ctx context.Context
+
+	// rollbackOf is the identity of the task whose objects are deleted by
+	// this rollback task; is set only for rollback tasks
+	rollbackOf string
+
+	// log logs the messages of this task; defaults to the logger of this
+	// package
+	log Logger
 }
 
 // newTaskExecutor returns a new instance of taskExecutor
 func newTaskExecutor(runtask *v1alpha1.RunTask, values map[string]interface{}) (*taskExecutor, error) {
-	mte, err := newMetaTaskExecutor(runtask.Spec.Meta, values)
+	return newTaskExecutorWithFuncs(runtask, values, nil)
+}
+
+// newTaskExecutorWithFuncs returns a new instance of taskExecutor whose
+// templates can invoke the provided template functions
+func newTaskExecutorWithFuncs(runtask *v1alpha1.RunTask, values map[string]interface{}, funcs template.FuncMap) (*taskExecutor, error) {
+	err := verifyCapacity(runtask, values)
 	if err != nil {
 		return nil, err
 	}
 
+	mte, err := newMetaTaskExecutor(runtask.Spec.Meta, values, funcs)
+	if err != nil {
+		return nil, err
+	}
+
+	// timeout set in meta specifications overrides the one set in task
+	// specifications
+	timeout := mte.getTimeout()
+	if timeout <= 0 {
+		timeout = time.Duration(runtask.Spec.TimeoutSeconds) * time.Second
+	}
+
 	return &taskExecutor{
 		templateValues: values,
 		metaTaskExec:   mte,
 		runtask:        runtask,
+		timeout:        timeout,
+		funcs:          funcs,
 	}, nil
 }
 
@@ -80,6 +143,28 @@ func (m *taskExecutor) String() string {
 	return fmt.Sprintf("task with identity '%s' and with objectname '%s'", m.getTaskIdentity(), m.getTaskObjectName())
 }
 
+// context returns the context this task is executed with
+func (m *taskExecutor) context() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+// wait blocks for the provided duration. It returns an error if this task's
+// context is done before the duration elapses.
+func (m *taskExecutor) wait(d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-m.context().Done():
+		return errors.Wrapf(m.context().Err(), "failed to execute runtask '%s'", m.getTaskIdentity())
+	}
+}
+
 // getTaskIdentity gets the task identity
 func (m *taskExecutor) getTaskIdentity() string {
 	return m.metaTaskExec.getIdentity()
@@ -98,9 +183,32 @@ func (m *taskExecutor) getK8sClient() *m_k8s_client.K8sClient {
 // Output returns the result of templating this task's yaml
 //
 // This implements TaskOutputExecutor interface
+//
+// NOTE:
+//  Output is converted to the format set in meta specifications if any
 func (m *taskExecutor) Output() (output []byte, err error) {
-	output, err = template.AsTemplatedBytes("Output", m.runtask.Spec.Task, m.templateValues)
-	return
+	output, err = m.asTemplatedBytes("Output", m.runtask.Spec.Task)
+	if err != nil {
+		return
+	}
+	return formatOutput(output, m.metaTaskExec.getOutputFormat())
+}
+
+// renderTask returns the result of templating this task's yaml. The result
+// is recorded as this task's rendered yaml.
+func (m *taskExecutor) renderTask(context string) ([]byte, error) {
+	b, err := m.asTemplatedBytes(context, m.runtask.Spec.Task)
+	if err != nil {
+		return nil, err
+	}
+	m.renderedTask = string(b)
+	return b, nil
+}
+
+// asTemplatedBytes returns the result of templating the provided yaml
+// against this task's template values
+func (m *taskExecutor) asTemplatedBytes(context, yml string) ([]byte, error) {
+	return template.AsTemplatedBytesWithFuncs(context, yml, m.templateValues, m.funcs)
 }
 
 // getTaskResultNotFoundError fetches the NotFound error if any from this
@@ -189,6 +297,11 @@ func (m *taskExecutor) repeatWith() (err error) {
 	)
 
 	for idx := 0; idx < repeats; idx++ {
+		if ctxErr := m.context().Err(); ctxErr != nil {
+			// stop repetition since the task's context is done
+			return errors.Wrapf(ctxErr, "failed to execute runtask '%s'", m.getTaskIdentity())
+		}
+
 		// fetch a new repeat meta task instance
 		rptMetaTaskExec, err = m.metaTaskExec.asRepeatInstance(idx)
 		if err != nil {
@@ -255,10 +368,12 @@ func (m *taskExecutor) retryOnVerificationError() (err error) {
 		err, _ = verifyErr.(*template.VerifyError)
 
 		if i != retryAttempts {
-			glog.Warningf("verify error was found during post runtask operations '%s': error '%+v': will retry task execution'%d'", m.getTaskIdentity(), err, i+1)
+			m.logger().Error(err, "verify error was found during post runtask operations: will retry", "task", m.getTaskIdentity(), "attempt", i+1)
 
 			// will retry after the specified interval
-			time.Sleep(interval)
+			if waitErr := m.wait(interval); waitErr != nil {
+				return waitErr
+			}
 		}
 	}
 
@@ -289,7 +404,7 @@ func (m *taskExecutor) postExecuteIt() (err error) {
 	}
 
 	// post runtask operation
-	_, err = template.AsTemplatedBytes("PostRun", m.runtask.Spec.PostRun, m.templateValues)
+	_, err = m.asTemplatedBytes("PostRun", m.runtask.Spec.PostRun)
 	if err != nil {
 		// return any un-handled runtime error
 		return
@@ -300,7 +415,7 @@ func (m *taskExecutor) postExecuteIt() (err error) {
 	// extracted from template values and thrown as VersionMismatchError
 	verMismatchErr := m.getTaskResultVersionMismatchError()
 	if verMismatchErr != nil {
-		glog.Warningf("version mismatch error during post runtask operations '%s': error '%+v'", m.getTaskIdentity(), verMismatchErr)
+		m.logger().Error(nil, "version mismatch error during post runtask operations", "task", m.getTaskIdentity(), "error", verMismatchErr)
 		err, _ = verMismatchErr.(*template.VersionMismatchError)
 		return
 	}
@@ -310,7 +425,7 @@ func (m *taskExecutor) postExecuteIt() (err error) {
 	// extracted and thrown as NotFoundError
 	notFoundErr := m.getTaskResultNotFoundError()
 	if notFoundErr != nil {
-		glog.Warningf("notfound error during post runtask operations '%s': error '%+v'", m.getTaskIdentity(), notFoundErr)
+		m.logger().Error(nil, "notfound error during post runtask operations", "task", m.getTaskIdentity(), "error", notFoundErr)
 		err, _ = notFoundErr.(*template.NotFoundError)
 		return
 	}
@@ -320,9 +435,18 @@ func (m *taskExecutor) postExecuteIt() (err error) {
 
 // ExecuteIt will execute the runtask based on its meta specs & task specs
 func (m *taskExecutor) ExecuteIt() (err error) {
+	// plugins do not make kubernetes API calls
+	if m.metaTaskExec.isPlugin() {
+		err = m.executePlugin()
+		if err != nil {
+			return
+		}
+		return m.afterExecuteIt()
+	}
+
 	if m.getK8sClient() == nil {
 		emsg := "failed to execute task: nil k8s client: verify if run namespace was available"
-		glog.Errorf(fmt.Sprintf("%s: metatask '%+v'", emsg, m.metaTaskExec.getMetaInfo()))
+		m.logger().Error(nil, emsg, "metatask", m.metaTaskExec.getMetaInfo())
 		err = fmt.Errorf("%s: task '%s'", emsg, m.getTaskIdentity())
 		return
 	}
@@ -332,6 +456,29 @@ func (m *taskExecutor) ExecuteIt() (err error) {
 		return m.postExecuteIt()
 	}
 
+	if m.metaTaskExec.isIfNotExists() {
+		m.existed, err = m.getExistingObject()
+		if err != nil {
+			return
+		}
+		if m.existed {
+			m.logger().Info("skipping task because object already exists", "task", m.getTaskIdentity(), "objectName", m.getTaskObjectName())
+			return m.afterExecuteIt()
+		}
+	}
+
+	var cacheKey string
+	cacheable := m.isCacheable()
+	if cacheable {
+		cacheKey, err = m.resultCacheKey()
+		if err != nil {
+			return
+		}
+		if m.getCachedResult(cacheKey) {
+			return m.afterExecuteIt()
+		}
+	}
+
 	if m.metaTaskExec.isPutExtnV1B1Deploy() {
 		err = m.putExtnV1B1Deploy()
 	} else if m.metaTaskExec.isPutAppsV1B1Deploy() {
@@ -342,6 +489,10 @@ func (m *taskExecutor) ExecuteIt() (err error) {
 		err = m.patchAppsV1B1Deploy()
 	} else if m.metaTaskExec.isPatchOEV1alpha1SPC() {
 		err = m.patchOEV1alpha1SPC()
+	} else if m.metaTaskExec.isPatchCASExtnV1B1Deploy() {
+		err = m.patchCASExtnV1B1Deploy()
+	} else if m.metaTaskExec.isPatchCASOEV1alpha1SPC() {
+		err = m.patchCASOEV1alpha1SPC()
 	} else if m.metaTaskExec.isPutCoreV1Service() {
 		err = m.putCoreV1Service()
 	} else if m.metaTaskExec.isDeleteExtnV1B1Deploy() {
@@ -380,6 +531,8 @@ func (m *taskExecutor) ExecuteIt() (err error) {
 		err = m.getStorageV1SC()
 	} else if m.metaTaskExec.isGetCoreV1PV() {
 		err = m.getCoreV1PV()
+	} else if m.metaTaskExec.isWatch() {
+		err = m.watchK8sResource()
 	} else {
 		err = fmt.Errorf("un-supported task operation: failed to execute task: '%+v'", m.metaTaskExec.getMetaInfo())
 	}
@@ -388,10 +541,51 @@ func (m *taskExecutor) ExecuteIt() (err error) {
 		return
 	}
 
+	if cacheable {
+		m.setCachedResult(cacheKey)
+	}
+
+	return m.afterExecuteIt()
+}
+
+// afterExecuteIt extracts the named outputs & runs the post operations after
+// a runtask is executed
+func (m *taskExecutor) afterExecuteIt() (err error) {
+	// extract the named outputs if any before running the post operations so
+	// that these outputs are available to the post operations
+	err = m.extractOutputs()
+	if err != nil {
+		return
+	}
+
 	// run the post operations after a runtask is executed
 	return m.postExecuteIt()
 }
 
+// extractOutputs extracts the named outputs from the result of this task's
+// execution & stores each of them at .TaskResult.<taskID>.<outputName>
+func (m *taskExecutor) extractOutputs() (err error) {
+	if m.runtask == nil || len(m.runtask.Spec.Outputs) == 0 {
+		// nothing needs to be done
+		return
+	}
+
+	result, _ := util.GetNestedField(m.templateValues, string(v1alpha1.CurrentJSONResultTLP)).([]byte)
+	for _, o := range m.runtask.Spec.Outputs {
+		name := strings.TrimSpace(o.Name)
+		if len(name) == 0 {
+			return fmt.Errorf("failed to extract outputs of task '%s': output name is missing", m.getTaskIdentity())
+		}
+
+		value, err := template.NewJsonQuery(name, result, o.JSONPath).Query()
+		if err != nil {
+			return fmt.Errorf("failed to extract output '%s' of task '%s': %s", name, m.getTaskIdentity(), err)
+		}
+		util.SetNestedField(m.templateValues, value, string(v1alpha1.TaskResultTLP), m.getTaskIdentity(), name)
+	}
+	return
+}
+
 // asRollbackInstance will provide the rollback instance w.r.t this task's instance
 func (m *taskExecutor) asRollbackInstance(objectName string) (*taskExecutor, error) {
 	mte, willRollback, err := m.metaTaskExec.asRollbackInstance(objectName)
@@ -408,16 +602,82 @@ func (m *taskExecutor) asRollbackInstance(objectName string) (*taskExecutor, err
 	// other words no need of task yaml template & values
 	return &taskExecutor{
 		metaTaskExec: mte,
+		rollbackOf:   m.getTaskIdentity(),
+		log:          m.log,
 	}, nil
 }
 
+// Validate verifies if this task can be executed without executing it
+//
+// NOTE:
+//  Templates of this task if any are parsed to report syntax errors & its
+// rollback if any is verified. A rollback task does not have templates. Hence its meta specifications are
+// verified to have an object name & a delete action that is supported for
+// its kind.
+func (m *taskExecutor) Validate() error {
+	if m.runtask != nil {
+		templates := []struct{ context, yml string }{
+			{"MetaTaskSpec", m.runtask.Spec.Meta},
+			{"RunTask", m.runtask.Spec.Task},
+			{"PostRun", m.runtask.Spec.PostRun},
+		}
+		for _, t := range templates {
+			err := template.ValidateSyntaxWithFuncs(t.context, t.yml, m.funcs)
+			if err != nil {
+				return errors.Wrapf(err, "invalid task '%s': invalid %s template", m.getTaskIdentity(), t.context)
+			}
+		}
+		return m.validateRollback()
+	}
+
+	if len(m.rollbackOf) == 0 {
+		// not a rollback task
+		return nil
+	}
+
+	if len(m.getTaskObjectName()) == 0 {
+		return fmt.Errorf("invalid rollback of task '%s': object name is missing", m.rollbackOf)
+	}
+
+	if !m.metaTaskExec.isSupportedDelete() {
+		meta := m.metaTaskExec.getMetaInfo()
+		return fmt.Errorf("invalid rollback of task '%s': delete is not supported for kind '%s' with api version '%s'", m.rollbackOf, meta.Kind, meta.APIVersion)
+	}
+	return nil
+}
+
+// validateRollback verifies if the rollback of this task can be executed
+//
+// NOTE:
+//  This is verified before this task is executed since the objects created
+// by this task can not be rolled back otherwise.
+func (m *taskExecutor) validateRollback() error {
+	if !m.metaTaskExec.isPut() {
+		// this task does not need a rollback
+		return nil
+	}
+
+	rbSpec, i, err := getRollbackMetaInstances(m.metaTaskExec.metaTask, "")
+	if err != nil {
+		return errors.Wrapf(err, "invalid rollback of task '%s'", m.getTaskIdentity())
+	}
+
+	rollback := &metaTaskExecutor{metaTask: rbSpec, identifier: i}
+	if !rollback.isSupportedDelete() {
+		meta := rollback.getMetaInfo()
+		return fmt.Errorf("invalid rollback of task '%s': delete is not supported for kind '%s' with api version '%s'", m.getTaskIdentity(), meta.Kind, meta.APIVersion)
+	}
+	return nil
+}
+
 // asAppsV1B1Deploy generates a K8s Deployment object
 // out of the embedded yaml
 func (m *taskExecutor) asAppsV1B1Deploy() (*api_apps_v1beta1.Deployment, error) {
-	d, err := m_k8s.NewDeploymentYml("AppsV1B1Deploy", m.runtask.Spec.Task, m.templateValues)
+	b, err := m.renderTask("AppsV1B1Deploy")
 	if err != nil {
 		return nil, err
 	}
+	d := &m_k8s.DeploymentYml{YmlInBytes: b}
 
 	return d.AsAppsV1B1Deployment()
 }
@@ -425,10 +685,11 @@ func (m *taskExecutor) asAppsV1B1Deploy() (*api_apps_v1beta1.Deployment, error)
 // asExtnV1B1Deploy generates a K8s Deployment object
 // out of the embedded yaml
 func (m *taskExecutor) asExtnV1B1Deploy() (*api_extn_v1beta1.Deployment, error) {
-	d, err := m_k8s.NewDeploymentYml("ExtnV1B11Deploy", m.runtask.Spec.Task, m.templateValues)
+	b, err := m.renderTask("ExtnV1B11Deploy")
 	if err != nil {
 		return nil, err
 	}
+	d := &m_k8s.DeploymentYml{YmlInBytes: b}
 
 	return d.AsExtnV1B1Deployment()
 }
@@ -436,10 +697,11 @@ func (m *taskExecutor) asExtnV1B1Deploy() (*api_extn_v1beta1.Deployment, error)
 // asCStorPool generates a CstorPool object
 // out of the embedded yaml
 func (m *taskExecutor) asCStorPool() (*v1alpha1.CStorPool, error) {
-	d, err := m_k8s.NewCStorPoolYml("CStorPool", m.runtask.Spec.Task, m.templateValues)
+	b, err := m.renderTask("CStorPool")
 	if err != nil {
 		return nil, err
 	}
+	d := &m_k8s.CStorPoolYml{YmlInBytes: b}
 
 	return d.AsCStorPoolYml()
 }
@@ -447,10 +709,11 @@ func (m *taskExecutor) asCStorPool() (*v1alpha1.CStorPool, error) {
 // asStoragePool generates a StoragePool object
 // out of the embedded yaml
 func (m *taskExecutor) asStoragePool() (*v1alpha1.StoragePool, error) {
-	d, err := m_k8s.NewStoragePoolYml("StoragePool", m.runtask.Spec.Task, m.templateValues)
+	b, err := m.renderTask("StoragePool")
 	if err != nil {
 		return nil, err
 	}
+	d := &m_k8s.StoragePoolYml{YmlInBytes: b}
 
 	return d.AsStoragePoolYml()
 }
@@ -458,10 +721,11 @@ func (m *taskExecutor) asStoragePool() (*v1alpha1.StoragePool, error) {
 // asCStorVolume generates a CstorVolume object
 // out of the embedded yaml
 func (m *taskExecutor) asCStorVolume() (*v1alpha1.CStorVolume, error) {
-	d, err := m_k8s.NewCStorVolumeYml("CstorVolume", m.runtask.Spec.Task, m.templateValues)
+	b, err := m.renderTask("CstorVolume")
 	if err != nil {
 		return nil, err
 	}
+	d := &m_k8s.CStorVolumeYml{YmlInBytes: b}
 
 	return d.AsCStorVolumeYml()
 }
@@ -469,10 +733,11 @@ func (m *taskExecutor) asCStorVolume() (*v1alpha1.CStorVolume, error) {
 // asCstorVolumeReplica generates a CStorVolumeReplica object
 // out of the embedded yaml
 func (m *taskExecutor) asCstorVolumeReplica() (*v1alpha1.CStorVolumeReplica, error) {
-	d, err := m_k8s.NewCStorVolumeReplicaYml("CstorVolumeReplica", m.runtask.Spec.Task, m.templateValues)
+	b, err := m.renderTask("CstorVolumeReplica")
 	if err != nil {
 		return nil, err
 	}
+	d := &m_k8s.CStorVolumeReplicaYml{YmlInBytes: b}
 
 	return d.AsCStorVolumeReplicaYml()
 }
@@ -480,10 +745,11 @@ func (m *taskExecutor) asCstorVolumeReplica() (*v1alpha1.CStorVolumeReplica, err
 // asCoreV1Svc generates a K8s Service object
 // out of the embedded yaml
 func (m *taskExecutor) asCoreV1Svc() (*api_core_v1.Service, error) {
-	s, err := m_k8s.NewServiceYml("CoreV1Svc", m.runtask.Spec.Task, m.templateValues)
+	b, err := m.renderTask("CoreV1Svc")
 	if err != nil {
 		return nil, err
 	}
+	s := &m_k8s.ServiceYml{YmlInBytes: b}
 
 	return s.AsCoreV1Service()
 }
@@ -522,10 +788,22 @@ func (m *taskExecutor) putExtnV1B1Deploy() (err error) {
 	return
 }
 
+// asPatch returns the patch of this task. The patch type is the one flagged
+// by the task's action e.g. patch-json or else the one set in the task
+// specifications.
+func (m *taskExecutor) asPatch(context string) (patch TaskPatch, err error) {
+	b, err := m.renderTask(context)
+	if err != nil {
+		return
+	}
+
+	return toTaskPatch(b, m.metaTaskExec.getPatchType())
+}
+
 // patchSPC will patch a SPC object in a kubernetes cluster.
 // The patch specifications as configured in the RunTask
 func (m *taskExecutor) patchOEV1alpha1SPC() (err error) {
-	patch, err := asTaskPatch("patchSPC", m.runtask.Spec.Task, m.templateValues)
+	patch, err := m.asPatch("patchSPC")
 	if err != nil {
 		return
 	}
@@ -560,7 +838,7 @@ func (m *taskExecutor) patchAppsV1B1Deploy() (err error) {
 // patchExtnV1B1Deploy will patch a Deployment where the patch specifications
 // are configured in the RunTask
 func (m *taskExecutor) patchExtnV1B1Deploy() (err error) {
-	patch, err := asTaskPatch("ExtnV1B1DeployPatch", m.runtask.Spec.Task, m.templateValues)
+	patch, err := m.asPatch("ExtnV1B1DeployPatch")
 	if err != nil {
 		return
 	}
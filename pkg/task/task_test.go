@@ -17,7 +17,13 @@ limitations under the License.
 package task
 
 import (
+	"context"
 	"testing"
+	"time"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+	"github.com/pkg/errors"
 )
 
 // TODO
@@ -79,3 +85,154 @@ func TestRetryOnVerificationError(t *testing.T) {}
 
 // TODO
 func TestAsOutput(t *testing.T) {}
+
+func TestExtractOutputs(t *testing.T) {
+	tests := map[string]struct {
+		outputs  []v1alpha1.OutputSpec
+		expected map[string]string
+		isErr    bool
+	}{
+		"extract outputs - +ve test case - no outputs": {
+			expected: map[string]string{},
+		},
+		"extract outputs - +ve test case - multiple outputs": {
+			outputs: []v1alpha1.OutputSpec{
+				{Name: "names", JSONPath: "{.items[*].metadata.name}"},
+				{Name: "pools", JSONPath: "{.items[*].spec.pool}"},
+			},
+			expected: map[string]string{"names": "pv-1 pv-2", "pools": "pool-1 pool-2"},
+		},
+		"extract outputs - -ve test case - missing output name": {
+			outputs: []v1alpha1.OutputSpec{{JSONPath: "{.items[*].metadata.name}"}},
+			isErr:   true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			runtask := &v1alpha1.RunTask{}
+			runtask.Spec.Outputs = mock.outputs
+			values := fakeTemplateValues()
+			values[string(v1alpha1.CurrentJSONResultTLP)] = []byte(`{"items":[{"metadata":{"name":"pv-1"},"spec":{"pool":"pool-1"}},{"metadata":{"name":"pv-2"},"spec":{"pool":"pool-2"}}]}`)
+			te := &taskExecutor{
+				templateValues: values,
+				runtask:        runtask,
+				metaTaskExec: &metaTaskExecutor{
+					metaTask: MetaTaskSpec{MetaTaskIdentity: MetaTaskIdentity{Identity: "listpv"}},
+				},
+			}
+
+			err := te.extractOutputs()
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("failed to test extract outputs: expected 'error': actual 'no error'")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to test extract outputs: expected 'no error': actual '%s'", err)
+			}
+			for k, v := range mock.expected {
+				actual := util.GetNestedString(values, "TaskResult", "listpv", k)
+				if actual != v {
+					t.Fatalf("failed to test extract outputs: expected '%s' as '%s': actual '%s'", k, v, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	tests := map[string]struct {
+		isCancelled bool
+		isErr       bool
+	}{
+		"wait - +ve test case - active context":    {},
+		"wait - -ve test case - cancelled context": {isCancelled: true, isErr: true},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if mock.isCancelled {
+				cancel()
+			}
+			te := &taskExecutor{
+				ctx: ctx,
+				metaTaskExec: &metaTaskExecutor{
+					metaTask: MetaTaskSpec{MetaTaskIdentity: MetaTaskIdentity{Identity: "t1"}},
+				},
+			}
+
+			err := te.wait(10 * time.Millisecond)
+			if !mock.isErr {
+				if err != nil {
+					t.Fatalf("failed to test wait: expected 'no error': actual '%s'", err)
+				}
+				return
+			}
+			if errors.Cause(err) != context.Canceled {
+				t.Fatalf("failed to test wait: expected '%s': actual '%v'", context.Canceled, err)
+			}
+		})
+	}
+}
+
+func TestValidateTaskExecutor(t *testing.T) {
+	tests := map[string]struct {
+		meta       string
+		task       string
+		post       string
+		isRollback bool
+		isErr      bool
+	}{
+		"validate task executor - +ve test case - valid templates": {
+			meta: "id: cmd\nkind: Command\naction: put",
+			post: `{{- "obj" | saveAs "cmd.objectName" .TaskResult | noop -}}`,
+		},
+		"validate task executor - -ve test case - malformed post template": {
+			meta:  "id: cmd\nkind: Command\naction: put",
+			post:  `{{- "obj" | saveAs "cmd.objectName" .TaskResult | noop`,
+			isErr: true,
+		},
+		"validate task executor - -ve test case - malformed task template": {
+			meta:  "id: svc\napiVersion: v1\nkind: Service\naction: put\nrunNamespace: default",
+			task:  "apiVersion: v1\nkind: Service\nmetadata:\n  name: {{ .Volume.owner }",
+			isErr: true,
+		},
+		"validate task executor - +ve test case - rollback of a service": {
+			meta:       "id: svc\napiVersion: v1\nkind: Service\naction: put\nrunNamespace: default",
+			isRollback: true,
+		},
+		"validate task executor - -ve test case - rollback of an unsupported kind": {
+			meta:       "id: pvc\napiVersion: v1\nkind: PersistentVolumeClaim\naction: put\nrunNamespace: default",
+			isRollback: true,
+			isErr:      true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			runtask := &v1alpha1.RunTask{}
+			runtask.Spec.Meta = mock.meta
+			runtask.Spec.Task = mock.task
+			runtask.Spec.PostRun = mock.post
+			te, err := newTaskExecutor(runtask, fakeTemplateValues())
+			if err != nil {
+				t.Fatalf("failed to test validate task executor: expected 'no error': actual '%s'", err)
+			}
+
+			if mock.isRollback {
+				// planning a rollback validates the rollback task
+				r := NewTaskGroupRunner()
+				err = r.planForRollback(te, []string{"obj-1"})
+			} else {
+				err = te.Validate()
+			}
+			if mock.isErr != (err != nil) {
+				t.Fatalf("failed to test validate task executor: expected error '%t': actual '%v'", mock.isErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testing provides fakes of the task package that are useful while
+// testing the callers of task group runner.
+package testing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/openebs/maya/pkg/task"
+)
+
+// MatchFn matches the template values a runner is run with
+type MatchFn func(values map[string]interface{}) bool
+
+// TestingT is the subset of testing.T used by the assertions of this
+// package
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// RunCall is a recorded call to Run of a fake runner
+type RunCall struct {
+	// Values are the template values the runner was run with
+	Values map[string]interface{}
+	// Output is the output returned by the runner
+	Output []byte
+	// Err is the error returned by the runner
+	Err error
+}
+
+// runExpectation is an expected call to Run along with its pre-canned
+// response
+type runExpectation struct {
+	match  MatchFn
+	output []byte
+	err    error
+	// met flags if this expectation was matched by a call to Run
+	met bool
+}
+
+// FakeTaskGroupRunner is a fake task group runner. It records the calls to
+// Run & returns the responses set via ExpectRun instead of running any task.
+//
+// NOTE:
+//  Each expectation is met by a single call to Run. Expectations are matched
+// in the order they were set.
+type FakeTaskGroupRunner struct {
+	mutex        sync.Mutex
+	expectations []*runExpectation
+	calls        []RunCall
+}
+
+// FakeTaskGroupRunner implements task.GroupRunner
+var _ task.GroupRunner = &FakeTaskGroupRunner{}
+
+// NewFakeTaskGroupRunner returns a new instance of FakeTaskGroupRunner
+func NewFakeTaskGroupRunner() *FakeTaskGroupRunner {
+	return &FakeTaskGroupRunner{}
+}
+
+// ExpectRun sets an expected call to Run whose template values match the
+// provided input. The provided output & error are returned by this call. A
+// nil input matches any template values.
+func (f *FakeTaskGroupRunner) ExpectRun(input MatchFn, output []byte, err error) *FakeTaskGroupRunner {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.expectations = append(f.expectations, &runExpectation{match: input, output: output, err: err})
+	return f
+}
+
+// Run returns the response of the first expectation that is not yet met &
+// matches the provided template values. An error is returned if there is no
+// such expectation.
+func (f *FakeTaskGroupRunner) Run(ctx context.Context, values map[string]interface{}) (output []byte, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	err = fmt.Errorf("unexpected run: no expectation matches the template values '%v'", values)
+	for _, e := range f.expectations {
+		if e.met || (e.match != nil && !e.match(values)) {
+			continue
+		}
+		e.met = true
+		output, err = e.output, e.err
+		break
+	}
+
+	f.calls = append(f.calls, RunCall{Values: values, Output: output, Err: err})
+	return
+}
+
+// Calls returns the recorded calls to Run in the order they were made
+func (f *FakeTaskGroupRunner) Calls() []RunCall {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return append([]RunCall(nil), f.calls...)
+}
+
+// AssertAllExpectationsMet reports an error via the provided testing
+// instance for each expectation that was not met. It returns true if all
+// the expectations were met.
+func (f *FakeTaskGroupRunner) AssertAllExpectationsMet(t TestingT) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	met := true
+	for idx, e := range f.expectations {
+		if !e.met {
+			t.Errorf("expected run at index '%d' was not met: calls '%d'", idx, len(f.calls))
+			met = false
+		}
+	}
+	return met
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeT records the errors reported by the assertions
+type fakeT struct {
+	errs []string
+}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errs = append(f.errs, fmt.Sprintf(format, args...))
+}
+
+// volumeIs matches the template values having the provided volume owner
+func volumeIs(owner string) MatchFn {
+	return func(values map[string]interface{}) bool {
+		volume, _ := values["Volume"].(map[string]interface{})
+		return volume["owner"] == owner
+	}
+}
+
+func TestFakeTaskGroupRunner(t *testing.T) {
+	tests := map[string]struct {
+		owners          []string
+		expectedOutputs []string
+		expectedErrs    []bool
+		expectedMet     bool
+	}{
+		"fake runner - +ve test case - all expectations are met": {
+			owners:          []string{"pvc-2", "pvc-1", "pvc-3"},
+			expectedOutputs: []string{"out-2", "out-1", "out-any"},
+			expectedErrs:    []bool{true, false, false},
+			expectedMet:     true,
+		},
+		"fake runner - -ve test case - expectations are not met": {
+			owners:          []string{"pvc-1"},
+			expectedOutputs: []string{"out-1"},
+			expectedErrs:    []bool{false},
+		},
+		"fake runner - -ve test case - unexpected run": {
+			owners:          []string{"pvc-1", "pvc-2", "pvc-3", "pvc-4"},
+			expectedOutputs: []string{"out-1", "out-2", "out-any", ""},
+			expectedErrs:    []bool{false, true, false, true},
+			expectedMet:     true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := NewFakeTaskGroupRunner().
+				ExpectRun(volumeIs("pvc-1"), []byte("out-1"), nil).
+				ExpectRun(volumeIs("pvc-2"), []byte("out-2"), errors.New("failed")).
+				ExpectRun(nil, []byte("out-any"), nil)
+
+			for idx, owner := range mock.owners {
+				values := map[string]interface{}{"Volume": map[string]interface{}{"owner": owner}}
+				output, err := f.Run(context.Background(), values)
+				if string(output) != mock.expectedOutputs[idx] {
+					t.Fatalf("failed to test fake runner: expected output '%s': actual '%s'", mock.expectedOutputs[idx], output)
+				}
+				if mock.expectedErrs[idx] != (err != nil) {
+					t.Fatalf("failed to test fake runner: expected error '%t': actual '%v'", mock.expectedErrs[idx], err)
+				}
+			}
+
+			if len(f.Calls()) != len(mock.owners) {
+				t.Fatalf("failed to test fake runner: expected calls '%d': actual '%d'", len(mock.owners), len(f.Calls()))
+			}
+			ft := &fakeT{}
+			if f.AssertAllExpectationsMet(ft) != mock.expectedMet || mock.expectedMet != (len(ft.errs) == 0) {
+				t.Fatalf("failed to test fake runner: expected all met '%t': actual errors '%v'", mock.expectedMet, ft.errs)
+			}
+		})
+	}
+}
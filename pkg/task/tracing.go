@@ -0,0 +1,141 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+)
+
+// Tracer starts the spans that trace the execution of a task group runner's
+// tasks, output, rollback & fallback
+//
+// NOTE:
+//  This abstracts the tracing library e.g. OpenTelemetry so that the runner
+// does not depend on any. Tracing is a no-op unless a tracer is set in the
+// context provided to Run.
+type Tracer interface {
+	// Start starts a span with the provided name. The span is a child of the
+	// span in the provided context if any. The returned context holds the
+	// started span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span represents a traced operation
+type Span interface {
+	// SetAttribute sets an attribute of this span
+	SetAttribute(key string, value interface{})
+	// RecordError records the error that resulted from the traced operation
+	RecordError(err error)
+	// End ends this span
+	End()
+}
+
+// taskSpanPrefix prefixes the identity of a task to name the span that
+// traces the task's execution
+//
+// e.g. runtask/cvolcreateputsvc
+const taskSpanPrefix = "runtask/"
+
+// tracerKey is the key against which a tracer is set in a context
+type tracerKey struct{}
+
+// WithTracer returns a copy of the provided context that holds the provided
+// tracer. The spans of a task group runner that is run with this context are
+// started by this tracer.
+func WithTracer(ctx context.Context, tracer Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, tracer)
+}
+
+// hasTracer flags if the provided context holds a tracer
+func hasTracer(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	tracer, ok := ctx.Value(tracerKey{}).(Tracer)
+	return ok && tracer != nil
+}
+
+// tracerFrom returns the tracer held by the provided context; a no-op tracer
+// is returned if there is none
+func tracerFrom(ctx context.Context) Tracer {
+	if ctx != nil {
+		if tracer, ok := ctx.Value(tracerKey{}).(Tracer); ok && tracer != nil {
+			return tracer
+		}
+	}
+	return noopTracer{}
+}
+
+// noopTracer is a tracer that does not trace
+type noopTracer struct{}
+
+// Start returns the provided context & a no-op span
+func (noopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// noopSpan is a span that does not record anything
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+
+func (noopSpan) RecordError(err error) {}
+
+func (noopSpan) End() {}
+
+// WithRunnerTracer sets the tracer that traces the task group runner's
+// tasks, output, rollback & fallback. This tracer is used only if the
+// context provided to Run does not hold a tracer set via WithTracer.
+func WithRunnerTracer(tracer Tracer) TaskGroupRunnerOption {
+	return func(m *TaskGroupRunner) {
+		m.tracer = tracer
+	}
+}
+
+// tracedContext returns the provided context with the runner's tracer set
+// if the provided context does not hold a tracer
+func (m *TaskGroupRunner) tracedContext(ctx context.Context) context.Context {
+	if m.tracer == nil || hasTracer(ctx) {
+		return ctx
+	}
+	return WithTracer(ctx, m.tracer)
+}
+
+// startTaskSpan starts the span that traces the execution of the provided
+// task
+func startTaskSpan(ctx context.Context, te *taskExecutor) (context.Context, Span) {
+	ctx, span := startSpan(ctx, taskSpanPrefix+te.getTaskIdentity())
+	meta := te.metaTaskExec.getMetaInfo()
+	span.SetAttribute("action", string(meta.Action))
+	span.SetAttribute("kind", meta.Kind)
+	span.SetAttribute("namespace", meta.RunNamespace)
+	return ctx, span
+}
+
+// startSpan starts a span with the provided name using the tracer held by
+// the provided context
+func startSpan(ctx context.Context, name string) (context.Context, Span) {
+	return tracerFrom(ctx).Start(ctx, name)
+}
+
+// endSpan records the provided error if any & ends the provided span
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
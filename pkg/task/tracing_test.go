@@ -0,0 +1,147 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+// fakeSpan records its attributes & error
+type fakeSpan struct {
+	name   string
+	parent string
+	attrs  map[string]interface{}
+	err    error
+	ended  bool
+}
+
+func (s *fakeSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+
+func (s *fakeSpan) RecordError(err error) { s.err = err }
+
+func (s *fakeSpan) End() { s.ended = true }
+
+// fakeSpanKey is the key against which the current fake span is set in a
+// context
+type fakeSpanKey struct{}
+
+// fakeTracer records the started spans
+type fakeTracer struct {
+	mutex sync.Mutex
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	span := &fakeSpan{name: name, attrs: map[string]interface{}{}}
+	if parent, ok := ctx.Value(fakeSpanKey{}).(*fakeSpan); ok {
+		span.parent = parent.name
+	}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, fakeSpanKey{}, span), span
+}
+
+func TestTracing(t *testing.T) {
+	tracer := &fakeTracer{}
+	ctx, _ := tracer.Start(WithTracer(context.Background(), tracer), "caller")
+
+	r := NewTaskGroupRunner()
+	r.AddRunTasks([]*v1alpha1.RunTask{
+		fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`),
+		fakeCommandRunTask("t2", `{{- fail "t2 failed" -}}`),
+	})
+	r.Run(ctx, fakeTemplateValues())
+
+	var names []string
+	for _, span := range tracer.spans {
+		names = append(names, span.name)
+		if span.name != "caller" && !span.ended {
+			t.Fatalf("failed to test tracing: expected span '%s' to be ended", span.name)
+		}
+		if span.name != "caller" && span.parent != "caller" {
+			t.Fatalf("failed to test tracing: expected span '%s' to be a child of 'caller': actual parent '%s'", span.name, span.parent)
+		}
+	}
+	expected := []string{"caller", "runtask/t1", "runtask/t2", "rollback"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("failed to test tracing: expected spans '%v': actual '%v'", expected, names)
+	}
+
+	if objectNames := tracer.spans[1].attrs["objectNames"]; !reflect.DeepEqual(objectNames, []string{"t1-obj"}) {
+		t.Fatalf("failed to test tracing: expected object names '[t1-obj]': actual '%v'", objectNames)
+	}
+	if action, kind := tracer.spans[1].attrs["action"], tracer.spans[1].attrs["kind"]; action != "put" || kind != "Command" {
+		t.Fatalf("failed to test tracing: expected action 'put' & kind 'Command': actual '%v' & '%v'", action, kind)
+	}
+	if tracer.spans[1].err != nil || tracer.spans[2].err == nil {
+		t.Fatalf("failed to test tracing: expected error to be recorded only for 't2': actual '%v' & '%v'", tracer.spans[1].err, tracer.spans[2].err)
+	}
+}
+
+func TestRunnerTracer(t *testing.T) {
+	tests := map[string]struct {
+		ctxTracer      bool
+		expectedRunner int
+		expectedCtx    int
+	}{
+		"runner tracer - +ve test case - runner tracer is used": {
+			// spans of the task & the output
+			expectedRunner: 2,
+		},
+		"runner tracer - +ve test case - context tracer takes precedence": {
+			ctxTracer:   true,
+			expectedCtx: 2,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			runnerTracer, ctxTracer := &fakeTracer{}, &fakeTracer{}
+			ctx := context.Background()
+			if mock.ctxTracer {
+				ctx = WithTracer(ctx, ctxTracer)
+			}
+
+			r := NewTaskGroupRunner(WithRunnerTracer(runnerTracer))
+			r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+			r.Run(ctx, fakeTemplateValues())
+
+			if len(runnerTracer.spans) != mock.expectedRunner || len(ctxTracer.spans) != mock.expectedCtx {
+				t.Fatalf("failed to test runner tracer: expected '%d' runner & '%d' context spans: actual '%d' & '%d'", mock.expectedRunner, mock.expectedCtx, len(runnerTracer.spans), len(ctxTracer.spans))
+			}
+		})
+	}
+}
+
+func TestNoopTracer(t *testing.T) {
+	ctx := context.Background()
+	actual, span := startSpan(ctx, "t1")
+	if actual != ctx {
+		t.Fatalf("failed to test noop tracer: expected the provided context to be returned")
+	}
+	if _, ok := span.(noopSpan); !ok {
+		t.Fatalf("failed to test noop tracer: expected 'noop span': actual '%T'", span)
+	}
+	endSpan(span, nil)
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/hashicorp/go-multierror"
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// Validate verifies all the tasks of this runner without executing any of
+// them. It verifies if there are tasks to run, if each task has meta
+// specifications & if there is an output task when output is expected. It
+// then renders the meta specifications of each task, verifies the
+// uniqueness of task identities, verifies if the tasks refer to the results
+// of only the tasks executed before them, verifies the output task's
+// specifications & verifies if the fallback templates can be resolved.
+// Errors of all the failed verifications are returned.
+//
+// NOTE:
+//  Meta specifications are rendered against a copy of the provided template
+// values. Results of the tasks are not available while rendering since the
+// tasks are not executed.
+func (m *TaskGroupRunner) Validate(values map[string]interface{}) error {
+	return m.validate(values, true)
+}
+
+// validate verifies the tasks of this runner. Only the verifications that
+// do not need the meta specifications to be rendered are done if render is
+// not set. These are cheap enough to be done at the start of every run.
+//
+// NOTE:
+//  Uniqueness is verified only for the identities that are not templated
+// if render is not set
+func (m *TaskGroupRunner) validate(values map[string]interface{}, render bool) error {
+	var errs *multierror.Error
+	if len(m.allTasks) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("invalid runner: no runtasks found"))
+	}
+	if m.outputExpected && len(m.outputTasks) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("invalid runner: output is expected but no output task found"))
+	}
+
+	if render {
+		values = util.DeepCopyMap(values)
+		m.applySeededResults(values)
+	}
+
+	ids := map[string]string{}
+	// positions maps the task identities to their positions in the sequence
+	positions := map[string]int{}
+	for idx, runtask := range m.allTasks {
+		if len(runtask.Spec.Meta) == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("invalid runtask '%s': nil meta task specs found", runtask.Name))
+			continue
+		}
+
+		var identity string
+		if render {
+			mts, _, _, err := getMetaInstances(runtask.Spec.Meta, values, m.templateFuncs)
+			if err != nil {
+				errs = multierror.Append(errs, errors.Wrapf(err, "invalid runtask '%s'", runtask.Name))
+				continue
+			}
+			identity = mts.Identity
+		} else {
+			identity = literalTaskID(runtask.Spec.Meta)
+			if len(identity) == 0 {
+				// identity is known only after rendering
+				continue
+			}
+		}
+
+		if m.isSeeded(identity) {
+			errs = multierror.Append(errs, fmt.Errorf("invalid runtask '%s': duplicate id '%s': id is reserved by a seeded task result", runtask.Name, identity))
+			continue
+		}
+
+		id := strings.ToLower(identity)
+		if name, found := ids[id]; found {
+			errs = multierror.Append(errs, fmt.Errorf("invalid runtask '%s': duplicate id '%s': id is already used by runtask '%s'", runtask.Name, identity, name))
+			continue
+		}
+		ids[id] = runtask.Name
+		positions[id] = idx
+	}
+
+	if !render {
+		return errs.ErrorOrNil()
+	}
+
+	// order of execution is not the sequence of the tasks if there are
+	// dependencies
+	for idx := 0; idx < len(m.allTasks) && len(m.dependencies) == 0; idx++ {
+		for _, err := range verifyTaskResultRefs(m.allTasks[idx], idx, positions) {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	if len(m.dependencies) != 0 && errs.ErrorOrNil() == nil {
+		_, err := m.dependencyStages(values)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	for _, outputTask := range m.outputTasks {
+		if len(outputTask.Spec.Meta) == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("invalid output task '%s': nil meta task specs found", outputTask.Name))
+		}
+		if len(outputTask.Spec.Task) == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("invalid output task '%s': nil task specs found", outputTask.Name))
+		}
+	}
+
+	resolve := m.resolveFallbackFn
+	if resolve == nil {
+		resolve = resolveFallbackTemplate
+	}
+	for _, castemplate := range m.fallbackTemplates {
+		err := resolve(castemplate)
+		if err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "invalid fallback '%s'", castemplate))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// taskResultRefPatterns match the references to a task's result in a
+// template i.e. .TaskResult.<taskID> or .TaskResult "<taskID>" as used with
+// functions like index & nestedString
+var taskResultRefPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\.` + string(v1alpha1.TaskResultTLP) + `\.([a-zA-Z0-9_]+)`),
+	regexp.MustCompile(`\.` + string(v1alpha1.TaskResultTLP) + `\s+"([^"]+)"`),
+}
+
+// taskResultRefs returns the identities of the tasks whose results are
+// referred to in the provided template
+func taskResultRefs(tpl string) (refs []string) {
+	for _, p := range taskResultRefPatterns {
+		for _, match := range p.FindAllStringSubmatch(tpl, -1) {
+			refs = append(refs, strings.ToLower(match[1]))
+		}
+	}
+	return
+}
+
+// verifyTaskResultRefs verifies if the provided task refers to the results
+// of the tasks that are executed before it. Meta & task specifications are
+// rendered before the task is executed & hence can not refer to the task's
+// own result. Post run specifications can refer to the task's own result.
+//
+// NOTE:
+//  Positions are as per the sequence the tasks were added in. References to
+// the identities not found in the provided positions are not verified since
+// these results may be seeded or may be produced by templated identities.
+func verifyTaskResultRefs(runtask *v1alpha1.RunTask, idx int, positions map[string]int) (errs []error) {
+	specs := []struct {
+		name     string
+		tpl      string
+		allowOwn bool
+	}{
+		{"meta", runtask.Spec.Meta, false},
+		{"task", runtask.Spec.Task, false},
+		{"post", runtask.Spec.PostRun, true},
+	}
+
+	for _, spec := range specs {
+		for _, ref := range taskResultRefs(spec.tpl) {
+			pos, found := positions[ref]
+			if !found {
+				continue
+			}
+			if pos == idx && !spec.allowOwn {
+				errs = append(errs, fmt.Errorf("invalid runtask '%s': %s specs refer to the task's own result '%s'", runtask.Name, spec.name, ref))
+			}
+			if pos > idx {
+				errs = append(errs, fmt.Errorf("invalid runtask '%s': %s specs refer to the result of task '%s' that is executed later", runtask.Name, spec.name, ref))
+			}
+		}
+	}
+	return
+}
+
+var (
+	// templateActionPattern matches the actions of a go template
+	templateActionPattern = regexp.MustCompile(`(?s){{.*?}}`)
+
+	// templateControlPattern matches the go template actions that decide
+	// which parts of the template are rendered
+	templateControlPattern = regexp.MustCompile(`{{-?\s*(if|else|range|with|end|define|template|block)\b`)
+)
+
+// templateActionPlaceholder replaces the template actions so that the
+// specifications can be parsed without rendering
+const templateActionPlaceholder = "__action__"
+
+// literalTaskID returns the identity set in the provided meta specifications
+// if the identity is not templated. An empty identity is returned otherwise.
+//
+// NOTE:
+//  An empty identity is returned as well if the meta specifications have
+// control actions e.g. if else blocks since these may decide the identity,
+// or if these are not valid yaml once the actions are replaced.
+func literalTaskID(meta string) string {
+	if templateControlPattern.MatchString(meta) {
+		return ""
+	}
+	var spec struct {
+		ID string `json:"id"`
+	}
+	err := yaml.Unmarshal([]byte(templateActionPattern.ReplaceAllString(meta, templateActionPlaceholder)), &spec)
+	if err != nil || strings.Contains(spec.ID, templateActionPlaceholder) {
+		return ""
+	}
+	return spec.ID
+}
+
+// resolveFallbackTemplate verifies if the provided fallback CAS Template is
+// available
+func resolveFallbackTemplate(castemplate string) error {
+	_, err := getFallbackCAST(castemplate)
+	return err
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		runtasks     []*v1alpha1.RunTask
+		output       *v1alpha1.RunTask
+		fallbacks    []string
+		expectedErrs []string
+	}{
+		"validate - +ve test case - valid tasks": {
+			runtasks:  []*v1alpha1.RunTask{fakeCommandRunTask("t1", ""), fakeCommandRunTask("t2", "")},
+			output:    &v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{Meta: "id: out\nkind: Command\naction: output", Task: "out"}},
+			fallbacks: []string{"cast-legacy"},
+		},
+		"validate - -ve test case - duplicate ids": {
+			runtasks:     []*v1alpha1.RunTask{fakeCommandRunTask("t1", ""), fakeCommandRunTask("t1", "")},
+			expectedErrs: []string{"duplicate id 't1'"},
+		},
+		"validate - -ve test case - invalid meta": {
+			runtasks:     []*v1alpha1.RunTask{{Spec: v1alpha1.RunTaskSpec{Meta: "id: t1\nkind: Service\napiVersion: v1"}}},
+			expectedErrs: []string{"'action' is required"},
+		},
+		"validate - -ve test case - bad template": {
+			runtasks:     []*v1alpha1.RunTask{{Spec: v1alpha1.RunTaskSpec{Meta: "id: {{ .Volume.owner"}}},
+			expectedErrs: []string{"MetaTaskSpec"},
+		},
+		"validate - -ve test case - output task without task specs": {
+			runtasks:     []*v1alpha1.RunTask{fakeCommandRunTask("t1", "")},
+			output:       &v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{Meta: "id: out\nkind: Command\naction: output"}},
+			expectedErrs: []string{"nil task specs"},
+		},
+		"validate - -ve test case - unresolvable fallback": {
+			runtasks:     []*v1alpha1.RunTask{fakeCommandRunTask("t1", "")},
+			fallbacks:    []string{"cast-legacy", "cast-missing"},
+			expectedErrs: []string{"invalid fallback 'cast-missing'"},
+		},
+		"validate - +ve test case - refers to earlier task result": {
+			runtasks: []*v1alpha1.RunTask{
+				fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`),
+				fakeCommandRunTask("t2", `{{- .TaskResult.t1.objectName | saveAs "t2.objectName" .TaskResult | noop -}}`),
+			},
+		},
+		"validate - +ve test case - post run refers to own result": {
+			runtasks: []*v1alpha1.RunTask{fakeCommandRunTask("t1", `{{- nestedString .TaskResult "t1" "objectName" | noop -}}`)},
+		},
+		"validate - -ve test case - meta refers to own result": {
+			runtasks:     []*v1alpha1.RunTask{{Spec: v1alpha1.RunTaskSpec{Meta: "id: t1\nkind: Command\naction: put\nobjectName: {{ .TaskResult.t1.objectName }}"}}},
+			expectedErrs: []string{"meta specs refer to the task's own result 't1'"},
+		},
+		"validate - -ve test case - task refers to later task result": {
+			runtasks: []*v1alpha1.RunTask{
+				{Spec: v1alpha1.RunTaskSpec{Meta: "id: t1\nkind: Command\naction: put", Task: `name: {{ nestedString .TaskResult "t2" "objectName" }}`}},
+				fakeCommandRunTask("t2", ""),
+			},
+			expectedErrs: []string{"task specs refer to the result of task 't2' that is executed later"},
+		},
+		"validate - -ve test case - post run refers to later task result": {
+			runtasks: []*v1alpha1.RunTask{
+				fakeCommandRunTask("t1", `{{- .TaskResult.t2.objectName | noop -}}`),
+				fakeCommandRunTask("t2", ""),
+			},
+			expectedErrs: []string{"post specs refer to the result of task 't2'"},
+		},
+		"validate - -ve test case - multiple errors": {
+			runtasks:     []*v1alpha1.RunTask{fakeCommandRunTask("t1", ""), fakeCommandRunTask("t1", "")},
+			fallbacks:    []string{"cast-missing"},
+			expectedErrs: []string{"duplicate id 't1'", "invalid fallback 'cast-missing'"},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.resolveFallbackFn = func(castemplate string) error {
+				if castemplate == "cast-missing" {
+					return fmt.Errorf("castemplate '%s' not found", castemplate)
+				}
+				return nil
+			}
+			r.allTasks = mock.runtasks
+			if mock.output != nil {
+				r.outputTasks = []*v1alpha1.RunTask{mock.output}
+			}
+			r.SetFallback(mock.fallbacks)
+
+			err := r.Validate(fakeTemplateValues())
+			if len(mock.expectedErrs) == 0 {
+				if err != nil {
+					t.Fatalf("failed to test validate: expected 'no error': actual '%s'", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("failed to test validate: expected 'error': actual 'no error'")
+			}
+			for _, e := range mock.expectedErrs {
+				if !strings.Contains(err.Error(), e) {
+					t.Fatalf("failed to test validate: expected error '%s': actual '%s'", e, err)
+				}
+			}
+		})
+	}
+}
+
+func TestStrictValidation(t *testing.T) {
+	r := NewTaskGroupRunner()
+	r.SetStrictValidation(true)
+	r.AddRunTask(fakeCommandRunTask("t1", `{{- "t1-obj" | saveAs "t1.objectName" .TaskResult | noop -}}`))
+	r.AddRunTask(fakeCommandRunTask("t1", ""))
+
+	result := r.RunWithReport(fakeTemplateValues())
+	if result.Err == nil {
+		t.Fatalf("failed to test strict validation: expected 'error': actual 'no error'")
+	}
+	if len(result.ExecutedTasks) != 0 {
+		t.Fatalf("failed to test strict validation: expected no tasks to be executed: actual '%d'", len(result.ExecutedTasks))
+	}
+}
+
+func TestValidateWithoutRender(t *testing.T) {
+	templatedTask := func(name string) *v1alpha1.RunTask {
+		r := &v1alpha1.RunTask{Spec: v1alpha1.RunTaskSpec{Meta: "id: {{ .Volume.owner }}\nkind: Command\naction: put"}}
+		r.Name = name
+		return r
+	}
+
+	tests := map[string]struct {
+		runtasks       []*v1alpha1.RunTask
+		seeded         string
+		output         bool
+		outputExpected bool
+		expectedErrs   []string
+	}{
+		"validate without render - +ve test case - valid tasks": {
+			runtasks: []*v1alpha1.RunTask{fakeCommandRunTask("t1", ""), fakeCommandRunTask("t2", "")},
+		},
+		"validate without render - +ve test case - templated ids are not verified": {
+			runtasks: []*v1alpha1.RunTask{templatedTask("t1"), templatedTask("t2")},
+		},
+		"validate without render - +ve test case - expected output is found": {
+			runtasks:       []*v1alpha1.RunTask{fakeCommandRunTask("t1", "")},
+			output:         true,
+			outputExpected: true,
+		},
+		"validate without render - -ve test case - no tasks": {
+			expectedErrs: []string{"no runtasks found"},
+		},
+		"validate without render - -ve test case - nil meta": {
+			runtasks:     []*v1alpha1.RunTask{fakeCommandRunTask("t1", ""), {Spec: v1alpha1.RunTaskSpec{Meta: ""}}},
+			expectedErrs: []string{"nil meta task specs"},
+		},
+		"validate without render - -ve test case - duplicate ids": {
+			runtasks:     []*v1alpha1.RunTask{fakeCommandRunTask("t1", ""), fakeCommandRunTask("T1", "")},
+			expectedErrs: []string{"duplicate id 'T1'"},
+		},
+		"validate without render - -ve test case - seeded id": {
+			runtasks:     []*v1alpha1.RunTask{fakeCommandRunTask("t1", "")},
+			seeded:       "t1",
+			expectedErrs: []string{"reserved by a seeded task result"},
+		},
+		"validate without render - -ve test case - expected output is missing": {
+			runtasks:       []*v1alpha1.RunTask{fakeCommandRunTask("t1", "")},
+			outputExpected: true,
+			expectedErrs:   []string{"no output task found"},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewTaskGroupRunner()
+			r.allTasks = mock.runtasks
+			if len(mock.seeded) != 0 {
+				r.SeedTaskResult(mock.seeded, map[string]interface{}{})
+			}
+			if mock.output {
+				r.outputTasks = []*v1alpha1.RunTask{{Spec: v1alpha1.RunTaskSpec{Meta: "id: out\nkind: Command\naction: output", Task: "out"}}}
+			}
+			r.SetOutputExpected(mock.outputExpected)
+
+			err := r.validate(nil, false)
+			if len(mock.expectedErrs) == 0 && err != nil {
+				t.Fatalf("failed to test validate without render: expected 'no error': actual '%s'", err)
+			}
+			if len(mock.expectedErrs) != 0 && err == nil {
+				t.Fatalf("failed to test validate without render: expected errors '%v': actual 'no error'", mock.expectedErrs)
+			}
+			for _, e := range mock.expectedErrs {
+				if !strings.Contains(err.Error(), e) {
+					t.Fatalf("failed to test validate without render: expected error '%s': actual '%s'", e, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLiteralTaskID(t *testing.T) {
+	tests := map[string]struct {
+		meta     string
+		expected string
+	}{
+		"literal task id - +ve test case - plain meta": {
+			meta:     "id: t1\nkind: Command\naction: put",
+			expected: "t1",
+		},
+		"literal task id - +ve test case - quoted id": {
+			meta:     "kind: Command\nid: 't1'\naction: put",
+			expected: "t1",
+		},
+		"literal task id - +ve test case - other fields are templated": {
+			meta:     "id: t1\nrunNamespace: {{ .Volume.runNamespace }}\nobjectName: \"{{ .Volume.owner }}-svc\"",
+			expected: "t1",
+		},
+		"literal task id - +ve test case - document start marker": {
+			meta:     "---\nid: t1\nkind: Command",
+			expected: "t1",
+		},
+		"literal task id - -ve test case - nested id is not the task id": {
+			meta: "kind: Command\noptions:\n  id: t1",
+		},
+		"literal task id - -ve test case - templated id": {
+			meta: "id: {{ .Volume.owner }}\nkind: Command",
+		},
+		"literal task id - -ve test case - partly templated id": {
+			meta: "id: t1-{{ .Volume.owner }}\nkind: Command",
+		},
+		"literal task id - -ve test case - id decided by the template": {
+			meta: "{{- if .Volume.owner }}\nid: t1\n{{- else }}\nid: t2\n{{- end }}\nkind: Command",
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := literalTaskID(mock.meta)
+			if actual != mock.expected {
+				t.Fatalf("failed to test literal task id: expected '%s': actual '%s'", mock.expected, actual)
+			}
+		})
+	}
+}
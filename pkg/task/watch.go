@@ -0,0 +1,136 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// defaultWatchTimeout is the maximum duration a watch based task waits for
+// its watch condition to be met if the task does not have a timeout
+const defaultWatchTimeout = 5 * time.Minute
+
+// newWatcher starts a watch on the object of this task
+func (m *taskExecutor) newWatcher() (watch.Interface, error) {
+	name := m.getTaskObjectName()
+	identifier := m.metaTaskExec.identifier
+
+	if identifier.isCoreV1PVC() {
+		return m.getK8sClient().WatchCoreV1PVC(name)
+	} else if identifier.isCoreV1PV() {
+		return m.getK8sClient().WatchCoreV1PV(name)
+	} else if identifier.isCoreV1Pod() {
+		return m.getK8sClient().WatchCoreV1Pod(name)
+	}
+
+	return nil, fmt.Errorf("un-supported watch operation: failed to execute task: '%+v'", m.metaTaskExec.getMetaInfo())
+}
+
+// watchK8sResource watches the object of this task till the task's watch
+// condition is met or the watch times out
+//
+// NOTE:
+//  The object that met the watch condition is stored at
+// .TaskResult.<taskID>.watchResult as well as at .JsonResult
+func (m *taskExecutor) watchK8sResource() (err error) {
+	if len(strings.TrimSpace(m.runtask.Spec.WatchCondition)) == 0 {
+		return fmt.Errorf("failed to watch task '%s': missing watch condition", m.getTaskIdentity())
+	}
+
+	w, err := m.newWatcher()
+	if err != nil {
+		return
+	}
+	defer w.Stop()
+
+	timeout := m.timeout
+	if timeout <= 0 {
+		timeout = defaultWatchTimeout
+	}
+
+	return m.watchUntil(w, timeout)
+}
+
+// watchUntil evaluates the task's watch condition against each object
+// observed by the provided watcher. It returns once the condition is met or
+// the provided timeout expires.
+func (m *taskExecutor) watchUntil(w watch.Interface, timeout time.Duration) (err error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			return fmt.Errorf("failed to watch task '%s': watch condition was not met within '%s'", m.getTaskIdentity(), timeout)
+		case <-m.context().Done():
+			return errors.Wrapf(m.context().Err(), "failed to watch task '%s'", m.getTaskIdentity())
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return fmt.Errorf("failed to watch task '%s': watch was closed before watch condition was met", m.getTaskIdentity())
+			}
+
+			if event.Type == watch.Error {
+				return k8serrors.FromObject(event.Object)
+			}
+
+			raw, err := json.Marshal(event.Object)
+			if err != nil {
+				return err
+			}
+
+			met, err := m.isWatchConditionMet(raw)
+			if err != nil {
+				return err
+			}
+			if !met {
+				if isDebug() {
+					m.logger().Info("watch condition is not met for task", "task", m.getTaskIdentity(), "event", event.Type)
+				}
+				continue
+			}
+
+			var obj map[string]interface{}
+			err = json.Unmarshal(raw, &obj)
+			if err != nil {
+				return err
+			}
+			util.SetNestedField(m.templateValues, obj, string(v1alpha1.TaskResultTLP), m.getTaskIdentity(), string(v1alpha1.TaskResultWatchResultTRTP))
+			return nil
+		}
+	}
+}
+
+// isWatchConditionMet evaluates the task's watch condition against the
+// provided object
+func (m *taskExecutor) isWatchConditionMet(raw []byte) (bool, error) {
+	util.SetNestedField(m.templateValues, raw, string(v1alpha1.CurrentJSONResultTLP))
+
+	b, err := m.asTemplatedBytes("WatchCondition", m.runtask.Spec.WatchCondition)
+	if err != nil {
+		return false, err
+	}
+	return util.CheckTruthy(strings.TrimSpace(string(b))), nil
+}
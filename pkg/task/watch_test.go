@@ -0,0 +1,111 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+	api_core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func fakePVC(phase api_core_v1.PersistentVolumeClaimPhase) *api_core_v1.PersistentVolumeClaim {
+	pvc := &api_core_v1.PersistentVolumeClaim{}
+	pvc.Name = "pvc-1"
+	pvc.Status.Phase = phase
+	return pvc
+}
+
+func TestWatchUntil(t *testing.T) {
+	tests := map[string]struct {
+		phases        []api_core_v1.PersistentVolumeClaimPhase
+		isClosed      bool
+		isCancelled   bool
+		expectedPhase string
+		isErr         bool
+	}{
+		"watch until - +ve test case - condition met on first event": {
+			phases:        []api_core_v1.PersistentVolumeClaimPhase{api_core_v1.ClaimBound},
+			expectedPhase: "Bound",
+		},
+		"watch until - +ve test case - condition met on later event": {
+			phases:        []api_core_v1.PersistentVolumeClaimPhase{api_core_v1.ClaimPending, api_core_v1.ClaimBound},
+			expectedPhase: "Bound",
+		},
+		"watch until - -ve test case - timed out": {
+			phases: []api_core_v1.PersistentVolumeClaimPhase{api_core_v1.ClaimPending},
+			isErr:  true,
+		},
+		"watch until - -ve test case - watch closed": {
+			phases:   []api_core_v1.PersistentVolumeClaimPhase{api_core_v1.ClaimPending},
+			isClosed: true,
+			isErr:    true,
+		},
+		"watch until - -ve test case - cancelled context": {
+			phases:      []api_core_v1.PersistentVolumeClaimPhase{api_core_v1.ClaimPending},
+			isCancelled: true,
+			isErr:       true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			runtask := &v1alpha1.RunTask{}
+			runtask.Spec.WatchCondition = `{{- jsonpath .JsonResult "{.status.phase}" | eq "Bound" -}}`
+			te := &taskExecutor{
+				templateValues: fakeTemplateValues(),
+				runtask:        runtask,
+				metaTaskExec: &metaTaskExecutor{
+					metaTask: MetaTaskSpec{MetaTaskIdentity: MetaTaskIdentity{Identity: "waitpvc"}},
+				},
+			}
+
+			w := watch.NewFakeWithChanSize(len(mock.phases), false)
+			for _, phase := range mock.phases {
+				w.Modify(fakePVC(phase))
+			}
+			if mock.isClosed {
+				w.Stop()
+			}
+			if mock.isCancelled {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				te.ctx = ctx
+			}
+
+			err := te.watchUntil(w, 100*time.Millisecond)
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("failed to test watch until: expected 'error': actual 'no error'")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to test watch until: expected 'no error': actual '%s'", err)
+			}
+
+			phase := util.GetNestedString(te.templateValues, "TaskResult", "waitpvc", "watchResult", "status", "phase")
+			if phase != mock.expectedPhase {
+				t.Fatalf("failed to test watch until: expected phase '%s': actual '%s'", mock.expectedPhase, phase)
+			}
+		})
+	}
+}
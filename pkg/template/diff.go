@@ -0,0 +1,159 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValuesDiff returns the difference between the provided template values as
+// a unified diff like string. Each changed value is reported against its
+// dotted path; removed values are prefixed with '-' & added values with '+'.
+// A modified value is reported as removed followed by added.
+//
+// Example:
+//  - TaskResult.t1.phase: Pending
+//  + TaskResult.t1.phase: Running
+//  + TaskResult.t2.objectName: pvc-1-svc
+//
+// NOTE:
+//  Values of the keys that match the redact pattern are redacted & long byte
+// slices are truncated similar to a debug snapshot. Empty string is returned
+// if there is no difference.
+func ValuesDiff(before, after map[string]interface{}) string {
+	var lines []string
+	diffValues("", before, after, &lines)
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diffValues appends the difference between the provided values found at
+// the provided path to the provided lines
+func diffValues(path string, before, after interface{}, lines *[]string) {
+	beforeMap, isBeforeMap := asMapOfValues(before)
+	afterMap, isAfterMap := asMapOfValues(after)
+	if isBeforeMap && isAfterMap {
+		keys := map[string]bool{}
+		for k := range beforeMap {
+			keys[k] = true
+		}
+		for k := range afterMap {
+			keys[k] = true
+		}
+		var sorted []string
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		for _, k := range sorted {
+			nested := k
+			if len(path) != 0 {
+				nested = path + "." + k
+			}
+			b, inBefore := beforeMap[k]
+			a, inAfter := afterMap[k]
+			switch {
+			case isRedacted(k):
+				if !inBefore || !inAfter || !reflect.DeepEqual(b, a) {
+					diffLeaf(nested, redactedIfPresent(b, inBefore), redactedIfPresent(a, inAfter), inBefore, inAfter, lines)
+				}
+			case !inBefore:
+				diffValues(nested, nil, a, lines)
+			case !inAfter:
+				diffValues(nested, b, nil, lines)
+			default:
+				diffValues(nested, b, a, lines)
+			}
+		}
+		return
+	}
+
+	if isBeforeMap {
+		// report each of the removed values followed by the value that
+		// replaced them if any
+		diffValues(path, before, map[string]interface{}{}, lines)
+		if after != nil {
+			diffLeaf(path, nil, after, false, true, lines)
+		}
+		return
+	}
+	if isAfterMap {
+		// report the replaced value if any followed by each of the added
+		// values
+		if before != nil {
+			diffLeaf(path, before, nil, true, false, lines)
+		}
+		diffValues(path, map[string]interface{}{}, after, lines)
+		return
+	}
+
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+	diffLeaf(path, before, after, before != nil, after != nil, lines)
+}
+
+// diffLeaf appends the removed & added lines of the provided value
+func diffLeaf(path string, before, after interface{}, inBefore, inAfter bool, lines *[]string) {
+	if inBefore {
+		*lines = append(*lines, fmt.Sprintf("- %s: %v", path, diffValue(before)))
+	}
+	if inAfter {
+		*lines = append(*lines, fmt.Sprintf("+ %s: %v", path, diffValue(after)))
+	}
+}
+
+// redactedIfPresent returns the redacted value if the value is present
+func redactedIfPresent(v interface{}, present bool) interface{} {
+	if !present {
+		return nil
+	}
+	return redactedValue
+}
+
+// diffValue returns the provided value as it is displayed in a diff
+func diffValue(v interface{}) interface{} {
+	if b, ok := v.([]byte); ok {
+		if len(b) > maxSnapshotBytes {
+			return fmt.Sprintf("%s...(truncated %d bytes)", b[:maxSnapshotBytes], len(b)-maxSnapshotBytes)
+		}
+		return string(b)
+	}
+	return v
+}
+
+// asMapOfValues returns the provided value as a map of values if it is a map
+// of strings to any value or to strings
+func asMapOfValues(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[string]string:
+		values := make(map[string]interface{}, len(m))
+		for k, s := range m {
+			values[k] = s
+		}
+		return values, true
+	}
+	return nil, false
+}
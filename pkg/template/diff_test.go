@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValuesDiff(t *testing.T) {
+	tests := map[string]struct {
+		before   map[string]interface{}
+		after    map[string]interface{}
+		expected string
+	}{
+		"101": {
+			before:   map[string]interface{}{"Volume": map[string]interface{}{"owner": "pvc-1"}},
+			after:    map[string]interface{}{"Volume": map[string]interface{}{"owner": "pvc-1"}},
+			expected: "",
+		},
+		"102": {
+			before: map[string]interface{}{"TaskResult": map[string]interface{}{}},
+			after: map[string]interface{}{"TaskResult": map[string]interface{}{
+				"t1": map[string]interface{}{"objectName": "pvc-1-svc", "phase": "Running"},
+			}},
+			expected: "+ TaskResult.t1.objectName: pvc-1-svc\n+ TaskResult.t1.phase: Running",
+		},
+		"103": {
+			before:   map[string]interface{}{"TaskResult": map[string]interface{}{"t1": map[string]interface{}{"phase": "Pending"}}},
+			after:    map[string]interface{}{"TaskResult": map[string]interface{}{"t1": map[string]interface{}{"phase": "Running"}}},
+			expected: "- TaskResult.t1.phase: Pending\n+ TaskResult.t1.phase: Running",
+		},
+		"104": {
+			before:   map[string]interface{}{"JsonResult": []byte(`{"kind":"Service"}`), "owner": "pvc-1"},
+			after:    map[string]interface{}{"owner": "pvc-1"},
+			expected: `- JsonResult: {"kind":"Service"}`,
+		},
+		"105": {
+			before:   map[string]interface{}{"Config": map[string]string{"Replicas": "1"}},
+			after:    map[string]interface{}{"Config": map[string]string{"Replicas": "3"}},
+			expected: "- Config.Replicas: 1\n+ Config.Replicas: 3",
+		},
+		"106": {
+			before:   map[string]interface{}{"Volume": map[string]interface{}{"chapPassword": "pass-1"}},
+			after:    map[string]interface{}{"Volume": map[string]interface{}{"chapPassword": "pass-2"}},
+			expected: "- Volume.chapPassword: --redacted--\n+ Volume.chapPassword: --redacted--",
+		},
+		"107": {
+			before:   map[string]interface{}{"t1": "obj-1"},
+			after:    map[string]interface{}{"t1": map[string]interface{}{"objectName": "obj-1"}},
+			expected: "- t1: obj-1\n+ t1.objectName: obj-1",
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := ValuesDiff(mock.before, mock.after)
+			if actual != mock.expected {
+				t.Fatalf("failed to test values diff: expected '%s': actual '%s'", mock.expected, actual)
+			}
+			if strings.Contains(actual, "pass-") {
+				t.Fatalf("failed to test values diff: expected redacted values: actual '%s'", actual)
+			}
+		})
+	}
+}
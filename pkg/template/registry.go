@@ -0,0 +1,317 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+var (
+	// funcsMutex guards the registered template functions
+	funcsMutex sync.RWMutex
+	// funcs are the registered template functions mapped by their names
+	funcs = template.FuncMap{}
+
+	// funcNamePattern matches the names that are valid template function
+	// names
+	funcNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+	// errorType is the type of the error interface
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// RegisterTemplateFunc registers the provided function against the provided
+// name. Templates executed by this library can invoke the function by this
+// name.
+//
+// NOTE:
+//  The function should return either a single value or a value & an error.
+// A function registered earlier with the same name is replaced. Functions
+// registered this way take precedence over the sprig functions but can not
+// replace the runtask functions of this library e.g. saveAs.
+func RegisterTemplateFunc(name string, fn interface{}) error {
+	if !funcNamePattern.MatchString(name) {
+		return fmt.Errorf("failed to register template function '%s': invalid name", name)
+	}
+	if _, found := runtaskFuncs()[name]; found {
+		return fmt.Errorf("failed to register template function '%s': name is reserved", name)
+	}
+	if _, found := runCommandFuncs()[name]; found {
+		return fmt.Errorf("failed to register template function '%s': name is reserved", name)
+	}
+	if err := validateFuncSignature(fn); err != nil {
+		return fmt.Errorf("failed to register template function '%s': %s", name, err)
+	}
+
+	funcsMutex.Lock()
+	defer funcsMutex.Unlock()
+
+	funcs[name] = fn
+	return nil
+}
+
+// FuncMap is the map of template functions mapped by their names
+type FuncMap = template.FuncMap
+
+// ValidateTemplateFuncs verifies if the provided functions can be used in
+// addition to the functions supported by this library. A function whose name
+// collides with a supported function is rejected.
+func ValidateTemplateFuncs(fns FuncMap) error {
+	builtins := allCustomFuncs()
+	for name, fn := range fns {
+		if !funcNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid template function '%s': invalid name", name)
+		}
+		if _, found := builtins[name]; found {
+			return fmt.Errorf("invalid template function '%s': name collides with a built-in function", name)
+		}
+		if err := validateFuncSignature(fn); err != nil {
+			return fmt.Errorf("invalid template function '%s': %s", name, err)
+		}
+	}
+	return nil
+}
+
+// validateFuncSignature verifies if the provided function can be invoked
+// from a template
+func validateFuncSignature(fn interface{}) error {
+	if fn == nil {
+		return fmt.Errorf("nil function")
+	}
+	t := reflect.TypeOf(fn)
+	if t.Kind() != reflect.Func {
+		return fmt.Errorf("expected a function: actual '%T'", fn)
+	}
+	switch t.NumOut() {
+	case 1:
+		return nil
+	case 2:
+		if t.Out(1) == errorType {
+			return nil
+		}
+		return fmt.Errorf("expected second return value of type error: actual '%s'", t.Out(1))
+	default:
+		return fmt.Errorf("expected 1 or 2 return values: actual '%d'", t.NumOut())
+	}
+}
+
+// registeredFuncs returns a copy of the registered template functions
+func registeredFuncs() template.FuncMap {
+	funcsMutex.RLock()
+	defer funcsMutex.RUnlock()
+
+	f := make(template.FuncMap, len(funcs))
+	for k, v := range funcs {
+		f[k] = v
+	}
+	return f
+}
+
+// defaultFuncs returns the utility template functions that are registered by
+// default
+func defaultFuncs() template.FuncMap {
+	return template.FuncMap{
+		"toBase64":      toBase64,
+		"fromBase64":    fromBase64,
+		"toBase64URL":   toBase64URL,
+		"fromBase64URL": fromBase64URL,
+		"b64enc":        toBase64,
+		"b64dec":        b64dec,
+		"toJSON":        ToJSON,
+		"fromJSON":      fromJSON,
+		"randAlphaNum":  randAlphaNum,
+		"randNumeric":   randNumeric,
+		"trimSuffix":    trimSuffix,
+		"trimPrefix":    trimPrefix,
+		"hasPrefix":     hasPrefix,
+		"hasSuffix":     hasSuffix,
+		"toLower":       strings.ToLower,
+		"toUpper":       strings.ToUpper,
+		"trimSpace":     strings.TrimSpace,
+		"replaceAll":    replaceAll,
+		"splitBy":       splitBy,
+		"joinWith":      joinWith,
+		"sha256sum":     sha256sum,
+		"md5sum":        md5sum,
+		"shortHash":     shortHash,
+		"toTolerations": toTolerations,
+		"toAffinity":    toAffinity,
+		"parseInt":      strconv.Atoi,
+	}
+}
+
+func init() {
+	for name, fn := range defaultFuncs() {
+		if err := RegisterTemplateFunc(name, fn); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// toBase64 returns the standard base64 encoding of the provided string
+func toBase64(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+// fromBase64 returns the string decoded from the provided standard base64
+// encoding
+func fromBase64(s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	return string(b), err
+}
+
+// b64dec returns the string decoded from the provided standard base64
+// encoding. It is a sprig compatible alternative to fromBase64.
+//
+// NOTE:
+//  An invalid encoding does not fail the render since that would abort the
+// entire run. The returned string is marked as invalid instead.
+func b64dec(s string) string {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return fmt.Sprintf("--invalid base64: %s--", err)
+	}
+	return string(b)
+}
+
+// toBase64URL returns the url safe base64 encoding of the provided string
+func toBase64URL(s string) string {
+	return base64.URLEncoding.EncodeToString([]byte(s))
+}
+
+// fromBase64URL returns the string decoded from the provided url safe
+// base64 encoding
+func fromBase64URL(s string) (string, error) {
+	b, err := base64.URLEncoding.DecodeString(s)
+	return string(b), err
+}
+
+const (
+	// alphaNumChars are the characters of a random alphanumeric string
+	alphaNumChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	// numericChars are the characters of a random numeric string
+	numericChars = "0123456789"
+)
+
+// randAlphaNum returns a random alphanumeric string of the provided length
+func randAlphaNum(length int) (string, error) {
+	return randString(length, alphaNumChars)
+}
+
+// randNumeric returns a random numeric string of the provided length
+func randNumeric(length int) (string, error) {
+	return randString(length, numericChars)
+}
+
+// randString returns a random string of the provided length made of the
+// provided characters
+func randString(length int, chars string) (string, error) {
+	if length < 0 {
+		return "", fmt.Errorf("invalid length '%d' of random string", length)
+	}
+	b := make([]byte, length)
+	max := big.NewInt(int64(len(chars)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = chars[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// trimSuffix returns the provided string without the provided suffix
+//
+// NOTE:
+//  The string is the last argument so that it can be piped e.g.
+// {{ .name | trimSuffix "-svc" }}
+func trimSuffix(suffix, s string) string {
+	return strings.TrimSuffix(s, suffix)
+}
+
+// trimPrefix returns the provided string without the provided prefix
+func trimPrefix(prefix, s string) string {
+	return strings.TrimPrefix(s, prefix)
+}
+
+// hasPrefix flags if the provided string begins with the provided prefix
+func hasPrefix(prefix, s string) bool {
+	return strings.HasPrefix(s, prefix)
+}
+
+// hasSuffix flags if the provided string ends with the provided suffix
+func hasSuffix(suffix, s string) bool {
+	return strings.HasSuffix(s, suffix)
+}
+
+// replaceAll replaces all the occurrences of old with new in the provided
+// string
+func replaceAll(old, new, s string) string {
+	return strings.Replace(s, old, new, -1)
+}
+
+// splitBy splits the provided string by the provided separator
+func splitBy(sep, s string) []string {
+	return strings.Split(s, sep)
+}
+
+// joinWith joins the provided strings with the provided separator
+func joinWith(sep string, list []string) string {
+	return strings.Join(list, sep)
+}
+
+// sha256sum returns the hex encoded sha256 checksum of the UTF-8 bytes of
+// the provided string
+func sha256sum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// md5sum returns the hex encoded md5 checksum of the UTF-8 bytes of the
+// provided string
+func md5sum(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// shortHash returns the first n characters of the hex encoded sha256
+// checksum of the UTF-8 bytes of the provided string. This is typically used
+// to name the resources deterministically within the length limits of K8s
+// names & labels.
+//
+// Example:
+//  name: pvc-{{ shortHash .Volume.owner 8 }}
+func shortHash(s string, n int) (string, error) {
+	sum := sha256sum(s)
+	if n < 1 || n > len(sum) {
+		return "", fmt.Errorf("invalid short hash length '%d': expected a length between 1 and %d", n, len(sum))
+	}
+	return sum[:n], nil
+}
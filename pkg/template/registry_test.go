@@ -0,0 +1,228 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestRegisterTemplateFunc(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		fn      interface{}
+		isError bool
+	}{
+		"register template func - +ve test case - single return value": {
+			name: "testGreet",
+			fn:   func(s string) string { return "hello " + s },
+		},
+		"register template func - +ve test case - value & error": {
+			name: "testFailIfEmpty",
+			fn: func(s string) (string, error) {
+				if s == "" {
+					return "", errors.New("empty")
+				}
+				return s, nil
+			},
+		},
+		"register template func - -ve test case - invalid name": {
+			name:    "test-greet",
+			fn:      func() string { return "" },
+			isError: true,
+		},
+		"register template func - -ve test case - reserved name": {
+			name:    "saveAs",
+			fn:      func() string { return "" },
+			isError: true,
+		},
+		"register template func - -ve test case - not a function": {
+			name:    "testNotFunc",
+			fn:      "hello",
+			isError: true,
+		},
+		"register template func - -ve test case - nil function": {
+			name:    "testNilFunc",
+			isError: true,
+		},
+		"register template func - -ve test case - no return value": {
+			name:    "testNoReturn",
+			fn:      func() {},
+			isError: true,
+		},
+		"register template func - -ve test case - second return value is not an error": {
+			name:    "testNotError",
+			fn:      func() (string, string) { return "", "" },
+			isError: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := RegisterTemplateFunc(mock.name, mock.fn)
+			if mock.isError && err == nil {
+				t.Fatalf("failed to test register template func: expected error: actual no error")
+			}
+			if !mock.isError && err != nil {
+				t.Fatalf("failed to test register template func: expected no error: actual '%s'", err)
+			}
+			_, found := registeredFuncs()[mock.name]
+			if found == mock.isError {
+				t.Fatalf("failed to test register template func: expected registered '%t': actual '%t'", !mock.isError, found)
+			}
+		})
+	}
+
+	b, err := AsTemplatedBytes("RegisterTemplateFunc", `{{ "world" | testGreet }}`, nil)
+	if err != nil || string(b) != "hello world" {
+		t.Fatalf("failed to test register template func: expected 'hello world': actual '%s': error '%v'", b, err)
+	}
+	_, err = AsTemplatedBytes("RegisterTemplateFunc", `{{ "" | testFailIfEmpty }}`, nil)
+	if err == nil {
+		t.Fatalf("failed to test register template func: expected error: actual no error")
+	}
+}
+
+func TestValidateTemplateFuncs(t *testing.T) {
+	tests := map[string]struct {
+		fns     FuncMap
+		isError bool
+	}{
+		"validate template funcs - +ve test case - custom function": {
+			fns: FuncMap{"orgName": func(s string) string { return "org-" + s }},
+		},
+		"validate template funcs - +ve test case - no functions": {},
+		"validate template funcs - -ve test case - collides with runtask function": {
+			fns:     FuncMap{"saveAs": func() string { return "" }},
+			isError: true,
+		},
+		"validate template funcs - -ve test case - collides with registered function": {
+			fns:     FuncMap{"toBase64": func() string { return "" }},
+			isError: true,
+		},
+		"validate template funcs - -ve test case - collides with sprig function": {
+			fns:     FuncMap{"upper": func() string { return "" }},
+			isError: true,
+		},
+		"validate template funcs - -ve test case - invalid name": {
+			fns:     FuncMap{"org-name": func() string { return "" }},
+			isError: true,
+		},
+		"validate template funcs - -ve test case - invalid signature": {
+			fns:     FuncMap{"orgName": func() {}},
+			isError: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateTemplateFuncs(mock.fns)
+			if mock.isError != (err != nil) {
+				t.Fatalf("failed to test validate template funcs: expected error '%t': actual '%v'", mock.isError, err)
+			}
+		})
+	}
+
+	fns := FuncMap{"orgName": func(s string) string { return "org-" + s }}
+	b, err := AsTemplatedBytesWithFuncs("ValidateTemplateFuncs", `{{ "pvc" | orgName }}`, nil, fns)
+	if err != nil || string(b) != "org-pvc" {
+		t.Fatalf("failed to test validate template funcs: expected 'org-pvc': actual '%s': error '%v'", b, err)
+	}
+	if err := ValidateSyntaxWithFuncs("ValidateTemplateFuncs", `{{ "pvc" | orgName }}`, fns); err != nil {
+		t.Fatalf("failed to test validate template funcs: expected no error: actual '%s'", err)
+	}
+	if err := ValidateSyntax("ValidateTemplateFuncs", `{{ "pvc" | orgName }}`); err == nil {
+		t.Fatalf("failed to test validate template funcs: expected error: actual no error")
+	}
+}
+
+func TestDefaultFuncs(t *testing.T) {
+	if len(defaultFuncs()) != 26 {
+		t.Fatalf("failed to test default funcs: expected '26' functions: actual '%d'", len(defaultFuncs()))
+	}
+
+	tests := map[string]struct {
+		template string
+		values   map[string]interface{}
+		expected string
+		isError  bool
+	}{
+		"101": {template: `{{ "admin" | toBase64 }}`, expected: "YWRtaW4="},
+		"102": {template: `{{ "YWRtaW4=" | fromBase64 }}`, expected: "admin"},
+		"103": {template: `{{ "not base64!" | fromBase64 }}`, isError: true},
+		"104": {template: `{{ "a?b" | toBase64URL | fromBase64URL }}`, expected: "a?b"},
+		"105": {template: `{{ .obj | toJSON }}`, values: map[string]interface{}{"obj": map[string]string{"a": "b"}}, expected: `{"a":"b"}`},
+		"106": {template: `{{ $m := fromJSON "{\"a\":\"b\"}" }}{{ $m.a }}`, expected: "b"},
+		"107": {template: `{{ "pvc-1-svc" | trimSuffix "-svc" }}`, expected: "pvc-1"},
+		"108": {template: `{{ "pvc-1" | trimPrefix "pvc-" }}`, expected: "1"},
+		"109": {template: `{{ hasPrefix "pvc" "pvc-1" }} {{ hasSuffix "-1" "pvc-1" }}`, expected: "true true"},
+		"110": {template: `{{ "PvC" | toLower }} {{ "PvC" | toUpper }}`, expected: "pvc PVC"},
+		"111": {template: `{{ " pvc " | trimSpace }}`, expected: "pvc"},
+		"112": {template: `{{ "a-b-c" | replaceAll "-" "." }}`, expected: "a.b.c"},
+		"113": {template: `{{ "a,b" | splitBy "," | joinWith " " }}`, expected: "a b"},
+		"114": {template: `{{ "abc" | sha256sum }}`, expected: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		"115": {template: `{{ add ("10" | parseInt) 1 }}`, expected: "11"},
+		"116": {template: `{{ "ten" | parseInt }}`, isError: true},
+		"117": {template: `{{ "abc" | md5sum }}`, expected: "900150983cd24fb0d6963f7d28e17f72"},
+		"118": {template: `pvc-{{ shortHash "abc" 8 }}`, expected: "pvc-ba7816bf"},
+		"119": {template: `{{ shortHash "abc" 0 }}`, isError: true},
+		"120": {template: `{{ shortHash "abc" 65 }}`, isError: true},
+		"121": {template: `{{ shortHash .name 8 }}`, values: map[string]interface{}{"name": "é"}, expected: "4a99557e"},
+		"122": {template: `{{ "admin" | b64enc }}`, expected: "YWRtaW4="},
+		"123": {template: `{{ "YWRtaW4=" | b64dec }}`, expected: "admin"},
+		"124": {template: `{{ "admin" | b64enc | b64dec }}`, expected: "admin"},
+		"125": {template: `{{ "not base64!" | b64dec }}`, expected: "--invalid base64: illegal base64 data at input byte 3--"},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := AsTemplatedBytes("DefaultFuncs", mock.template, mock.values)
+			if mock.isError && err == nil {
+				t.Fatalf("failed to test default funcs: expected error: actual no error")
+			}
+			if !mock.isError && err != nil {
+				t.Fatalf("failed to test default funcs: expected no error: actual '%s'", err)
+			}
+			if !mock.isError && string(b) != mock.expected {
+				t.Fatalf("failed to test default funcs: expected '%s': actual '%s'", mock.expected, b)
+			}
+		})
+	}
+}
+
+func TestRandFuncs(t *testing.T) {
+	tests := map[string]struct {
+		template string
+		pattern  string
+	}{
+		"rand funcs - +ve test case - alphanumeric": {template: `{{ randAlphaNum 16 }}`, pattern: `^[a-zA-Z0-9]{16}$`},
+		"rand funcs - +ve test case - numeric":      {template: `{{ randNumeric 8 }}`, pattern: `^[0-9]{8}$`},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := AsTemplatedBytes("RandFuncs", mock.template, nil)
+			if err != nil {
+				t.Fatalf("failed to test rand funcs: expected no error: actual '%s'", err)
+			}
+			if !regexp.MustCompile(mock.pattern).Match(b) {
+				t.Fatalf("failed to test rand funcs: expected match of '%s': actual '%s'", mock.pattern, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	api_core_v1 "k8s.io/api/core/v1"
+)
+
+// toTolerations returns the yaml of a list of K8s tolerations built from the
+// provided list of maps. Each map supports key, operator, value, effect &
+// tolerationSeconds. The provided list can also be a yaml string.
+//
+// Example:
+//  tolerations:
+//  {{ toTolerations .Pool.tolerations | indent 2 }}
+//
+// NOTE:
+//  An error is returned if a toleration is invalid e.g. key is missing for
+// operator Equal
+func toTolerations(input interface{}) (string, error) {
+	list, err := asListOfMaps(input)
+	if err != nil {
+		return "", fmt.Errorf("invalid tolerations: %s", err)
+	}
+
+	tolerations := make([]api_core_v1.Toleration, 0, len(list))
+	for idx, item := range list {
+		t, err := asToleration(item)
+		if err != nil {
+			return "", fmt.Errorf("invalid toleration at index '%d': %s", idx, err)
+		}
+		tolerations = append(tolerations, t)
+	}
+
+	b, err := yaml.Marshal(tolerations)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// asToleration builds a K8s toleration from the provided map
+func asToleration(item map[string]interface{}) (t api_core_v1.Toleration, err error) {
+	for k := range item {
+		switch k {
+		case "key", "operator", "value", "effect", "tolerationSeconds":
+		default:
+			err = fmt.Errorf("unsupported field '%s'", k)
+			return
+		}
+	}
+
+	t.Key = fmt.Sprint(valueOrEmpty(item["key"]))
+	t.Value = fmt.Sprint(valueOrEmpty(item["value"]))
+	t.Operator = api_core_v1.TolerationOperator(fmt.Sprint(valueOrEmpty(item["operator"])))
+	t.Effect = api_core_v1.TaintEffect(fmt.Sprint(valueOrEmpty(item["effect"])))
+
+	switch t.Operator {
+	case "", api_core_v1.TolerationOpEqual:
+		if len(t.Key) == 0 {
+			err = fmt.Errorf("key is required for operator '%s'", api_core_v1.TolerationOpEqual)
+			return
+		}
+	case api_core_v1.TolerationOpExists:
+		if len(t.Value) != 0 {
+			err = fmt.Errorf("value must be empty for operator '%s'", api_core_v1.TolerationOpExists)
+			return
+		}
+	default:
+		err = fmt.Errorf("unsupported operator '%s'", t.Operator)
+		return
+	}
+
+	switch t.Effect {
+	case "", api_core_v1.TaintEffectNoSchedule, api_core_v1.TaintEffectPreferNoSchedule, api_core_v1.TaintEffectNoExecute:
+	default:
+		err = fmt.Errorf("unsupported effect '%s'", t.Effect)
+		return
+	}
+
+	seconds, ok := item["tolerationSeconds"]
+	if !ok || seconds == nil {
+		return
+	}
+	if t.Effect != api_core_v1.TaintEffectNoExecute {
+		err = fmt.Errorf("tolerationSeconds is supported for effect '%s' only", api_core_v1.TaintEffectNoExecute)
+		return
+	}
+	s, err := strconv.ParseInt(fmt.Sprint(seconds), 10, 64)
+	if err != nil {
+		err = fmt.Errorf("invalid tolerationSeconds '%v'", seconds)
+		return
+	}
+	t.TolerationSeconds = &s
+	return
+}
+
+// toAffinity returns the yaml of a K8s affinity that requires the pod to be
+// scheduled on the nodes having the provided labels. The provided map is a
+// node label mapped to one or more of its values. The provided map can also
+// be a yaml string.
+//
+// Example:
+//  affinity:
+//  {{ toAffinity .Pool.nodeLabels | indent 2 }}
+//
+// NOTE:
+//  An error is returned if a label does not have any value
+func toAffinity(input interface{}) (string, error) {
+	labels, err := asMap(input)
+	if err != nil {
+		return "", fmt.Errorf("invalid affinity: %s", err)
+	}
+	if len(labels) == 0 {
+		return "", fmt.Errorf("invalid affinity: no node labels")
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	// sorted for a deterministic yaml
+	sort.Strings(keys)
+
+	var expressions []api_core_v1.NodeSelectorRequirement
+	for _, k := range keys {
+		values, err := asListOfStrings(labels[k])
+		if err != nil || len(values) == 0 {
+			return "", fmt.Errorf("invalid affinity: node label '%s' needs one or more values", k)
+		}
+		expressions = append(expressions, api_core_v1.NodeSelectorRequirement{
+			Key:      k,
+			Operator: api_core_v1.NodeSelectorOpIn,
+			Values:   values,
+		})
+	}
+
+	affinity := api_core_v1.Affinity{
+		NodeAffinity: &api_core_v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &api_core_v1.NodeSelector{
+				NodeSelectorTerms: []api_core_v1.NodeSelectorTerm{
+					{MatchExpressions: expressions},
+				},
+			},
+		},
+	}
+	b, err := yaml.Marshal(affinity)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// valueOrEmpty returns an empty string if the provided value is nil
+func valueOrEmpty(v interface{}) interface{} {
+	if v == nil {
+		return ""
+	}
+	return v
+}
+
+// asListOfMaps converts the provided list or yaml string to a list of maps
+func asListOfMaps(input interface{}) ([]map[string]interface{}, error) {
+	switch list := input.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		var parsed []map[string]interface{}
+		err := yaml.Unmarshal([]byte(list), &parsed)
+		return parsed, err
+	case []map[string]interface{}:
+		return list, nil
+	case []map[string]string:
+		maps := make([]map[string]interface{}, 0, len(list))
+		for _, item := range list {
+			m := make(map[string]interface{}, len(item))
+			for k, v := range item {
+				m[k] = v
+			}
+			maps = append(maps, m)
+		}
+		return maps, nil
+	case []interface{}:
+		maps := make([]map[string]interface{}, 0, len(list))
+		for idx, item := range list {
+			m, err := asMap(item)
+			if err != nil {
+				return nil, fmt.Errorf("item at index '%d': %s", idx, err)
+			}
+			maps = append(maps, m)
+		}
+		return maps, nil
+	}
+	return nil, fmt.Errorf("expected a list of maps: actual '%T'", input)
+}
+
+// asMap converts the provided map or yaml string to a map
+func asMap(input interface{}) (map[string]interface{}, error) {
+	switch m := input.(type) {
+	case string:
+		var parsed map[string]interface{}
+		err := yaml.Unmarshal([]byte(m), &parsed)
+		return parsed, err
+	case map[string]interface{}:
+		return m, nil
+	case map[string]string:
+		converted := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			converted[k] = v
+		}
+		return converted, nil
+	}
+	return nil, fmt.Errorf("expected a map: actual '%T'", input)
+}
+
+// asListOfStrings converts the provided string or list to a list of strings
+func asListOfStrings(input interface{}) ([]string, error) {
+	switch l := input.(type) {
+	case string:
+		if len(l) == 0 {
+			return nil, nil
+		}
+		return []string{l}, nil
+	case []string:
+		return l, nil
+	case []interface{}:
+		list := make([]string, 0, len(l))
+		for _, v := range l {
+			list = append(list, fmt.Sprint(v))
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("expected a string or a list of strings: actual '%T'", input)
+}
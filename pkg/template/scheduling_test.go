@@ -0,0 +1,104 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"testing"
+)
+
+func TestSchedulingFuncs(t *testing.T) {
+	tests := map[string]struct {
+		template string
+		values   map[string]interface{}
+		expected string
+		isError  bool
+	}{
+		"101": {
+			template: `{{ toTolerations .tolerations }}`,
+			values: map[string]interface{}{"tolerations": []interface{}{
+				map[string]interface{}{"key": "disk", "value": "ssd", "effect": "NoSchedule"},
+				map[string]interface{}{"key": "node", "operator": "Exists", "effect": "NoExecute", "tolerationSeconds": 60},
+			}},
+			expected: "- effect: NoSchedule\n  key: disk\n  value: ssd\n- effect: NoExecute\n  key: node\n  operator: Exists\n  tolerationSeconds: 60",
+		},
+		"102": {
+			template: `tolerations:` + "\n" + `{{ toTolerations .tolerations | indent 2 }}`,
+			values:   map[string]interface{}{"tolerations": "- key: disk\n  operator: Equal\n  value: ssd"},
+			expected: "tolerations:\n  - key: disk\n    operator: Equal\n    value: ssd",
+		},
+		"103": {
+			template: `{{ toTolerations .tolerations }}`,
+			values:   map[string]interface{}{"tolerations": []interface{}{map[string]interface{}{"value": "ssd"}}},
+			isError:  true,
+		},
+		"104": {
+			template: `{{ toTolerations .tolerations }}`,
+			values:   map[string]interface{}{"tolerations": []interface{}{map[string]interface{}{"key": "disk", "operator": "Exists", "value": "ssd"}}},
+			isError:  true,
+		},
+		"105": {
+			template: `{{ toTolerations .tolerations }}`,
+			values:   map[string]interface{}{"tolerations": []interface{}{map[string]interface{}{"key": "disk", "effect": "NoSchedule", "tolerationSeconds": 60}}},
+			isError:  true,
+		},
+		"106": {
+			template: `{{ toTolerations .tolerations }}`,
+			values:   map[string]interface{}{"tolerations": []interface{}{map[string]interface{}{"key": "disk", "effct": "NoSchedule"}}},
+			isError:  true,
+		},
+		"107": {
+			template: `{{ toTolerations .tolerations }}`,
+			values:   map[string]interface{}{"tolerations": "key: disk"},
+			isError:  true,
+		},
+		"108": {
+			template: `{{ toAffinity .labels }}`,
+			values:   map[string]interface{}{"labels": map[string]interface{}{"zone": []interface{}{"a", "b"}, "disk": "ssd"}},
+			expected: "nodeAffinity:\n  requiredDuringSchedulingIgnoredDuringExecution:\n    nodeSelectorTerms:\n    - matchExpressions:\n      - key: disk\n        operator: In\n        values:\n        - ssd\n      - key: zone\n        operator: In\n        values:\n        - a\n        - b",
+		},
+		"109": {
+			template: `{{ toAffinity .labels }}`,
+			values:   map[string]interface{}{"labels": "disk: ssd"},
+			expected: "nodeAffinity:\n  requiredDuringSchedulingIgnoredDuringExecution:\n    nodeSelectorTerms:\n    - matchExpressions:\n      - key: disk\n        operator: In\n        values:\n        - ssd",
+		},
+		"110": {
+			template: `{{ toAffinity .labels }}`,
+			values:   map[string]interface{}{"labels": map[string]interface{}{"disk": ""}},
+			isError:  true,
+		},
+		"111": {
+			template: `{{ toAffinity .labels }}`,
+			values:   map[string]interface{}{"labels": map[string]interface{}{}},
+			isError:  true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := AsTemplatedBytes("SchedulingFuncs", mock.template, mock.values)
+			if mock.isError && err == nil {
+				t.Fatalf("failed to test scheduling funcs: expected error: actual '%s'", b)
+			}
+			if !mock.isError && err != nil {
+				t.Fatalf("failed to test scheduling funcs: expected no error: actual '%s'", err)
+			}
+			if !mock.isError && string(b) != mock.expected {
+				t.Fatalf("failed to test scheduling funcs: expected '%s': actual '%s'", mock.expected, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+
+	apis "github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+)
+
+const (
+	// DefaultRedactPattern is the default pattern of the keys whose values
+	// are redacted in a debug snapshot
+	DefaultRedactPattern = `(?i)password|token|secret|key`
+
+	// redactedValue replaces the values that are redacted in a debug snapshot
+	redactedValue = "--redacted--"
+
+	// maxSnapshotBytes is the maximum length of a byte slice that is
+	// displayed in a debug snapshot; longer byte slices are truncated
+	maxSnapshotBytes = 512
+
+	// maxSnapshotDepth is the number of levels that are displayed in a debug
+	// snapshot; deeper values are summarized
+	maxSnapshotDepth = 2
+)
+
+var (
+	// redactMutex guards the redact pattern
+	redactMutex sync.RWMutex
+
+	// redactPattern matches the keys whose values are redacted in a debug
+	// snapshot
+	redactPattern = regexp.MustCompile(DefaultRedactPattern)
+)
+
+// SetRedactPattern sets the pattern of the keys whose values are redacted in
+// a debug snapshot
+func SetRedactPattern(pattern string) error {
+	r, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid redact pattern '%s': %s", pattern, err)
+	}
+
+	redactMutex.Lock()
+	defer redactMutex.Unlock()
+	redactPattern = r
+	return nil
+}
+
+// isRedacted flags if the value of the provided key needs to be redacted
+func isRedacted(key string) bool {
+	redactMutex.RLock()
+	defer redactMutex.RUnlock()
+	return redactPattern.MatchString(key)
+}
+
+// DebugSnapshot returns the provided template values as yaml that is fit to
+// be logged. Values of the keys that match the redact pattern are redacted,
+// long byte slices are truncated & only the top two levels are displayed.
+//
+// NOTE:
+//  The provided template values are not mutated. The snapshot is
+// deterministic since the keys are sorted.
+func DebugSnapshot(values map[string]interface{}) string {
+	return ToYaml(snapshotValue(values, 0))
+}
+
+// snapshotValue returns the debug snapshot of the provided value found at the
+// provided depth
+func snapshotValue(v interface{}, depth int) interface{} {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if len(val) > maxSnapshotBytes {
+			return fmt.Sprintf("%s...(truncated %d bytes)", val[:maxSnapshotBytes], len(val)-maxSnapshotBytes)
+		}
+		return string(val)
+	case map[string]interface{}:
+		if depth >= maxSnapshotDepth {
+			return fmt.Sprintf("<map of %d keys>", len(val))
+		}
+		snapshot := make(map[string]interface{}, len(val))
+		for k, nested := range val {
+			// the secret referred to by a task is redacted irrespective of
+			// the redact pattern
+			if isRedacted(k) || (depth == 0 && k == string(apis.SecretTLP)) {
+				snapshot[k] = redactedValue
+				continue
+			}
+			snapshot[k] = snapshotValue(nested, depth+1)
+		}
+		return snapshot
+	case map[string]string:
+		if depth >= maxSnapshotDepth {
+			return fmt.Sprintf("<map of %d keys>", len(val))
+		}
+		snapshot := make(map[string]interface{}, len(val))
+		for k, nested := range val {
+			if isRedacted(k) {
+				snapshot[k] = redactedValue
+				continue
+			}
+			snapshot[k] = nested
+		}
+		return snapshot
+	case []interface{}:
+		if depth >= maxSnapshotDepth {
+			return fmt.Sprintf("<list of %d items>", len(val))
+		}
+		snapshot := make([]interface{}, 0, len(val))
+		for _, nested := range val {
+			snapshot = append(snapshot, snapshotValue(nested, depth+1))
+		}
+		return snapshot
+	}
+
+	// any other composite value e.g. a typed object is summarized beyond the
+	// displayed levels
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Ptr:
+		if depth >= maxSnapshotDepth {
+			return fmt.Sprintf("<%T>", v)
+		}
+	}
+	return v
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDebugSnapshot(t *testing.T) {
+	tests := map[string]struct {
+		values      map[string]interface{}
+		contains    []string
+		notContains []string
+	}{
+		"debug snapshot - +ve test case - redacted keys": {
+			values: map[string]interface{}{
+				"Config": map[string]interface{}{"Password": "pass-1", "AuthToken": "token-1", "owner": "pvc-1"},
+				"secret": "secret-1",
+			},
+			contains:    []string{"Password: --redacted--", "AuthToken: --redacted--", "owner: pvc-1", "secret: --redacted--"},
+			notContains: []string{"pass-1", "token-1", "secret-1"},
+		},
+		"debug snapshot - +ve test case - truncated bytes": {
+			values: map[string]interface{}{
+				"JsonResult": []byte(strings.Repeat("a", 600)),
+			},
+			contains:    []string{strings.Repeat("a", 512) + "...(truncated"},
+			notContains: []string{strings.Repeat("a", 513)},
+		},
+		"debug snapshot - +ve test case - top two levels": {
+			values: map[string]interface{}{
+				"TaskResult": map[string]interface{}{
+					"t1": map[string]interface{}{"objectName": "obj-1"},
+					"t2": []interface{}{"a", "b"},
+				},
+			},
+			contains:    []string{"t1: <map of 1 keys>", "t2: <list of 2 items>"},
+			notContains: []string{"obj-1"},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := DebugSnapshot(mock.values)
+			for _, c := range mock.contains {
+				if !strings.Contains(actual, c) {
+					t.Fatalf("failed to test debug snapshot: expected '%s': actual '%s'", c, actual)
+				}
+			}
+			for _, c := range mock.notContains {
+				if strings.Contains(actual, c) {
+					t.Fatalf("failed to test debug snapshot: expected no '%s': actual '%s'", c, actual)
+				}
+			}
+			if DebugSnapshot(mock.values) != actual {
+				t.Fatalf("failed to test debug snapshot: expected deterministic snapshot")
+			}
+		})
+	}
+}
+
+func TestSetRedactPattern(t *testing.T) {
+	defer SetRedactPattern(DefaultRedactPattern)
+
+	err := SetRedactPattern("(invalid")
+	if err == nil {
+		t.Fatalf("failed to test set redact pattern: expected 'error': actual 'no error'")
+	}
+
+	err = SetRedactPattern("^owner$")
+	if err != nil {
+		t.Fatalf("failed to test set redact pattern: expected 'no error': actual '%s'", err)
+	}
+	actual := DebugSnapshot(map[string]interface{}{"owner": "pvc-1", "password": "pass-1"})
+	if !strings.Contains(actual, "owner: --redacted--") || !strings.Contains(actual, "password: pass-1") {
+		t.Fatalf("failed to test set redact pattern: expected only owner to be redacted: actual '%s'", actual)
+	}
+
+	// secret referred to by a task is redacted irrespective of the pattern
+	actual = DebugSnapshot(map[string]interface{}{"Secret": "secret-1"})
+	if !strings.Contains(actual, "Secret: --redacted--") {
+		t.Fatalf("failed to test set redact pattern: expected secret to be redacted: actual '%s'", actual)
+	}
+}
@@ -20,11 +20,15 @@ package template
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"github.com/Masterminds/sprig"
 	"github.com/ghodss/yaml"
+	"github.com/golang/glog"
 	v1alpha1 "github.com/openebs/maya/pkg/task/v1alpha1"
 	"github.com/openebs/maya/pkg/util"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"reflect"
 	"strings"
 	"text/template"
@@ -71,8 +75,13 @@ func (e *VersionMismatchError) Error() string {
 }
 
 // IsVersionMismatch flags if the error is a version mismatch error
+//
+// NOTE:
+//  The error is unwrapped to its cause before the check. This lets a version
+// mismatch error wrapped with the details of the task that resulted in this
+// error to be detected.
 func IsVersionMismatch(err error) (match bool) {
-	_, match = err.(*VersionMismatchError)
+	_, match = errors.Cause(err).(*VersionMismatchError)
 	return
 }
 
@@ -98,6 +107,54 @@ func (e *VerifyError) Error() string {
 	return e.err
 }
 
+// IsNotFound flags if the error is due to a missing object. This is true for
+// a NotFoundError as well as for a kubernetes not found error.
+//
+// NOTE:
+//  The error is unwrapped to its cause before the check
+func IsNotFound(err error) bool {
+	cause := errors.Cause(err)
+	if _, ok := cause.(*NotFoundError); ok {
+		return true
+	}
+	return k8serrors.IsNotFound(cause)
+}
+
+// Classes of the errors as returned by ErrorClass
+const (
+	// VersionMismatchErrorClass is the class of a version mismatch error
+	VersionMismatchErrorClass = "VersionMismatch"
+	// NotFoundErrorClass is the class of an error due to a missing object
+	NotFoundErrorClass = "NotFound"
+	// VerifyErrorClass is the class of an error due to a failure in
+	// verification
+	VerifyErrorClass = "Verify"
+	// UnknownErrorClass is the class of any other error
+	UnknownErrorClass = "Unknown"
+)
+
+// ErrorClass returns the class of the provided error. An empty string is
+// returned if there is no error.
+//
+// NOTE:
+//  The error is unwrapped to its cause before it is classified
+func ErrorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	if IsVersionMismatch(err) {
+		return VersionMismatchErrorClass
+	}
+	if IsNotFound(err) {
+		return NotFoundErrorClass
+	}
+	if _, ok := errors.Cause(err).(*VerifyError); ok {
+		return VerifyErrorClass
+	}
+	return UnknownErrorClass
+}
+
 // isLen returns true if the expected value matches the given object's
 // length
 //
@@ -122,7 +179,8 @@ func isLen(expected int, given interface{}) bool {
 }
 
 // jsonPath returns the value at a given jsonpath of a json doc. This resulting
-// value is returned as a string.
+// value is returned as a string. An empty string is returned if the jsonpath
+// does not match any value.
 //
 // This function is intended to be used as a go template function.
 //
@@ -135,16 +193,40 @@ func isLen(expected int, given interface{}) bool {
 //
 //  The assumptions here are:
 // - '.Values' is of type 'map[string]interface{}'
-// - '.JsonDoc' is of type '[]byte'
-func jsonPath(json []byte, path string) string {
+// - '.JsonDoc' is of type '[]byte' or 'string'; any other type is marshaled
+// to json before running the jsonpath
+func jsonPath(doc interface{}, path string) string {
+	json, err := asJSONDoc(doc)
+	if err != nil {
+		return fmt.Sprintf("jsonpath failed: path '%s': error '%+v'", path, err)
+	}
+
 	jq := NewJsonQuery("templated-jsonpath", json, path)
 	output, err := jq.Query()
 	if err != nil {
 		return fmt.Sprintf("jsonpath failed: path '%s': error '%+v'", path, err)
 	}
+
+	if len(strings.TrimSpace(output)) == 0 {
+		glog.V(4).Infof("jsonpath '%s' did not match any value", path)
+	}
 	return output
 }
 
+// asJSONDoc returns the provided document as json bytes
+func asJSONDoc(doc interface{}) ([]byte, error) {
+	switch d := doc.(type) {
+	case []byte:
+		return d, nil
+	case string:
+		return []byte(d), nil
+	case nil:
+		return []byte("{}"), nil
+	default:
+		return json.Marshal(d)
+	}
+}
+
 // noop as its name suggests does nothing
 //
 // NOTE:
@@ -738,6 +820,37 @@ func ToYaml(v interface{}) (yamlstr string) {
 	return
 }
 
+// ToJSON takes an interface, marshals it to json, and returns a string. It will
+// always return a string, even on marshal error (empty string).
+//
+// This is designed to be called from a template.
+func ToJSON(v interface{}) (jsonstr string) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		// error is handled
+		return
+	}
+
+	jsonstr = string(data)
+	return
+}
+
+// ToJSONIndent takes an interface, marshals it to json with each json element
+// indented by the provided indent, and returns a string. It will always return
+// a string, even on marshal error (empty string).
+//
+// This is designed to be called from a template.
+func ToJSONIndent(v interface{}, indent string) (jsonstr string) {
+	data, err := json.MarshalIndent(v, "", indent)
+	if err != nil {
+		// error is handled
+		return
+	}
+
+	jsonstr = string(data)
+	return
+}
+
 // fromYaml converts a YAML document into a map[string]interface{}.
 //
 // This is not a general-purpose YAML parser, and will not parse all valid
@@ -756,6 +869,20 @@ func fromYaml(str string) map[string]interface{} {
 	return m
 }
 
+// fromJSON converts a JSON document into a map[string]interface{}.
+//
+// Like fromYaml, this tolerates errors since it is intended to be used within
+// templates. It will insert the returned error message string into
+// m["Error"] in the returned map.
+func fromJSON(str string) map[string]interface{} {
+	m := map[string]interface{}{}
+
+	if err := json.Unmarshal([]byte(str), &m); err != nil {
+		m["Error"] = err.Error()
+	}
+	return m
+}
+
 // runtaskFuncs returns the set of runtask based template functions
 func runtaskFuncs() (f template.FuncMap) {
 	return template.FuncMap{
@@ -763,6 +890,7 @@ func runtaskFuncs() (f template.FuncMap) {
 		"pickSuffix":         pickSuffix,
 		"pickPrefix":         pickPrefix,
 		"toYaml":             ToYaml,
+		"toJSONIndent":       ToJSONIndent,
 		"fromYaml":           fromYaml,
 		"jsonpath":           jsonPath,
 		"saveAs":             saveAs,
@@ -774,17 +902,23 @@ func runtaskFuncs() (f template.FuncMap) {
 		"notFoundErr":        notFoundErr,
 		"verifyErr":          verifyErr,
 		"versionMismatchErr": versionMismatchErr,
+		"versionInRange":     versionInRange,
 		"isLen":              isLen,
 		"nestedKeyMap":       nestedKeyMap,
 		"keyMap":             keyMap,
 		"splitKeyMap":        splitKeyMap,
 		"splitListTrim":      splitListTrim,
+		"nestedString":       nestedString,
+		"nestedValue":        nestedValue,
 	}
 }
 
 // allCustomFuncs returns the set of template functions supported in this library
 func allCustomFuncs() template.FuncMap {
 	f := sprig.TxtFuncMap()
+	for k, v := range registeredFuncs() {
+		f[k] = v
+	}
 	rt := runtaskFuncs()
 	for k, v := range rt {
 		f[k] = v
@@ -796,13 +930,34 @@ func allCustomFuncs() template.FuncMap {
 	return f
 }
 
+// withFuncs returns the set of template functions supported in this library
+// along with the provided functions
+func withFuncs(fns FuncMap) template.FuncMap {
+	f := allCustomFuncs()
+	for k, v := range fns {
+		f[k] = v
+	}
+	return f
+}
+
 // AsTemplatedBytes returns a byte slice
 // based on the provided yaml & values
 func AsTemplatedBytes(context string, yml string, values map[string]interface{}) ([]byte, error) {
+	return AsTemplatedBytesWithFuncs(context, yml, values, nil)
+}
+
+// AsTemplatedBytesWithFuncs returns a byte slice based on the provided yaml
+// & values. The provided functions can be invoked from the yaml in addition
+// to the functions supported by this library.
+//
+// NOTE:
+//  The provided functions are expected to be verified via
+// ValidateTemplateFuncs
+func AsTemplatedBytesWithFuncs(context string, yml string, values map[string]interface{}, fns FuncMap) ([]byte, error) {
 	tpl := template.New(context + "YamlTpl")
 
 	// Any maya yaml exposes below templating functions
-	tpl.Funcs(allCustomFuncs())
+	tpl.Funcs(withFuncs(fns))
 
 	tpl, err := tpl.Parse(yml)
 	if err != nil {
@@ -823,6 +978,23 @@ func AsTemplatedBytes(context string, yml string, values map[string]interface{})
 	return buf.Bytes(), nil
 }
 
+// ValidateSyntax verifies if the provided yaml is a valid template. The
+// template is parsed but is not executed.
+func ValidateSyntax(context string, yml string) error {
+	return ValidateSyntaxWithFuncs(context, yml, nil)
+}
+
+// ValidateSyntaxWithFuncs verifies if the provided yaml is a valid template
+// that can invoke the provided functions in addition to the functions
+// supported by this library
+func ValidateSyntaxWithFuncs(context string, yml string, fns FuncMap) error {
+	tpl := template.New(context + "YamlTpl")
+	tpl.Funcs(withFuncs(fns))
+
+	_, err := tpl.Parse(yml)
+	return err
+}
+
 // AsMapOfObjects returns a map of objects based on the provided yaml & values
 func AsMapOfObjects(yml string, values map[string]interface{}) (map[string]interface{}, error) {
 	// templated & then unmarshall-ed version of this yaml
@@ -874,3 +1046,35 @@ func splitListTrim(sep, orig string) []string {
 	processedStr := strings.TrimRight(strings.TrimLeft(orig, sep), sep)
 	return strings.Split(processedStr, sep)
 }
+
+// nestedString returns the string value at the provided path of fields in the
+// given object. An empty string is returned if any of the fields is missing or
+// if the value is not a string.
+//
+// NOTE:
+//  This is intended to be used as a template function. Unlike index this does
+// not fail if a field is missing e.g. if the task that would have set the
+// field was skipped.
+//
+// Example:
+//  {{- nestedString .TaskResult "mytask" "objectName" -}}
+func nestedString(obj interface{}, fields ...string) string {
+	m, _ := obj.(map[string]interface{})
+	return util.GetNestedString(m, fields...)
+}
+
+// nestedValue returns the value at the provided path of fields in the given
+// object. Nil is returned if any of the fields is missing.
+//
+// NOTE:
+//  This is intended to be used as a template function
+//
+// Example:
+//  {{- $labels := nestedValue .Volume "labels" -}}
+func nestedValue(obj interface{}, fields ...string) interface{} {
+	m, _ := obj.(map[string]interface{})
+	if m == nil {
+		return nil
+	}
+	return util.GetNestedField(m, fields...)
+}
@@ -35,8 +35,13 @@ import (
 	"fmt"
 	"github.com/ghodss/yaml"
 	"reflect"
+	"strings"
 	"testing"
 	"text/template"
+
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestAddTo(t *testing.T) {
@@ -193,6 +198,260 @@ storage:
 	}
 }
 
+func TestToJSON(t *testing.T) {
+	tests := map[string]struct {
+		data     interface{}
+		indent   string
+		expected string
+	}{
+		"101": {
+			data:     map[string]string{"co": "k8s", "storage": "openebs"},
+			expected: `{"co":"k8s","storage":"openebs"}`,
+		},
+		"102": {
+			data:     map[string]interface{}{"storage": map[string]string{"gen1": "jiva"}},
+			indent:   "  ",
+			expected: "{\n  \"storage\": {\n    \"gen1\": \"jiva\"\n  }\n}",
+		},
+		"103": {
+			data:     nil,
+			expected: "null",
+		},
+		// marshal error results in empty string
+		"104": {
+			data:     map[string]interface{}{"ch": make(chan int)},
+			expected: "",
+		},
+		"105": {
+			data:     map[string]interface{}{"ch": make(chan int)},
+			indent:   "  ",
+			expected: "",
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := ToJSON(mock.data)
+			if len(mock.indent) != 0 {
+				actual = ToJSONIndent(mock.data, mock.indent)
+			}
+			if actual != mock.expected {
+				t.Fatalf("toJSON test failed: expected '%s' actual '%s'", mock.expected, actual)
+			}
+		})
+	}
+}
+
+func TestToJSONTemplateFuncs(t *testing.T) {
+	values := map[string]interface{}{"Volume": map[string]interface{}{"owner": "pvc-1"}}
+
+	actual, err := AsTemplatedBytes("ToJSON", `{{ .Volume | toJSON }}|{{ toJSONIndent .Volume " " }}`, values)
+	if err != nil {
+		t.Fatalf("toJSON template funcs test failed: expected no error actual '%s'", err)
+	}
+
+	expected := "{\"owner\":\"pvc-1\"}|{\n \"owner\": \"pvc-1\"\n}"
+	if string(actual) != expected {
+		t.Fatalf("toJSON template funcs test failed: expected '%s' actual '%s'", expected, actual)
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	notFound := k8serrors.NewNotFound(schema.GroupResource{Resource: "services"}, "svc-1")
+	tests := map[string]struct {
+		err        error
+		isNotFound bool
+		class      string
+	}{
+		"101": {err: nil, isNotFound: false, class: ""},
+		"102": {err: &NotFoundError{err: "not found"}, isNotFound: true, class: NotFoundErrorClass},
+		"103": {err: notFound, isNotFound: true, class: NotFoundErrorClass},
+		// wrapped errors are classified by their cause
+		"104": {err: errors.Wrap(notFound, "failed to delete"), isNotFound: true, class: NotFoundErrorClass},
+		"105": {err: errors.Wrap(&VersionMismatchError{err: "mismatch"}, "failed"), isNotFound: false, class: VersionMismatchErrorClass},
+		"106": {err: &VerifyError{err: "verify"}, isNotFound: false, class: VerifyErrorClass},
+		"107": {err: fmt.Errorf("some error"), isNotFound: false, class: UnknownErrorClass},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			if IsNotFound(mock.err) != mock.isNotFound {
+				t.Fatalf("error class test failed: expected not found '%t' actual '%t'", mock.isNotFound, !mock.isNotFound)
+			}
+			if actual := ErrorClass(mock.err); actual != mock.class {
+				t.Fatalf("error class test failed: expected '%s' actual '%s'", mock.class, actual)
+			}
+		})
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	tests := map[string]struct {
+		data     string
+		expected map[string]interface{}
+		isErr    bool
+	}{
+		"101": {
+			data:     `{"co":"k8s","storage":{"gen1":"jiva"}}`,
+			expected: map[string]interface{}{"co": "k8s", "storage": map[string]interface{}{"gen1": "jiva"}},
+		},
+		"102": {
+			data:     `{}`,
+			expected: map[string]interface{}{},
+		},
+		"103": {
+			data:  `["co","k8s"]`,
+			isErr: true,
+		},
+		"104": {
+			data:  `co: k8s`,
+			isErr: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := fromJSON(mock.data)
+
+			if mock.isErr {
+				if _, found := actual["Error"]; !found {
+					t.Fatalf("fromJSON test failed: expected error actual '%#v'", actual)
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(mock.expected, actual) {
+				t.Fatalf("fromJSON test failed: expected '%#v' actual '%#v'", mock.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFromJSONTemplateFunc(t *testing.T) {
+	values := map[string]interface{}{"Result": `{"metadata":{"name":"pvc-1"}}`}
+
+	actual, err := AsTemplatedBytes("FromJSON", `{{ $r := fromJSON .Result }}{{ $r.metadata.name }}`, values)
+	if err != nil {
+		t.Fatalf("fromJSON template func test failed: expected no error actual '%s'", err)
+	}
+	if string(actual) != "pvc-1" {
+		t.Fatalf("fromJSON template func test failed: expected 'pvc-1' actual '%s'", actual)
+	}
+}
+
+func TestNestedFuncs(t *testing.T) {
+	values := map[string]interface{}{
+		"TaskResult": map[string]interface{}{
+			"mytask": map[string]interface{}{
+				"objectName": "pvc-1",
+				"labels":     map[string]interface{}{"app": "jiva"},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		template string
+		expected string
+	}{
+		"101": {
+			template: `{{ nestedString .TaskResult "mytask" "objectName" }}`,
+			expected: "pvc-1",
+		},
+		// missing task i.e. a skipped task
+		"102": {
+			template: `{{ nestedString .TaskResult "skippedtask" "objectName" }}`,
+			expected: "",
+		},
+		// missing top level value
+		"103": {
+			template: `{{ nestedString .Missing "mytask" "objectName" }}`,
+			expected: "",
+		},
+		// not a string
+		"104": {
+			template: `{{ nestedString .TaskResult "mytask" "labels" }}`,
+			expected: "",
+		},
+		"105": {
+			template: `{{ $l := nestedValue .TaskResult "mytask" "labels" }}{{ $l.app }}`,
+			expected: "jiva",
+		},
+		"106": {
+			template: `{{ nestedValue .TaskResult "skippedtask" "labels" | empty }}`,
+			expected: "true",
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := AsTemplatedBytes("Nested", mock.template, values)
+			if err != nil {
+				t.Fatalf("nested funcs test failed: expected no error actual '%s'", err)
+			}
+			if string(actual) != mock.expected {
+				t.Fatalf("nested funcs test failed: expected '%s' actual '%s'", mock.expected, actual)
+			}
+		})
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	tests := map[string]struct {
+		doc      interface{}
+		path     string
+		expected string
+		isErr    bool
+	}{
+		"101": {
+			doc:      []byte(`{"status":{"phase":"Bound"}}`),
+			path:     "{.status.phase}",
+			expected: "Bound",
+		},
+		"102": {
+			doc:      `{"status":{"phase":"Bound"}}`,
+			path:     "{.status.phase}",
+			expected: "Bound",
+		},
+		// non json docs are marshaled to json
+		"103": {
+			doc:      map[string]interface{}{"status": map[string]string{"phase": "Pending"}},
+			path:     "{.status.phase}",
+			expected: "Pending",
+		},
+		// no match
+		"104": {
+			doc:      []byte(`{"status":{}}`),
+			path:     "{.status.phase}",
+			expected: "",
+		},
+		"105": {
+			doc:      nil,
+			path:     "{.status.phase}",
+			expected: "",
+		},
+		"106": {
+			doc:   []byte(`{"status":{}}`),
+			path:  "{.status.phase",
+			isErr: true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := jsonPath(mock.doc, mock.path)
+			if mock.isErr {
+				if !strings.HasPrefix(actual, "jsonpath failed") {
+					t.Fatalf("jsonpath test failed: expected error actual '%s'", actual)
+				}
+				return
+			}
+			if actual != mock.expected {
+				t.Fatalf("jsonpath test failed: expected '%s' actual '%s'", mock.expected, actual)
+			}
+		})
+	}
+}
+
 func TestNestedKeyMap(t *testing.T) {
 	tests := map[string]struct {
 		delimiters  string
@@ -0,0 +1,122 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	apis "github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// TemplateValuesBuilder builds the template values that are provided to a
+// task group runner. Values are set against their canonical top level
+// properties e.g. .Volume.owner
+type TemplateValuesBuilder struct {
+	namespace  string
+	volumeName string
+	capacity   *resource.Quantity
+	labels     map[string]string
+}
+
+// NewTemplateValuesBuilder returns a new instance of TemplateValuesBuilder
+func NewTemplateValuesBuilder() *TemplateValuesBuilder {
+	return &TemplateValuesBuilder{}
+}
+
+// WithNamespace sets the namespace where the volume's tasks are run
+//
+// NOTE:
+//  This is set at .Volume.runNamespace
+func (b *TemplateValuesBuilder) WithNamespace(ns string) *TemplateValuesBuilder {
+	b.namespace = strings.TrimSpace(ns)
+	return b
+}
+
+// WithVolumeName sets the name of the volume
+//
+// NOTE:
+//  This is set at .Volume.owner
+func (b *TemplateValuesBuilder) WithVolumeName(name string) *TemplateValuesBuilder {
+	b.volumeName = strings.TrimSpace(name)
+	return b
+}
+
+// WithCapacity sets the capacity of the volume
+//
+// NOTE:
+//  This is set at .Volume.capacity
+func (b *TemplateValuesBuilder) WithCapacity(q resource.Quantity) *TemplateValuesBuilder {
+	b.capacity = &q
+	return b
+}
+
+// WithLabels sets the labels of the volume. Labels are merged with the ones
+// set previously.
+//
+// NOTE:
+//  Each label is set at .Volume.<label key>. Labels do not override the
+// canonical volume properties e.g. owner
+func (b *TemplateValuesBuilder) WithLabels(labels map[string]string) *TemplateValuesBuilder {
+	if b.labels == nil {
+		b.labels = map[string]string{}
+	}
+	for k, v := range labels {
+		b.labels[k] = v
+	}
+	return b
+}
+
+// validate verifies if the required values are set
+func (b *TemplateValuesBuilder) validate() error {
+	var missing []string
+	if len(b.volumeName) == 0 {
+		missing = append(missing, "volume name")
+	}
+	if len(b.namespace) == 0 {
+		missing = append(missing, "namespace")
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("failed to build template values: missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Build returns the template values after validating the required values
+func (b *TemplateValuesBuilder) Build() (map[string]interface{}, error) {
+	err := b.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	volume := map[string]interface{}{}
+	for k, v := range b.labels {
+		volume[k] = v
+	}
+	volume[string(apis.OwnerVTP)] = b.volumeName
+	volume[string(apis.RunNamespaceVTP)] = b.namespace
+	if b.capacity != nil {
+		volume[string(apis.CapacityVTP)] = b.capacity.String()
+	}
+
+	return map[string]interface{}{
+		string(apis.VolumeTLP):     volume,
+		string(apis.ListItemsTLP):  map[string]interface{}{},
+		string(apis.TaskResultTLP): map[string]interface{}{},
+	}, nil
+}
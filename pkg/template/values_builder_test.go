@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"testing"
+
+	"github.com/openebs/maya/pkg/util"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestTemplateValuesBuilder(t *testing.T) {
+	tests := map[string]struct {
+		builder          *TemplateValuesBuilder
+		expectedOwner    string
+		expectedCapacity string
+		expectedLabel    string
+		isErr            bool
+	}{
+		"template values builder - +ve test case - all values": {
+			builder: NewTemplateValuesBuilder().
+				WithNamespace("openebs").
+				WithVolumeName("pvc-1").
+				WithCapacity(resource.MustParse("5G")).
+				WithLabels(map[string]string{"owner": "someone", "openebs.io/cas-type": "jiva"}),
+			expectedOwner:    "pvc-1",
+			expectedCapacity: "5G",
+			expectedLabel:    "jiva",
+		},
+		"template values builder - -ve test case - missing volume name": {
+			builder: NewTemplateValuesBuilder().WithNamespace("openebs"),
+			isErr:   true,
+		},
+		"template values builder - -ve test case - missing namespace": {
+			builder: NewTemplateValuesBuilder().WithVolumeName("pvc-1"),
+			isErr:   true,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			values, err := mock.builder.Build()
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("failed to test template values builder: expected 'error': actual 'no error'")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to test template values builder: expected 'no error': actual '%s'", err)
+			}
+
+			if util.GetNestedString(values, "Volume", "owner") != mock.expectedOwner {
+				t.Fatalf("failed to test template values builder: expected owner '%s': actual '%s'", mock.expectedOwner, util.GetNestedString(values, "Volume", "owner"))
+			}
+			if util.GetNestedString(values, "Volume", "capacity") != mock.expectedCapacity {
+				t.Fatalf("failed to test template values builder: expected capacity '%s': actual '%s'", mock.expectedCapacity, util.GetNestedString(values, "Volume", "capacity"))
+			}
+			if util.GetNestedString(values, "Volume", "openebs.io/cas-type") != mock.expectedLabel {
+				t.Fatalf("failed to test template values builder: expected label '%s': actual '%s'", mock.expectedLabel, util.GetNestedString(values, "Volume", "openebs.io/cas-type"))
+			}
+			if values["TaskResult"] == nil || values["ListItems"] == nil {
+				t.Fatalf("failed to test template values builder: expected task result & list items to be initialized")
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+// IsVersionInRange flags if the provided version lies within the provided
+// minimum & maximum versions. Both the bounds are inclusive.
+//
+// NOTE:
+//  An empty bound implies there is no restriction w.r.t that bound
+func IsVersionInRange(version, minVersion, maxVersion string) (bool, error) {
+	if len(strings.TrimSpace(minVersion)) == 0 && len(strings.TrimSpace(maxVersion)) == 0 {
+		// any version is supported
+		return true, nil
+	}
+
+	v, err := semver.NewVersion(strings.TrimSpace(version))
+	if err != nil {
+		return false, fmt.Errorf("failed to verify version range: invalid version '%s': %s", version, err)
+	}
+
+	if len(strings.TrimSpace(minVersion)) != 0 {
+		min, err := semver.NewVersion(strings.TrimSpace(minVersion))
+		if err != nil {
+			return false, fmt.Errorf("failed to verify version range: invalid min version '%s': %s", minVersion, err)
+		}
+		if v.LessThan(min) {
+			return false, nil
+		}
+	}
+
+	if len(strings.TrimSpace(maxVersion)) != 0 {
+		max, err := semver.NewVersion(strings.TrimSpace(maxVersion))
+		if err != nil {
+			return false, fmt.Errorf("failed to verify version range: invalid max version '%s': %s", maxVersion, err)
+		}
+		if v.GreaterThan(max) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// VerifyVersionRange returns VersionMismatchError if the provided version
+// does not lie within the provided minimum & maximum versions
+func VerifyVersionRange(version, minVersion, maxVersion string) error {
+	ok, err := IsVersionInRange(version, minVersion, maxVersion)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return &VersionMismatchError{
+			err: fmt.Sprintf("version '%s' is not supported: supported versions are from '%s' to '%s'", version, minVersion, maxVersion),
+		}
+	}
+	return nil
+}
+
+// versionInRange flags if the provided version lies within the provided
+// minimum & maximum versions. An invalid version is considered to be out of
+// range.
+//
+// NOTE:
+//  This is intended to be used as a go template function
+//
+// Example:
+// {{- .Volume.version | versionInRange "0.7.0" "0.8.0" | not | versionMismatchErr "unsupported volume version" | saveIf "vol.versionMismatchErr" .TaskResult | noop -}}
+func versionInRange(minVersion, maxVersion, version string) bool {
+	ok, _ := IsVersionInRange(version, minVersion, maxVersion)
+	return ok
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"testing"
+)
+
+func TestIsVersionInRange(t *testing.T) {
+	tests := map[string]struct {
+		version    string
+		minVersion string
+		maxVersion string
+		expected   bool
+		isErr      bool
+	}{
+		"version in range - +ve test case - within range":  {version: "1.5.0", minVersion: "1.2.0", maxVersion: "2.0.0", expected: true},
+		"version in range - +ve test case - equals min":    {version: "1.2.0", minVersion: "1.2.0", maxVersion: "2.0.0", expected: true},
+		"version in range - +ve test case - equals max":    {version: "2.0.0", minVersion: "1.2.0", maxVersion: "2.0.0", expected: true},
+		"version in range - +ve test case - no bounds":     {version: "dev", expected: true},
+		"version in range - +ve test case - no max":        {version: "9.0.0", minVersion: "1.2.0", expected: true},
+		"version in range - -ve test case - below min":     {version: "1.1.9", minVersion: "1.2.0", maxVersion: "2.0.0", expected: false},
+		"version in range - -ve test case - above max":     {version: "2.0.1", minVersion: "1.2.0", maxVersion: "2.0.0", expected: false},
+		"version in range - -ve test case - pre release":   {version: "1.2.0-RC1", minVersion: "1.2.0", expected: false},
+		"version in range - -ve test case - invalid":       {version: "dev", minVersion: "1.2.0", isErr: true},
+		"version in range - -ve test case - invalid bound": {version: "1.2.0", maxVersion: "latest", isErr: true},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			ok, err := IsVersionInRange(mock.version, mock.minVersion, mock.maxVersion)
+			if mock.isErr && err == nil {
+				t.Fatalf("failed to test version in range: expected 'error': actual 'no error'")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("failed to test version in range: expected 'no error': actual '%s'", err)
+			}
+			if ok != mock.expected {
+				t.Fatalf("failed to test version in range: expected '%t': actual '%t'", mock.expected, ok)
+			}
+		})
+	}
+}
+
+func TestVerifyVersionRange(t *testing.T) {
+	err := VerifyVersionRange("2.1.0", "1.2.0", "2.0.0")
+	if !IsVersionMismatch(err) {
+		t.Fatalf("failed to test verify version range: expected 'version mismatch error': actual '%#v'", err)
+	}
+
+	err = VerifyVersionRange("1.3.0", "1.2.0", "2.0.0")
+	if err != nil {
+		t.Fatalf("failed to test verify version range: expected 'no error': actual '%s'", err)
+	}
+}
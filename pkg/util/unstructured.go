@@ -124,6 +124,45 @@ func GetNestedMap(obj map[string]interface{}, fields ...string) map[string]strin
 	return nil
 }
 
+// GetNestedList returns the nested list from the provided map. The returned
+// flag is false if the path does not exist or the nested field is not a
+// list.
+//
+// NOTE:
+//  GetNestedSlice on the other hand returns only the string items of the
+// nested list
+func GetNestedList(obj map[string]interface{}, fields ...string) ([]interface{}, bool) {
+	list, ok := GetNestedField(obj, fields...).([]interface{})
+	return list, ok
+}
+
+// GetNestedFloat64 returns the nested number from the provided map as a
+// float64. The returned flag is false if the path does not exist or the
+// nested field is not a number.
+func GetNestedFloat64(obj map[string]interface{}, fields ...string) (float64, bool) {
+	switch n := GetNestedField(obj, fields...).(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// GetNestedBool returns the nested boolean from the provided map. The
+// returned flag is false if the path does not exist or the nested field is
+// not a boolean.
+func GetNestedBool(obj map[string]interface{}, fields ...string) (bool, bool) {
+	b, ok := GetNestedField(obj, fields...).(bool)
+	return b, ok
+}
+
 func SetNestedField(obj map[string]interface{}, value interface{}, fields ...string) {
 	if len(fields) == 0 || obj == nil {
 		// changes can not be done to the obj
@@ -214,3 +253,68 @@ func GetMapOfStrings(obj map[string]interface{}, field string) map[string]string
 	}
 	return nil
 }
+
+// DeepCopyMap returns a deep copy of the provided map. Nested maps, lists &
+// byte slices e.g. a json document are copied as well so that the copy can
+// be mutated without affecting the original & vice versa. Rest of the values
+// e.g. strings, numbers & pointers are copied as is.
+//
+// NOTE:
+//  This is meant to copy template values
+func DeepCopyMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+
+	copied := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		copied[k] = deepCopyValue(v)
+	}
+	return copied
+}
+
+// deepCopyValue returns a deep copy of the provided value
+func deepCopyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return DeepCopyMap(v)
+	case map[string]string:
+		if v == nil {
+			return v
+		}
+		copied := make(map[string]string, len(v))
+		for key, val := range v {
+			copied[key] = val
+		}
+		return copied
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		copied := make([]interface{}, len(v))
+		for idx, val := range v {
+			copied[idx] = deepCopyValue(val)
+		}
+		return copied
+	case []map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		copied := make([]map[string]interface{}, len(v))
+		for idx, val := range v {
+			copied[idx] = DeepCopyMap(val)
+		}
+		return copied
+	case []string:
+		if v == nil {
+			return v
+		}
+		return append([]string{}, v...)
+	case []byte:
+		if v == nil {
+			return v
+		}
+		return append([]byte{}, v...)
+	}
+	return value
+}
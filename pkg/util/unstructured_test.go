@@ -227,3 +227,166 @@ func TestSetNestedField(t *testing.T) {
 		})
 	}
 }
+
+func TestGetNestedTypedFields(t *testing.T) {
+	obj := map[string]interface{}{
+		"TaskResult": map[string]interface{}{
+			"t1": map[string]interface{}{
+				"replicas": []interface{}{"r1", "r2"},
+				"capacity": float64(5368709120),
+				"count":    int64(3),
+				"ready":    true,
+				"name":     "t1-obj",
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		get           func() (interface{}, bool)
+		expectedValue interface{}
+		expectedOk    bool
+	}{
+		"get nested list - +ve test case - list exists": {
+			get:           func() (interface{}, bool) { return GetNestedList(obj, "TaskResult", "t1", "replicas") },
+			expectedValue: []interface{}{"r1", "r2"},
+			expectedOk:    true,
+		},
+		"get nested list - -ve test case - not a list": {
+			get:           func() (interface{}, bool) { return GetNestedList(obj, "TaskResult", "t1", "name") },
+			expectedValue: []interface{}(nil),
+		},
+		"get nested list - -ve test case - missing path": {
+			get:           func() (interface{}, bool) { return GetNestedList(obj, "TaskResult", "t2", "replicas") },
+			expectedValue: []interface{}(nil),
+		},
+		"get nested float64 - +ve test case - float exists": {
+			get:           func() (interface{}, bool) { return GetNestedFloat64(obj, "TaskResult", "t1", "capacity") },
+			expectedValue: float64(5368709120),
+			expectedOk:    true,
+		},
+		"get nested float64 - +ve test case - int exists": {
+			get:           func() (interface{}, bool) { return GetNestedFloat64(obj, "TaskResult", "t1", "count") },
+			expectedValue: float64(3),
+			expectedOk:    true,
+		},
+		"get nested float64 - -ve test case - not a number": {
+			get:           func() (interface{}, bool) { return GetNestedFloat64(obj, "TaskResult", "t1", "name") },
+			expectedValue: float64(0),
+		},
+		"get nested float64 - -ve test case - path through a non map": {
+			get:           func() (interface{}, bool) { return GetNestedFloat64(obj, "TaskResult", "t1", "name", "capacity") },
+			expectedValue: float64(0),
+		},
+		"get nested bool - +ve test case - bool exists": {
+			get:           func() (interface{}, bool) { return GetNestedBool(obj, "TaskResult", "t1", "ready") },
+			expectedValue: true,
+			expectedOk:    true,
+		},
+		"get nested bool - -ve test case - missing path": {
+			get:           func() (interface{}, bool) { return GetNestedBool(nil, "TaskResult", "t1", "ready") },
+			expectedValue: false,
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			value, ok := mock.get()
+			if ok != mock.expectedOk {
+				t.Fatalf("failed to test %s: expected ok '%t': actual '%t'", name, mock.expectedOk, ok)
+			}
+			if !reflect.DeepEqual(value, mock.expectedValue) {
+				t.Fatalf("failed to test %s: expected '%v': actual '%v'", name, mock.expectedValue, value)
+			}
+		})
+	}
+}
+
+func TestDeepCopyMap(t *testing.T) {
+	tests := map[string]struct {
+		values map[string]interface{}
+		mutate func(copied map[string]interface{})
+		verify func(values map[string]interface{}) bool
+	}{
+		"deep copy - +ve test case - top level value": {
+			values: map[string]interface{}{"name": "openebs"},
+			mutate: func(copied map[string]interface{}) { copied["name"] = "maya" },
+			verify: func(values map[string]interface{}) bool { return values["name"] == "openebs" },
+		},
+		"deep copy - +ve test case - nested map": {
+			values: map[string]interface{}{
+				"TaskResult": map[string]interface{}{"t1": map[string]interface{}{"objectName": "pvc-1"}},
+			},
+			mutate: func(copied map[string]interface{}) {
+				SetNestedField(copied, "pvc-2", "TaskResult", "t1", "objectName")
+				SetNestedField(copied, "pvc-3", "TaskResult", "t2", "objectName")
+			},
+			verify: func(values map[string]interface{}) bool {
+				return GetNestedString(values, "TaskResult", "t1", "objectName") == "pvc-1" &&
+					GetNestedField(values, "TaskResult", "t2") == nil
+			},
+		},
+		"deep copy - +ve test case - list of maps": {
+			values: map[string]interface{}{"list": []interface{}{map[string]interface{}{"k": "v"}, "item"}},
+			mutate: func(copied map[string]interface{}) {
+				copied["list"].([]interface{})[0].(map[string]interface{})["k"] = "mutated"
+				copied["list"].([]interface{})[1] = "mutated"
+			},
+			verify: func(values map[string]interface{}) bool {
+				list := values["list"].([]interface{})
+				return list[0].(map[string]interface{})["k"] == "v" && list[1] == "item"
+			},
+		},
+		"deep copy - +ve test case - typed list of maps": {
+			values: map[string]interface{}{"items": []map[string]interface{}{{"k": "v"}}},
+			mutate: func(copied map[string]interface{}) {
+				copied["items"].([]map[string]interface{})[0]["k"] = "mutated"
+			},
+			verify: func(values map[string]interface{}) bool {
+				return values["items"].([]map[string]interface{})[0]["k"] == "v"
+			},
+		},
+		"deep copy - +ve test case - json result": {
+			values: map[string]interface{}{"JsonResult": []byte(`{"kind": "Service"}`)},
+			mutate: func(copied map[string]interface{}) { copied["JsonResult"].([]byte)[0] = '[' },
+			verify: func(values map[string]interface{}) bool { return values["JsonResult"].([]byte)[0] == '{' },
+		},
+		"deep copy - +ve test case - map & list of strings": {
+			values: map[string]interface{}{
+				"labels": map[string]string{"app": "jiva"},
+				"names":  []string{"pvc-1"},
+			},
+			mutate: func(copied map[string]interface{}) {
+				copied["labels"].(map[string]string)["app"] = "mutated"
+				copied["names"].([]string)[0] = "mutated"
+			},
+			verify: func(values map[string]interface{}) bool {
+				return values["labels"].(map[string]string)["app"] == "jiva" && values["names"].([]string)[0] == "pvc-1"
+			},
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			copied := DeepCopyMap(mock.values)
+			if !reflect.DeepEqual(copied, mock.values) {
+				t.Fatalf("failed to test deep copy: expected '%v': actual '%v'", mock.values, copied)
+			}
+			mock.mutate(copied)
+			if !mock.verify(mock.values) {
+				t.Fatalf("failed to test deep copy: expected original values to be intact: actual '%v'", mock.values)
+			}
+		})
+	}
+
+	if DeepCopyMap(nil) != nil {
+		t.Fatalf("failed to test deep copy: expected nil copy of nil map")
+	}
+
+	// copy is intact if the original is mutated
+	orig := map[string]interface{}{"nested": map[string]interface{}{"k": "v"}}
+	copied := DeepCopyMap(orig)
+	SetNestedField(orig, "mutated", "nested", "k")
+	if GetNestedString(copied, "nested", "k") != "v" {
+		t.Fatalf("failed to test deep copy: expected copy to be intact: actual '%v'", copied)
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package volume
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/openebs/maya/types/v1"
@@ -104,7 +105,7 @@ func (v *Operation) getCloneLabels() (map[string]interface{}, error) {
 }
 
 // Create provisions an OpenEBS volume
-func (v *Operation) Create() (*v1alpha1.CASVolume, error) {
+func (v *Operation) Create(ctx context.Context) (*v1alpha1.CASVolume, error) {
 	if v.k8sClient == nil {
 		return nil, fmt.Errorf("unable to create volume: nil k8s client")
 	}
@@ -188,7 +189,7 @@ func (v *Operation) Create() (*v1alpha1.CASVolume, error) {
 	}
 
 	// create the volume
-	data, err := cc.Create()
+	data, err := cc.Create(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -204,7 +205,7 @@ func (v *Operation) Create() (*v1alpha1.CASVolume, error) {
 }
 
 // Delete removes a CASVolume
-func (v *Operation) Delete() (*v1alpha1.CASVolume, error) {
+func (v *Operation) Delete(ctx context.Context) (*v1alpha1.CASVolume, error) {
 	if len(v.volume.Name) == 0 {
 		return nil, fmt.Errorf("unable to delete volume: volume name not provided")
 	}
@@ -250,7 +251,7 @@ func (v *Operation) Delete() (*v1alpha1.CASVolume, error) {
 	}
 
 	// delete the cas volume
-	data, err := engine.Delete()
+	data, err := engine.Delete(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -266,7 +267,7 @@ func (v *Operation) Delete() (*v1alpha1.CASVolume, error) {
 }
 
 // Get the openebs volume details
-func (v *Operation) Read() (*v1alpha1.CASVolume, error) {
+func (v *Operation) Read(ctx context.Context) (*v1alpha1.CASVolume, error) {
 	if len(v.volume.Name) == 0 {
 		return nil, fmt.Errorf("unable to read volume: volume name not provided")
 	}
@@ -320,7 +321,7 @@ func (v *Operation) Read() (*v1alpha1.CASVolume, error) {
 	}
 
 	// read the volume details
-	data, err := engine.Read()
+	data, err := engine.Read(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -365,7 +366,7 @@ func NewListOperation(volumes *v1alpha1.CASVolumeList) (*ListOperation, error) {
 }
 
 // List returns a list of CASVolumeList
-func (v *ListOperation) List() (*v1alpha1.CASVolumeList, error) {
+func (v *ListOperation) List(ctx context.Context) (*v1alpha1.CASVolumeList, error) {
 	// cas template to list cas volumes
 	castNames := menv.Get(menv.CASTemplateToListVolumeENVK)
 	if len(castNames) == 0 {
@@ -395,7 +396,7 @@ func (v *ListOperation) List() (*v1alpha1.CASVolumeList, error) {
 		}
 
 		// read the volume details
-		data, err := engine.List()
+		data, err := engine.List(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -18,6 +18,7 @@ limitations under the License.
 package volume
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -139,7 +140,7 @@ func (c *casVolumeEngine) prepareFinalConfig() (final []v1alpha1.Config) {
 }
 
 // Create creates a CAS volume
-func (c *casVolumeEngine) Create() ([]byte, error) {
+func (c *casVolumeEngine) Create(ctx context.Context) ([]byte, error) {
 	// set customized CAS config as a top level property
 	err := c.casEngine.AddConfigToConfigTLP(c.prepareFinalConfig())
 	if err != nil {
@@ -147,5 +148,5 @@ func (c *casVolumeEngine) Create() ([]byte, error) {
 	}
 
 	// delegate to generic cas template engine
-	return c.casEngine.Run()
+	return c.casEngine.Run(ctx)
 }